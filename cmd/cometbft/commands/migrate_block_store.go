@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/spf13/cobra"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmtos "github.com/tendermint/tendermint/libs/os"
+	"github.com/tendermint/tendermint/libs/progressbar"
+	"github.com/tendermint/tendermint/store"
+)
+
+// MigrateBlockStoreCmd moves the stored blocks to the block store backend of
+// the config.
+var MigrateBlockStoreCmd = &cobra.Command{
+	Use:     "migrate-block-store",
+	Aliases: []string{"migrate_block_store"},
+	Short:   "Move the stored blocks to the block store backend of the config",
+	Long: `
+migrate-block-store moves the stored blocks to the block store backend set by
+block_store_backend in the [storage] section of the config:
+
+  - "segments": the blocks stored in the blockstore database are appended to the
+    segment files of the data directory.
+  - "db": the blocks stored in the segment files are moved back to the blockstore
+    database, and the segment files are removed.
+
+The node must be stopped. A node storing its new blocks in segments still reads the
+blocks stored in the database, such that the migration can be run later on; the
+contrary is not true, and the blocks must be moved back to the database before
+setting block_store_backend to "db".
+	`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		moved, err := MigrateBlockStore(config)
+		if err != nil {
+			return fmt.Errorf("failed to migrate the block store: %w", err)
+		}
+
+		fmt.Printf("Moved %d blocks to the %q block store backend\n", moved, config.Storage.BlockStoreBackend)
+		return nil
+	},
+}
+
+// MigrateBlockStore moves the stored blocks to the block store backend of
+// config, and returns the number of blocks moved.
+func MigrateBlockStore(config *cfg.Config) (uint64, error) {
+	if !cmtos.FileExists(filepath.Join(config.DBDir(), "blockstore.db")) {
+		return 0, fmt.Errorf("no blockstore found in %v", config.DBDir())
+	}
+	segmentsDir := config.BlockStoreSegmentsDir()
+	toSegments := config.Storage.BlockStoreBackend == "segments"
+	if !toSegments && !cmtos.FileExists(segmentsDir) {
+		return 0, nil
+	}
+
+	blockStoreDB, err := dbm.NewDB("blockstore", dbm.BackendType(config.DBBackend), config.DBDir())
+	if err != nil {
+		return 0, err
+	}
+	segments, err := store.OpenSegments(segmentsDir, store.DefaultMaxSegmentSize)
+	if err != nil {
+		_ = blockStoreDB.Close()
+		return 0, err
+	}
+	blockStore := store.NewBlockStore(blockStoreDB, store.WithSegments(segments))
+
+	var bar progressbar.Bar
+	bar.NewOption(blockStore.Base()-1, blockStore.Height())
+	migrate := blockStore.MigrateFromSegments
+	if toSegments {
+		migrate = blockStore.MigrateToSegments
+	}
+	moved, err := migrate(bar.Play)
+	bar.Finish()
+
+	if closeErr := blockStore.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && !toSegments {
+		err = os.RemoveAll(segmentsDir)
+	}
+	return moved, err
+}
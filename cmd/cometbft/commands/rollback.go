@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
 	"path/filepath"
 
@@ -9,11 +10,23 @@ import (
 	dbm "github.com/cometbft/cometbft-db"
 
 	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/consensus"
+	cmtbytes "github.com/tendermint/tendermint/libs/bytes"
 	"github.com/tendermint/tendermint/libs/os"
 	"github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/state/indexer"
+	blockidxkv "github.com/tendermint/tendermint/state/indexer/block/kv"
+	"github.com/tendermint/tendermint/state/txindex"
+	"github.com/tendermint/tendermint/state/txindex/kv"
 	"github.com/tendermint/tendermint/store"
 )
 
+var (
+	rollbackHeight  int64
+	rollbackIndexes bool
+	rollbackDryRun  bool
+)
+
 var RollbackStateCmd = &cobra.Command{
 	Use:   "rollback",
 	Short: "rollback CometBFT state by one height",
@@ -24,18 +37,75 @@ progress. Rollback overwrites a state at height n with the state at height n - 1
 The application should also roll back to height n - 1. No blocks are removed, so upon
 restarting CometBFT the transactions in block n will be re-executed against the
 application.
+
+With --height h, the state is rolled back to height h, and the blocks above h are
+removed from the block store, along with the messages of the heights above h from
+the consensus WAL. With --index, the txs and blocks indexed above h, and the tracks
+pods of those txs, are removed as well; only the kv indexer supports it, which is
+checked before anything is changed. Without --index, the txs and blocks indexed
+above h are kept, and the synced blocks are indexed again over them, but not their
+pods. With --dry-run, the rollback is only printed. The application should also
+roll back to height h, and the blocks above h are synced again upon restarting
+CometBFT.
 `,
+	Example: `
+	cometbft rollback
+	cometbft rollback --height 100 --dry-run
+	cometbft rollback --height 100 --index
+	`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		height, hash, err := RollbackState(config)
+		if rollbackHeight == 0 {
+			if rollbackIndexes || rollbackDryRun {
+				return errors.New("--index and --dry-run require --height")
+			}
+			height, hash, err := RollbackState(config)
+			if err != nil {
+				return fmt.Errorf("failed to rollback state: %w", err)
+			}
+
+			fmt.Printf("Rolled back state to height %d and hash %v", height, hash)
+			return nil
+		}
+
+		result, err := RollbackToHeight(config, rollbackHeight, rollbackIndexes, rollbackDryRun)
 		if err != nil {
-			return fmt.Errorf("failed to rollback state: %w", err)
+			return fmt.Errorf("failed to rollback to height %d: %w", rollbackHeight, err)
+		}
+
+		if rollbackDryRun {
+			fmt.Printf("Would roll back state from height %d to height %d and hash %v\n",
+				result.StateHeight, result.Height, result.AppHash)
+			fmt.Printf("Would remove %d blocks above height %d, and %d messages of the consensus WAL\n",
+				result.Blocks, result.Height, result.WALMessages)
+			if rollbackIndexes {
+				fmt.Printf("Would remove the txs and blocks indexed above height %d\n", result.Height)
+			} else {
+				fmt.Printf("Would index the blocks above height %d again\n", result.Height)
+			}
+			return nil
 		}
 
-		fmt.Printf("Rolled back state to height %d and hash %v", height, hash)
+		fmt.Printf("Rolled back state from height %d to height %d and hash %v\n",
+			result.StateHeight, result.Height, result.AppHash)
+		fmt.Printf("Removed %d blocks above height %d, and %d messages of the consensus WAL\n",
+			result.Blocks, result.Height, result.WALMessages)
+		if rollbackIndexes {
+			fmt.Printf("Removed %d txs and %d blocks indexed above height %d\n",
+				result.IndexedTxs, result.IndexedBlocks, result.Height)
+		}
 		return nil
 	},
 }
 
+func init() {
+	RollbackStateCmd.Flags().Int64Var(&rollbackHeight, "height", 0,
+		"the height to roll back to, removing the blocks above it (default: one height below the state, keeping the blocks)")
+	RollbackStateCmd.Flags().BoolVar(&rollbackIndexes, "index", false,
+		"with --height, also remove the txs and blocks indexed above the height, and the tracks pods")
+	RollbackStateCmd.Flags().BoolVar(&rollbackDryRun, "dry-run", false,
+		"with --height, print the rollback without performing it")
+}
+
 // RollbackState takes the state at the current height n and overwrites it with the state
 // at height n - 1. Note state here refers to CometBFT state not application state.
 // Returns the latest state height and app hash alongside an error if there was one.
@@ -54,6 +124,127 @@ func RollbackState(config *cfg.Config) (int64, []byte, error) {
 	return state.Rollback(blockStore, stateStore)
 }
 
+// RollbackResult is the outcome of a rollback to a height, or the planned
+// one with a dry run.
+type RollbackResult struct {
+	// The height and app hash of the state, after the rollback.
+	Height  int64
+	AppHash cmtbytes.HexBytes
+	// The height of the state before the rollback.
+	StateHeight int64
+
+	// The number of blocks and consensus WAL messages removed.
+	Blocks      uint64
+	WALMessages int
+	// The number of txs and blocks removed from the indexes, unknown with a
+	// dry run.
+	IndexedTxs    uint64
+	IndexedBlocks uint64
+}
+
+// RollbackToHeight rolls back the state to height, and removes the blocks and
+// the consensus WAL messages above it. With indexes, the txs and blocks indexed
+// above height, and the tracks pods of those txs, are removed as well,
+// otherwise the blocks above height are only to be indexed again. With dryRun,
+// nothing is changed, the result describing the planned rollback.
+func RollbackToHeight(config *cfg.Config, height int64, indexes, dryRun bool) (*RollbackResult, error) {
+	// The indexes are checked first, for the rollback not to stop halfway.
+	if indexes {
+		switch config.TxIndex.Indexer {
+		case "kv", "null", "":
+		default:
+			return nil, fmt.Errorf("the %s indexer can't be rolled back", config.TxIndex.Indexer)
+		}
+	}
+
+	blockStore, stateStore, err := loadStateAndBlockStore(config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = blockStore.Close()
+		_ = stateStore.Close()
+	}()
+
+	current, err := stateStore.Load()
+	if err != nil {
+		return nil, err
+	}
+	result := &RollbackResult{StateHeight: current.LastBlockHeight}
+	if result.Height, result.AppHash, err = state.RollbackTo(blockStore, stateStore, height, dryRun); err != nil {
+		return nil, err
+	}
+
+	if last := blockStore.Height(); last > height {
+		result.Blocks = uint64(last - height)
+	}
+	if !dryRun {
+		if result.Blocks, err = blockStore.DeleteBlocksAbove(height); err != nil {
+			return nil, err
+		}
+	}
+
+	if result.WALMessages, err = consensus.TruncateWAL(config.Consensus.WalFile(), height, dryRun); err != nil {
+		return nil, fmt.Errorf("failed to truncate the consensus WAL: %w", err)
+	}
+
+	if !dryRun {
+		if result.IndexedTxs, result.IndexedBlocks, err = rewindIndexes(config, height, indexes); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// rewindIndexes lowers the indexed height tracked by the indexer service to
+// height, for the blocks above it to be indexed again. With remove, the txs
+// and blocks indexed above height, and their pods, are removed first. It
+// returns the number of txs and blocks removed.
+func rewindIndexes(config *cfg.Config, height int64, remove bool) (uint64, uint64, error) {
+	switch config.TxIndex.Indexer {
+	case "kv", "psql", "sqlite":
+	default:
+		return 0, 0, nil
+	}
+	if !os.FileExists(filepath.Join(config.DBDir(), "tx_index.db")) {
+		return 0, 0, nil
+	}
+
+	store, err := dbm.NewDB("tx_index", dbm.BackendType(config.DBBackend), config.DBDir())
+	if err != nil {
+		return 0, 0, err
+	}
+	defer store.Close()
+	// As in createAndStartIndexerService.
+	progress := dbm.NewPrefixDB(store, []byte("indexer_progress/"+config.TxIndex.Indexer+"/"))
+	if !remove {
+		// The pods of the heights above height are kept, and those of the
+		// synced blocks not indexed, for the pods not to be indexed twice.
+		return 0, 0, txindex.RewindIndexedHeight(progress, height)
+	}
+
+	// As in loadEventSinks, for the same keys to be removed as indexed.
+	filter := indexer.NewEventFilter(config.TxIndex.IncludeEvents, config.TxIndex.ExcludeEvents)
+	var (
+		txIndexer indexer.Rewinder = kv.NewTxIndex(store, kv.WithEventFilter(filter),
+			kv.WithAddressIndex(config.TxIndex.AddressEvents))
+		blockIndexer indexer.Rewinder = blockidxkv.New(dbm.NewPrefixDB(store, []byte("block_events")),
+			blockidxkv.WithEventFilter(filter))
+	)
+	txs, err := txIndexer.Rewind(height)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to remove the indexed txs: %w", err)
+	}
+	blocks, err := blockIndexer.Rewind(height)
+	if err != nil {
+		return txs, 0, fmt.Errorf("failed to remove the indexed blocks: %w", err)
+	}
+	if err := txindex.RewindIndexedHeight(progress, height); err != nil {
+		return txs, blocks, err
+	}
+	return txs, blocks, txindex.RewindPodsHeight(progress, height)
+}
+
 func loadStateAndBlockStore(config *cfg.Config) (*store.BlockStore, state.Store, error) {
 	dbType := dbm.BackendType(config.DBBackend)
 
@@ -66,7 +257,15 @@ func loadStateAndBlockStore(config *cfg.Config) (*store.BlockStore, state.Store,
 	if err != nil {
 		return nil, nil, err
 	}
-	blockStore := store.NewBlockStore(blockStoreDB)
+	var options []store.Option
+	if config.Storage.BlockStoreBackend == "segments" {
+		segments, err := store.OpenSegments(config.BlockStoreSegmentsDir(), store.DefaultMaxSegmentSize)
+		if err != nil {
+			return nil, nil, err
+		}
+		options = append(options, store.WithSegments(segments))
+	}
+	blockStore := store.NewBlockStore(blockStoreDB, options...)
 
 	if !os.FileExists(filepath.Join(config.DBDir(), "state.db")) {
 		return nil, nil, fmt.Errorf("no statestore found in %v", config.DBDir())
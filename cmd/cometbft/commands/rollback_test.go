@@ -0,0 +1,49 @@
+package commands
+
+import (
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+)
+
+func TestRollbackToHeightIndexes(t *testing.T) {
+	config := cfg.TestConfig()
+	config.DBBackend = "goleveldb"
+	config.SetRoot(t.TempDir())
+	cfg.EnsureRoot(config.RootDir)
+	require.NoError(t, initFilesWithConfig(config))
+	saveTestChain(t, config)
+
+	store, err := dbm.NewDB("tx_index", dbm.GoLevelDBBackend, config.DBDir())
+	require.NoError(t, err)
+	progress := dbm.NewPrefixDB(store, []byte("indexer_progress/kv/"))
+	require.NoError(t, progress.Set([]byte("indexed_height"), []byte("5")))
+	require.NoError(t, store.Close())
+
+	// The indexes which can't be rewound are refused before the rollback.
+	config.TxIndex.Indexer = "psql"
+	_, err = RollbackToHeight(config, 1, true, false)
+	require.ErrorContains(t, err, "can't be rolled back")
+
+	// Without --index, the blocks above the height are indexed again, but
+	// not their pods.
+	config.TxIndex.Indexer = "kv"
+	result, err := RollbackToHeight(config, 1, false, false)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, result.Height)
+
+	store, err = dbm.NewDB("tx_index", dbm.GoLevelDBBackend, config.DBDir())
+	require.NoError(t, err)
+	defer store.Close()
+	progress = dbm.NewPrefixDB(store, []byte("indexer_progress/kv/"))
+	bz, err := progress.Get([]byte("indexed_height"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", string(bz))
+	bz, err = progress.Get([]byte("pods_height"))
+	require.NoError(t, err)
+	assert.Equal(t, "5", string(bz))
+}
@@ -0,0 +1,530 @@
+package commands
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/spf13/cobra"
+	"github.com/syndtr/goleveldb/leveldb/storage"
+
+	cfg "github.com/tendermint/tendermint/config"
+	cmtbytes "github.com/tendermint/tendermint/libs/bytes"
+	cmtos "github.com/tendermint/tendermint/libs/os"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// snapshotManifestName is the name of the manifest, the first entry of the
+	// snapshot archives.
+	snapshotManifestName = "manifest.json"
+	// snapshotDataDir is the directory of the archives the entries of the
+	// data directory are stored in.
+	snapshotDataDir = "data"
+)
+
+// SnapshotCmd defines the root command containing the subcommands creating
+// and restoring the snapshots of the data directory.
+var SnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Create or restore a snapshot of the data directory of the node",
+}
+
+var snapshotCreateCmd = &cobra.Command{
+	Use:   "create [archive]",
+	Short: "Archive the databases of the data directory, with the node stopped",
+	Long: `
+create archives the databases of the data directory - the blockstore, the state,
+the evidence and the indexes, including the tracks and pods - along with a
+manifest of the chain ID and of the heights and hashes of the stored blocks, to a
+gzipped tar archive. The archive defaults to <chain-id>-<height>.tar.gz in the
+current directory.
+
+The node must be stopped. The goleveldb databases are locked while they are
+archived, for the node not to be started meanwhile. The validator state and the
+write-ahead logs, specific to the node, are not archived.
+	`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var archive string
+		if len(args) > 0 {
+			archive = args[0]
+		}
+		manifest, archive, err := CreateSnapshot(config, archive)
+		if err != nil {
+			return fmt.Errorf("failed to create the snapshot: %w", err)
+		}
+
+		fmt.Printf("Created snapshot %s of chain %s at height %d and block hash %v\n",
+			archive, manifest.ChainID, manifest.Height, manifest.BlockHash)
+		return nil
+	},
+}
+
+var snapshotRestoreCmd = &cobra.Command{
+	Use:   "restore <archive>",
+	Short: "Restore the databases of a snapshot to the data directory",
+	Long: `
+restore extracts a snapshot created by "snapshot create" to the data directory,
+after checking that its manifest matches the chain ID and the initial height of
+the genesis file, and the block store backend of the config. The checksum of
+each file is verified as it is extracted.
+
+The databases of the snapshot must not be in the data directory already; they are
+removed by "cometbft reset-state". The node can then be started from the height
+of the snapshot, the application being expected to be at that height as well.
+	`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		manifest, err := RestoreSnapshot(config, args[0])
+		if err != nil {
+			return fmt.Errorf("failed to restore the snapshot: %w", err)
+		}
+
+		fmt.Printf("Restored snapshot of chain %s at height %d and block hash %v\n",
+			manifest.ChainID, manifest.Height, manifest.BlockHash)
+		return nil
+	},
+}
+
+func init() {
+	SnapshotCmd.AddCommand(snapshotCreateCmd)
+	SnapshotCmd.AddCommand(snapshotRestoreCmd)
+}
+
+// SnapshotManifest describes the content of a snapshot.
+type SnapshotManifest struct {
+	ChainID       string    `json:"chain_id"`
+	InitialHeight int64     `json:"initial_height"`
+	CreatedAt     time.Time `json:"created_at"`
+
+	// The heights of the stored blocks, and the hash of the last one.
+	Base      int64             `json:"base"`
+	Height    int64             `json:"height"`
+	BlockHash cmtbytes.HexBytes `json:"block_hash"`
+	// The height of the state, and the app hash it expects.
+	StateHeight int64             `json:"state_height"`
+	AppHash     cmtbytes.HexBytes `json:"app_hash"`
+
+	BlockStoreBackend string         `json:"block_store_backend"`
+	Files             []SnapshotFile `json:"files"`
+}
+
+// SnapshotFile is a file of the data directory stored in a snapshot.
+type SnapshotFile struct {
+	// The path of the file in the data directory, separated by slashes.
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// CreateSnapshot archives the databases of the data directory of config to
+// archive, or to <chain-id>-<height>.tar.gz if archive is empty, and returns
+// the manifest of the snapshot along with the path of the archive.
+func CreateSnapshot(config *cfg.Config, archive string) (*SnapshotManifest, string, error) {
+	manifest, err := loadSnapshotManifest(config)
+	if err != nil {
+		return nil, "", err
+	}
+	// The databases are closed, for their files not to change while they are
+	// archived, but stay locked, for the node not to be started meanwhile.
+	unlock, err := lockSnapshotDBs(config)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlock()
+
+	if archive == "" {
+		archive = fmt.Sprintf("%s-%d.tar.gz", manifest.ChainID, manifest.Height)
+	}
+	if archive, err = filepath.Abs(archive); err != nil {
+		return nil, "", err
+	}
+	if cmtos.FileExists(archive) {
+		return nil, "", fmt.Errorf("%s already exists", archive)
+	}
+
+	// The files are hashed first, for the manifest to precede them in the
+	// archive.
+	paths, err := snapshotPaths(config, archive)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, p := range paths {
+		file, err := hashSnapshotFile(filepath.Join(config.DBDir(), p))
+		if err != nil {
+			return nil, "", err
+		}
+		file.Path = filepath.ToSlash(p)
+		manifest.Files = append(manifest.Files, file)
+	}
+
+	if err := writeSnapshot(config.DBDir(), archive, manifest); err != nil {
+		_ = os.Remove(archive)
+		return nil, "", err
+	}
+	return manifest, archive, nil
+}
+
+// loadSnapshotManifest returns the manifest of a snapshot of the data directory
+// of config, without its files.
+func loadSnapshotManifest(config *cfg.Config) (*SnapshotManifest, error) {
+	blockStore, stateStore, err := loadStateAndBlockStore(config)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = blockStore.Close()
+		_ = stateStore.Close()
+	}()
+
+	state, err := stateStore.Load()
+	if err != nil {
+		return nil, err
+	}
+	if state.IsEmpty() {
+		return nil, errors.New("no state found")
+	}
+	meta := blockStore.LoadBlockMeta(blockStore.Height())
+	if meta == nil {
+		return nil, errors.New("no blocks found")
+	}
+	return &SnapshotManifest{
+		ChainID:           state.ChainID,
+		InitialHeight:     state.InitialHeight,
+		CreatedAt:         time.Now().UTC(),
+		Base:              blockStore.Base(),
+		Height:            meta.Header.Height,
+		BlockHash:         meta.BlockID.Hash,
+		StateHeight:       state.LastBlockHeight,
+		AppHash:           state.AppHash,
+		BlockStoreBackend: config.Storage.BlockStoreBackend,
+	}, nil
+}
+
+// lockSnapshotDBs takes the lock of the goleveldb databases of the data
+// directory of config, without opening them, so that they can't be opened,
+// e.g. by the node, until the returned function is called. It fails if any of
+// them is open.
+func lockSnapshotDBs(config *cfg.Config) (func(), error) {
+	var locks []storage.Storage
+	unlock := func() {
+		for _, lock := range locks {
+			_ = lock.Close()
+		}
+	}
+	if dbm.BackendType(config.DBBackend) != dbm.GoLevelDBBackend {
+		return unlock, nil
+	}
+
+	entries, err := os.ReadDir(config.DBDir())
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		dir := filepath.Join(config.DBDir(), entry.Name())
+		if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".db") || !cmtos.FileExists(filepath.Join(dir, "LOCK")) {
+			continue
+		}
+		// The read-only lock is shared, but still excludes the node.
+		lock, err := storage.OpenFile(dir, true)
+		if err != nil {
+			unlock()
+			return nil, fmt.Errorf("%s is in use, is the node running? %w", entry.Name(), err)
+		}
+		locks = append(locks, lock)
+	}
+	return unlock, nil
+}
+
+// snapshotPaths returns the paths, relative to the data directory, of the
+// files to archive, leaving out the validator state, the write-ahead logs and
+// the archive itself.
+func snapshotPaths(config *cfg.Config, archive string) ([]string, error) {
+	skipped := map[string]bool{
+		config.PrivValidatorStateFile():          true,
+		filepath.Dir(config.Consensus.WalFile()): true,
+		archive:                                  true,
+	}
+	if config.Mempool.WalEnabled() {
+		skipped[config.Mempool.WalDir()] = true
+	}
+
+	var paths []string
+	err := filepath.WalkDir(config.DBDir(), func(p string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if skipped[p] {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(config.DBDir(), p)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, rel)
+		return nil
+	})
+	return paths, err
+}
+
+func hashSnapshotFile(path string) (SnapshotFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return SnapshotFile{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return SnapshotFile{}, err
+	}
+	return SnapshotFile{Size: size, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// writeSnapshot writes the manifest, then the files it lists, to archive.
+func writeSnapshot(dbDir, archive string, manifest *SnapshotManifest) error {
+	f, err := os.OpenFile(archive, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	bz, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    snapshotManifestName,
+		Mode:    0o600,
+		Size:    int64(len(bz)),
+		ModTime: manifest.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(bz); err != nil {
+		return err
+	}
+
+	for _, file := range manifest.Files {
+		if err := writeSnapshotFile(tw, dbDir, file, manifest.CreatedAt); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func writeSnapshotFile(tw *tar.Writer, dbDir string, file SnapshotFile, modTime time.Time) error {
+	f, err := os.Open(filepath.Join(dbDir, filepath.FromSlash(file.Path)))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name:    path.Join(snapshotDataDir, file.Path),
+		Mode:    0o600,
+		Size:    file.Size,
+		ModTime: modTime,
+	}); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(tw, f, file.Size); err != nil {
+		return fmt.Errorf("archiving %s: %w", file.Path, err)
+	}
+	return nil
+}
+
+// RestoreSnapshot extracts the snapshot archive to the data directory of
+// config, and returns its manifest. The manifest is validated against the
+// genesis file and the config before any file is extracted.
+func RestoreSnapshot(config *cfg.Config, archive string) (*SnapshotManifest, error) {
+	f, err := os.Open(archive)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	tr := tar.NewReader(gr)
+
+	manifest, err := readSnapshotManifest(tr)
+	if err != nil {
+		return nil, err
+	}
+	genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+	if err != nil {
+		return nil, err
+	}
+	if err := manifest.validate(config, genDoc); err != nil {
+		return nil, err
+	}
+
+	// The files are extracted to a temporary directory, moved to the data
+	// directory once all of them were verified.
+	if err := cmtos.EnsureDir(config.DBDir(), 0o700); err != nil {
+		return nil, err
+	}
+	tmpDir, err := os.MkdirTemp(config.DBDir(), ".snapshot-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+	if err := extractSnapshot(tr, tmpDir, manifest); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(tmpDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if err := os.Rename(filepath.Join(tmpDir, entry.Name()), filepath.Join(config.DBDir(), entry.Name())); err != nil {
+			return nil, err
+		}
+	}
+	return manifest, nil
+}
+
+func readSnapshotManifest(tr *tar.Reader) (*SnapshotManifest, error) {
+	header, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("reading the manifest: %w", err)
+	}
+	if header.Name != snapshotManifestName {
+		return nil, fmt.Errorf("expected the manifest as first entry, got %s", header.Name)
+	}
+	manifest := new(SnapshotManifest)
+	if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("decoding the manifest: %w", err)
+	}
+	return manifest, nil
+}
+
+// validate checks that the snapshot is of the chain of genDoc, that its files
+// can be restored to the data directory of config, and that the node can
+// read them.
+func (m *SnapshotManifest) validate(config *cfg.Config, genDoc *types.GenesisDoc) error {
+	if m.ChainID != genDoc.ChainID {
+		return fmt.Errorf("snapshot of chain %q, while the genesis file is of chain %q", m.ChainID, genDoc.ChainID)
+	}
+	if m.InitialHeight != genDoc.InitialHeight {
+		return fmt.Errorf("snapshot of a chain starting at height %d, while the genesis file starts at height %d",
+			m.InitialHeight, genDoc.InitialHeight)
+	}
+	if m.Height < m.Base || m.StateHeight > m.Height+1 {
+		return fmt.Errorf("invalid heights: base %d, height %d, state height %d", m.Base, m.Height, m.StateHeight)
+	}
+	if m.BlockStoreBackend != config.Storage.BlockStoreBackend {
+		return fmt.Errorf("snapshot of the %q block store backend, while the config sets %q",
+			m.BlockStoreBackend, config.Storage.BlockStoreBackend)
+	}
+
+	existing := make(map[string]bool)
+	for _, file := range m.Files {
+		if p := path.Clean(file.Path); p != file.Path || path.IsAbs(p) || p == ".." || strings.HasPrefix(p, "../") {
+			return fmt.Errorf("invalid path %s", file.Path)
+		}
+		top := strings.SplitN(file.Path, "/", 2)[0]
+		if existing[top] {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(config.DBDir(), top)); err == nil {
+			return fmt.Errorf("%s is already in the data directory %s", top, config.DBDir())
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+		existing[top] = true
+	}
+	return nil
+}
+
+// extractSnapshot extracts the files of manifest from tr to dir, verifying
+// their size and checksum.
+func extractSnapshot(tr *tar.Reader, dir string, manifest *SnapshotManifest) error {
+	files := make(map[string]SnapshotFile, len(manifest.Files))
+	for _, file := range manifest.Files {
+		files[path.Join(snapshotDataDir, file.Path)] = file
+	}
+
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		file, ok := files[header.Name]
+		if !ok {
+			return fmt.Errorf("%s is not in the manifest", header.Name)
+		}
+		delete(files, header.Name)
+		if header.Typeflag != tar.TypeReg || header.Size != file.Size {
+			return fmt.Errorf("%s doesn't match the manifest", header.Name)
+		}
+		if err := extractSnapshotFile(tr, filepath.Join(dir, filepath.FromSlash(file.Path)), file); err != nil {
+			return err
+		}
+	}
+
+	for name := range files {
+		return fmt.Errorf("%s is missing from the archive", name)
+	}
+	return nil
+}
+
+func extractSnapshotFile(r io.Reader, path string, file SnapshotFile) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.CopyN(io.MultiWriter(f, h), r, file.Size); err != nil {
+		return fmt.Errorf("extracting %s: %w", file.Path, err)
+	}
+	if hex.EncodeToString(h.Sum(nil)) != file.SHA256 {
+		return fmt.Errorf("checksum mismatch for %s", file.Path)
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,117 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/privval"
+	"github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestSnapshot(t *testing.T) {
+	config := cfg.TestConfig()
+	config.DBBackend = "goleveldb"
+	config.SetRoot(t.TempDir())
+	cfg.EnsureRoot(config.RootDir)
+	require.NoError(t, initFilesWithConfig(config))
+	require.NoError(t, os.WriteFile(filepath.Join(config.DBDir(), "tx_index.sqlite"), []byte("indexes"), 0o600))
+
+	saveTestChain(t, config)
+
+	archive := filepath.Join(t.TempDir(), "snapshot.tar.gz")
+
+	// The databases must not be in use.
+	evidenceDB, err := dbm.NewDB("evidence", dbm.GoLevelDBBackend, config.DBDir())
+	require.NoError(t, err)
+	_, _, err = CreateSnapshot(config, archive)
+	require.Error(t, err)
+	assert.NoFileExists(t, archive)
+	require.NoError(t, evidenceDB.Close())
+
+	// While archived, the databases can't be opened.
+	unlock, err := lockSnapshotDBs(config)
+	require.NoError(t, err)
+	_, err = dbm.NewDB("state", dbm.GoLevelDBBackend, config.DBDir())
+	require.Error(t, err)
+	unlock()
+
+	manifest, path, err := CreateSnapshot(config, archive)
+	require.NoError(t, err)
+	assert.Equal(t, archive, path)
+	assert.Equal(t, int64(1), manifest.Base)
+	assert.Equal(t, int64(1), manifest.Height)
+	assert.Equal(t, int64(1), manifest.StateHeight)
+	assert.NotEmpty(t, manifest.BlockHash)
+	for _, file := range manifest.Files {
+		assert.NotEqual(t, "priv_validator_state.json", file.Path)
+	}
+	_, _, err = CreateSnapshot(config, archive)
+	require.Error(t, err, "the archive exists")
+
+	// The databases must be removed before restoring.
+	_, err = RestoreSnapshot(config, archive)
+	require.Error(t, err)
+
+	pv := privval.LoadFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+	pv.LastSignState.Height = 10
+	pv.Save()
+	require.NoError(t, resetState(config.DBDir(), logger))
+	require.NoError(t, os.Remove(filepath.Join(config.DBDir(), "tx_index.sqlite")))
+	restored, err := RestoreSnapshot(config, archive)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.BlockHash, restored.BlockHash)
+
+	blockStore, stateStore, err := loadStateAndBlockStore(config)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), blockStore.Height())
+	assert.Equal(t, manifest.BlockHash, blockStore.LoadBlockMeta(1).BlockID.Hash)
+	s, err := stateStore.Load()
+	require.NoError(t, err)
+	assert.EqualValues(t, manifest.AppHash, s.AppHash)
+	require.NoError(t, blockStore.Close())
+	require.NoError(t, stateStore.Close())
+	bz, err := os.ReadFile(filepath.Join(config.DBDir(), "tx_index.sqlite"))
+	require.NoError(t, err)
+	assert.Equal(t, "indexes", string(bz))
+	pv = privval.LoadFilePV(config.PrivValidatorKeyFile(), config.PrivValidatorStateFile())
+	assert.Equal(t, int64(10), pv.LastSignState.Height)
+
+	// The snapshot is only restored for the chain of the genesis file.
+	require.NoError(t, resetState(config.DBDir(), logger))
+	genDoc, err := types.GenesisDocFromFile(config.GenesisFile())
+	require.NoError(t, err)
+	genDoc.ChainID = "other-chain"
+	require.NoError(t, genDoc.SaveAs(config.GenesisFile()))
+	_, err = RestoreSnapshot(config, archive)
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(config.DBDir(), "state.db"))
+}
+
+// saveTestChain saves the genesis state of config and a first block.
+func saveTestChain(t *testing.T, config *cfg.Config) {
+	require.NoError(t, os.MkdirAll(filepath.Join(config.DBDir(), "blockstore.db"), 0o700))
+	require.NoError(t, os.MkdirAll(filepath.Join(config.DBDir(), "state.db"), 0o700))
+	blockStore, stateStore, err := loadStateAndBlockStore(config)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, blockStore.Close())
+		require.NoError(t, stateStore.Close())
+	}()
+
+	s, err := state.MakeGenesisStateFromFile(config.GenesisFile())
+	require.NoError(t, err)
+	block, parts := s.MakeBlock(1, nil, &types.Commit{}, nil, s.Validators.Proposer.Address)
+	blockStore.SaveBlock(block, parts, &types.Commit{Height: 1, BlockID: types.BlockID{Hash: block.Hash()}})
+	s.LastBlockHeight = 1
+	s.LastValidators = s.Validators.Copy()
+	s.LastBlockID = types.BlockID{Hash: block.Hash(), PartSetHeader: parts.Header()}
+	s.AppHash = []byte("app_hash")
+	require.NoError(t, stateStore.Save(s))
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
@@ -28,6 +29,14 @@ const (
 	// Default is v0.
 	MempoolV0 = "v0"
 	MempoolV1 = "v1"
+
+	// Slow WebSocket client policies. A client is slow if it falls behind the
+	// event replay buffer of a subscription, or can't be written to in time.
+	// SlowClientPolicyDropOldest skips the events the client missed, which it
+	// can detect from a gap in the sequence numbers, while
+	// SlowClientPolicyCancel cancels the subscription.
+	SlowClientPolicyDropOldest = "drop_oldest"
+	SlowClientPolicyCancel     = "cancel"
 )
 
 // NOTE: Most of the structs & relevant comments + the
@@ -145,6 +154,12 @@ func (cfg *Config) ValidateBasic() error {
 	if err := cfg.Consensus.ValidateBasic(); err != nil {
 		return fmt.Errorf("error in [consensus] section: %w", err)
 	}
+	if err := cfg.Storage.ValidateBasic(); err != nil {
+		return fmt.Errorf("error in [storage] section: %w", err)
+	}
+	if err := cfg.TxIndex.ValidateBasic(); err != nil {
+		return fmt.Errorf("error in [tx_index] section: %w", err)
+	}
 	if err := cfg.Instrumentation.ValidateBasic(); err != nil {
 		return fmt.Errorf("error in [instrumentation] section: %w", err)
 	}
@@ -287,6 +302,12 @@ func (cfg BaseConfig) DBDir() string {
 	return rootify(cfg.DBPath, cfg.RootDir)
 }
 
+// BlockStoreSegmentsDir returns the full path to the segments of the block
+// store, when stored in segments.
+func (cfg BaseConfig) BlockStoreSegmentsDir() string {
+	return filepath.Join(cfg.DBDir(), "blockstore.segments")
+}
+
 // ValidateBasic performs basic validation (checking param bounds, etc.) and
 // returns an error if any check fails.
 func (cfg BaseConfig) ValidateBasic() error {
@@ -323,7 +344,11 @@ type RPCConfig struct {
 	CORSAllowedHeaders []string `mapstructure:"cors_allowed_headers"`
 
 	// TCP or UNIX socket address for the gRPC server to listen on
-	// NOTE: This server only supports /broadcast_tx_commit
+	// NOTE: This server only supports /broadcast_tx_commit, batch broadcasts and
+	// the read-only queries of the QueryAPI service
+	// auth_keys and the rate limits apply to its calls as to those of the
+	// equivalent routes, with the API key passed in the "authorization" or
+	// "x-api-key" metadata
 	GRPCListenAddress string `mapstructure:"grpc_laddr"`
 
 	// Maximum number of simultaneous connections.
@@ -376,8 +401,25 @@ type RPCConfig struct {
 	// Enabling this parameter will cause the WebSocket connection to be closed
 	// instead if it cannot read fast enough, allowing for greater
 	// predictability in subscription behaviour.
+	//
+	// Equivalent to setting SlowClientPolicy to "cancel".
 	CloseOnSlowClient bool `mapstructure:"experimental_close_on_slow_client"`
 
+	// The number of events matching each subscribed query kept by the server,
+	// so that WebSocket clients can resume a subscription from a sequence
+	// number (see the from_seq parameter of /subscribe) after reconnecting.
+	EventReplayBufferSize int `mapstructure:"event_replay_buffer_size"`
+
+	// How long the events of a query are kept once it has no subscribers,
+	// bounding how long a WebSocket client can stay disconnected and resume
+	// its subscription.
+	EventReplayIdleTimeout time.Duration `mapstructure:"event_replay_idle_timeout"`
+
+	// What to do when a WebSocket client cannot read events fast enough:
+	// "drop_oldest" skips the events it missed, and "cancel" cancels the
+	// subscription.
+	SlowClientPolicy string `mapstructure:"slow_client_policy"`
+
 	// How long to wait for a tx to be committed during /broadcast_tx_commit
 	// WARNING: Using a value larger than 10s will result in increasing the
 	// global HTTP write timeout, which applies to all connections and endpoints.
@@ -408,6 +450,59 @@ type RPCConfig struct {
 	// Otherwise, HTTP server is run.
 	TLSKeyFile string `mapstructure:"tls_key_file"`
 
+	// API keys allowed to call the RPC server, each as "<key>:<acl>", where
+	// <acl> is a comma-separated list of route names and route groups ("read",
+	// "broadcast", "unsafe", "tracks", or "*" for all routes).
+	// Keys are passed in the "Authorization: Bearer <key>" or "X-API-Key: <key>"
+	// header. If empty, the RPC server does not require authentication.
+	AuthKeys []string `mapstructure:"auth_keys"`
+
+	// Maximum average number of calls per second of each client, identified by
+	// its API key if auth_keys is set, or else by its IP. 0 means no limit.
+	// The requests with an invalid API key are also limited, by IP. Calls to
+	// ExpensiveRoutes are not counted, see ExpensiveRateLimit.
+	RateLimit float64 `mapstructure:"rate_limit"`
+
+	// Maximum number of calls a client can make at once, within RateLimit.
+	// If 0, it defaults to RateLimit.
+	RateLimitBurst int `mapstructure:"rate_limit_burst"`
+
+	// Maximum average number of calls per second of each client to
+	// ExpensiveRoutes. 0 means no limit.
+	ExpensiveRateLimit float64 `mapstructure:"expensive_rate_limit"`
+
+	// Maximum number of calls to ExpensiveRoutes a client can make at once,
+	// within ExpensiveRateLimit. If 0, it defaults to ExpensiveRateLimit.
+	ExpensiveRateLimitBurst int `mapstructure:"expensive_rate_limit_burst"`
+
+	// Routes limited by ExpensiveRateLimit instead of RateLimit.
+	ExpensiveRoutes []string `mapstructure:"expensive_routes"`
+
+	// Maximum size in bytes of the in-memory cache of the responses of
+	// cacheable calls at explicit heights, e.g. for blocks at past heights,
+	// or of immutable calls, e.g. genesis. 0 disables the cache.
+	ResponseCacheMaxBytes int `mapstructure:"response_cache_max_bytes"`
+
+	// Maximum number of requests in a JSON-RPC batch request. 0 means no
+	// limit.
+	MaxBatchSize int `mapstructure:"max_batch_size"`
+
+	// Maximum number of calls of a JSON-RPC batch request executed
+	// concurrently. Broadcasts, check_tx and unsafe calls are always executed
+	// in order.
+	BatchParallelism int `mapstructure:"batch_parallelism"`
+
+	// Maximum duration of each call of a JSON-RPC batch request, after which
+	// it fails. A call that timed out still counts towards BatchParallelism
+	// until it returns. 0 means no timeout.
+	BatchCallTimeout time.Duration `mapstructure:"batch_call_timeout"`
+
+	// Maximum duration of the calls to some routes, each as
+	// "<route>:<duration>", after which their context is cancelled, e.g. to
+	// stop index scans of clients that went away. Routes not listed here have
+	// no timeout, besides BatchCallTimeout.
+	RouteTimeouts []string `mapstructure:"route_timeouts"`
+
 	// pprof listen address (https://golang.org/pkg/net/http/pprof)
 	PprofListenAddress string `mapstructure:"pprof_laddr"`
 }
@@ -431,12 +526,35 @@ func DefaultRPCConfig() *RPCConfig {
 		SubscriptionBufferSize:    defaultSubscriptionBufferSize,
 		TimeoutBroadcastTxCommit:  10 * time.Second,
 		WebSocketWriteBufferSize:  defaultSubscriptionBufferSize,
+		EventReplayBufferSize:     defaultSubscriptionBufferSize,
+		EventReplayIdleTimeout:    time.Minute,
+		SlowClientPolicy:          SlowClientPolicyDropOldest,
 
 		MaxBodyBytes:   int64(1000000), // 1MB
 		MaxHeaderBytes: 1 << 20,        // same as the net/http default
 
 		TLSCertFile: "",
 		TLSKeyFile:  "",
+
+		AuthKeys: []string{},
+
+		RateLimit:          0,
+		ExpensiveRateLimit: 0,
+		ExpensiveRoutes: []string{
+			"tx_search", "block_search", "block_results", "tracks_get_pod", "tracks_pod_count",
+			"tx_count", "event_histogram", "block_count",
+		},
+
+		ResponseCacheMaxBytes: 0,
+
+		MaxBatchSize:     0,
+		BatchParallelism: 4,
+		BatchCallTimeout: 0,
+
+		RouteTimeouts: []string{
+			"tx_search:10s", "block_search:10s", "tx_count:10s", "event_histogram:10s", "block_count:10s",
+			"abci_query:10s",
+		},
 	}
 }
 
@@ -476,6 +594,18 @@ func (cfg *RPCConfig) ValidateBasic() error {
 			cfg.SubscriptionBufferSize,
 		)
 	}
+	if cfg.EventReplayBufferSize <= 0 {
+		return errors.New("event_replay_buffer_size must be positive")
+	}
+	if cfg.EventReplayIdleTimeout < 0 {
+		return errors.New("event_replay_idle_timeout can't be negative")
+	}
+	switch cfg.SlowClientPolicy {
+	case SlowClientPolicyDropOldest, SlowClientPolicyCancel:
+	default:
+		return fmt.Errorf("unknown slow_client_policy %q, expected %q or %q",
+			cfg.SlowClientPolicy, SlowClientPolicyDropOldest, SlowClientPolicyCancel)
+	}
 	if cfg.TimeoutBroadcastTxCommit < 0 {
 		return errors.New("timeout_broadcast_tx_commit can't be negative")
 	}
@@ -485,9 +615,109 @@ func (cfg *RPCConfig) ValidateBasic() error {
 	if cfg.MaxHeaderBytes < 0 {
 		return errors.New("max_header_bytes can't be negative")
 	}
+	if _, err := cfg.AuthACL(); err != nil {
+		return fmt.Errorf("auth_keys: %w", err)
+	}
+	if cfg.RateLimit < 0 {
+		return errors.New("rate_limit can't be negative")
+	}
+	if cfg.RateLimitBurst < 0 {
+		return errors.New("rate_limit_burst can't be negative")
+	}
+	if cfg.ExpensiveRateLimit < 0 {
+		return errors.New("expensive_rate_limit can't be negative")
+	}
+	if cfg.ExpensiveRateLimitBurst < 0 {
+		return errors.New("expensive_rate_limit_burst can't be negative")
+	}
+	if cfg.ResponseCacheMaxBytes < 0 {
+		return errors.New("response_cache_max_bytes can't be negative")
+	}
+	if cfg.MaxBatchSize < 0 {
+		return errors.New("max_batch_size can't be negative")
+	}
+	if cfg.BatchParallelism < 1 {
+		return errors.New("batch_parallelism must be positive")
+	}
+	if cfg.BatchCallTimeout < 0 {
+		return errors.New("batch_call_timeout can't be negative")
+	}
+	if _, err := cfg.CallTimeouts(); err != nil {
+		return fmt.Errorf("route_timeouts: %w", err)
+	}
 	return nil
 }
 
+// CancelSlowClients returns true if the subscriptions of slow WebSocket
+// clients are cancelled rather than skipping events.
+func (cfg *RPCConfig) CancelSlowClients() bool {
+	return cfg.CloseOnSlowClient || cfg.SlowClientPolicy == SlowClientPolicyCancel
+}
+
+// IsRateLimitEnabled returns true if the rate of calls to the RPC server is
+// limited.
+func (cfg *RPCConfig) IsRateLimitEnabled() bool {
+	return cfg.RateLimit > 0 || cfg.ExpensiveRateLimit > 0
+}
+
+// IsAuthEnabled returns true if API keys are required to call the RPC server.
+func (cfg *RPCConfig) IsAuthEnabled() bool {
+	return len(cfg.AuthKeys) != 0
+}
+
+// AuthACL parses AuthKeys into a map from API keys to the route names and
+// route groups they are allowed to call.
+func (cfg *RPCConfig) AuthACL() (map[string][]string, error) {
+	acl := make(map[string][]string, len(cfg.AuthKeys))
+	for n, entry := range cfg.AuthKeys {
+		// Entries are not quoted in errors, so as to not log the keys.
+		i := strings.LastIndex(entry, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("entry #%d must be of the form <key>:<acl>", n)
+		}
+		key := entry[:i]
+		if _, ok := acl[key]; ok {
+			return nil, fmt.Errorf("entry #%d: duplicate API key", n)
+		}
+		var routes []string
+		for _, route := range strings.Split(entry[i+1:], ",") {
+			if route = strings.TrimSpace(route); route != "" {
+				routes = append(routes, route)
+			}
+		}
+		if len(routes) == 0 {
+			return nil, fmt.Errorf("entry #%d has an empty acl", n)
+		}
+		acl[key] = routes
+	}
+	return acl, nil
+}
+
+// CallTimeouts parses RouteTimeouts into a map from route names to the
+// maximum duration of their calls.
+func (cfg *RPCConfig) CallTimeouts() (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration, len(cfg.RouteTimeouts))
+	for _, entry := range cfg.RouteTimeouts {
+		i := strings.LastIndex(entry, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("entry %q must be of the form <route>:<duration>", entry)
+		}
+		route := strings.TrimSpace(entry[:i])
+		if _, ok := timeouts[route]; ok {
+			return nil, fmt.Errorf("duplicate route %q", route)
+		}
+		timeout, err := time.ParseDuration(strings.TrimSpace(entry[i+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("entry %q: %w", entry, err)
+		}
+		if timeout <= 0 {
+			return nil, fmt.Errorf("entry %q: timeout must be positive", entry)
+		}
+		timeouts[route] = timeout
+	}
+	return timeouts, nil
+}
+
 // IsCorsEnabled returns true if cross-origin resource sharing is enabled.
 func (cfg *RPCConfig) IsCorsEnabled() bool {
 	return len(cfg.CORSAllowedOrigins) != 0
@@ -747,6 +977,19 @@ type MempoolConfig struct {
 	// has existed in the mempool at least TTLNumBlocks number of blocks or if
 	// it's insertion time into the mempool is beyond TTLDuration.
 	TTLNumBlocks int64 `mapstructure:"ttl-num-blocks"`
+
+	// V0Prioritized (default: false) makes the "v0" mempool take into account
+	// the priority assigned by the application in ResponseCheckTx. When the
+	// mempool is full, the lowest-priority transactions are evicted to make
+	// room for a higher-priority one, and higher-priority transactions are
+	// gossiped to peers first. It has no effect on the "v1" mempool, which is
+	// always prioritized.
+	V0Prioritized bool `mapstructure:"v0-prioritized"`
+
+	// TxLifecycleSize is the number of transactions whose lifecycle in the
+	// mempool (received, CheckTx result, eviction, expiry, commit) is kept
+	// for the tx_status RPC endpoint. Set to 0 to disable tracking.
+	TxLifecycleSize int `mapstructure:"tx-lifecycle-size"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the CometBFT mempool
@@ -764,6 +1007,8 @@ func DefaultMempoolConfig() *MempoolConfig {
 		MaxTxBytes:   1024 * 1024, // 1MB
 		TTLDuration:  0 * time.Second,
 		TTLNumBlocks: 0,
+
+		TxLifecycleSize: 10000,
 	}
 }
 
@@ -799,6 +1044,9 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.MaxTxBytes < 0 {
 		return errors.New("max_tx_bytes can't be negative")
 	}
+	if cfg.TxLifecycleSize < 0 {
+		return errors.New("tx-lifecycle-size can't be negative")
+	}
 	return nil
 }
 
@@ -1100,6 +1348,26 @@ type StorageConfig struct {
 	// required for `/block_results` RPC queries, and to reindex events in the
 	// command-line tool.
 	DiscardABCIResponses bool `mapstructure:"discard_abci_responses"`
+
+	// Where the blocks are stored
+	//
+	// Options:
+	//   1) "db" (default) - in the blockstore database.
+	//   2) "segments" - the blocks are appended to segment files, in the
+	//      blockstore.segments directory of the data directory, and only
+	//      their location and metadata are stored in the blockstore
+	//      database, reducing the compactions of the database. The blocks
+	//      stored before are moved with the migrate-block-store command.
+	BlockStoreBackend string `mapstructure:"block_store_backend"`
+
+	// Compact the blockstore, state and tx_index databases in the background,
+	// once compaction_interval heights were pruned from them, to reclaim the
+	// disk space of the pruned data without stopping the node. Only the
+	// goleveldb, cleveldb and rocksdb backends are compacted.
+	Compact bool `mapstructure:"compact"`
+
+	// Number of heights pruned from a database between its compactions.
+	CompactionInterval int64 `mapstructure:"compaction_interval"`
 }
 
 // DefaultStorageConfig returns the default configuration options relating to
@@ -1107,6 +1375,9 @@ type StorageConfig struct {
 func DefaultStorageConfig() *StorageConfig {
 	return &StorageConfig{
 		DiscardABCIResponses: false,
+		BlockStoreBackend:    "db",
+		Compact:              false,
+		CompactionInterval:   1000,
 	}
 }
 
@@ -1115,9 +1386,26 @@ func DefaultStorageConfig() *StorageConfig {
 func TestStorageConfig() *StorageConfig {
 	return &StorageConfig{
 		DiscardABCIResponses: false,
+		BlockStoreBackend:    "db",
+		Compact:              false,
+		CompactionInterval:   1000,
 	}
 }
 
+// ValidateBasic performs basic validation and returns an error if any check
+// fails.
+func (cfg *StorageConfig) ValidateBasic() error {
+	switch cfg.BlockStoreBackend {
+	case "db", "segments":
+	default:
+		return fmt.Errorf("unknown block_store_backend %q, expected \"db\" or \"segments\"", cfg.BlockStoreBackend)
+	}
+	if cfg.CompactionInterval <= 0 {
+		return errors.New("compaction_interval must be positive")
+	}
+	return nil
+}
+
 // -----------------------------------------------------------------------------
 // TxIndexConfig
 // Remember that Event has the following structure:
@@ -1139,11 +1427,35 @@ type TxIndexConfig struct {
 	//   2) "kv" (default) - the simplest possible indexer,
 	//      backed by key-value storage (defaults to levelDB; see DBBackend).
 	//   3) "psql" - the indexer services backed by PostgreSQL.
+	//   4) "sqlite" - the indexer services backed by SQLite, in the
+	//      tx_index.sqlite file of the data directory.
 	Indexer string `mapstructure:"indexer"`
 
 	// The PostgreSQL connection configuration, the connection format:
 	// postgresql://<user>:<password>@<host>:<port>/<db>?<opts>
 	PsqlConn string `mapstructure:"psql-conn"`
+
+	// Number of the latest heights whose transactions and block events are
+	// kept indexed, the older ones being pruned. The heights whose blocks were
+	// pruned, according to the retain height of the application, are pruned
+	// anyway. 0 only prunes those.
+	RetainBlocks int64 `mapstructure:"retain_blocks"`
+
+	// Composite keys ("type.key") of the event attributes indexed by the "kv"
+	// indexer, among the ones the application flagged for indexing. A
+	// pattern ending with '*' matches the keys starting with its prefix,
+	// such as "message.*". If empty, all the flagged attributes are indexed.
+	IncludeEvents []string `mapstructure:"include_events"`
+
+	// Composite keys of the event attributes not indexed by the "kv" indexer,
+	// even if included.
+	ExcludeEvents []string `mapstructure:"exclude_events"`
+
+	// Composite keys of the event attributes whose values are addresses
+	// (e.g. "transfer.sender"), by which the "kv" indexer indexes the txs,
+	// regardless of the index flag of the attributes and of the filters
+	// above, to list the txs of an address with the account_txs RPC.
+	AddressEvents []string `mapstructure:"address_events"`
 }
 
 // DefaultTxIndexConfig returns a default configuration for the transaction indexer.
@@ -1158,6 +1470,45 @@ func TestTxIndexConfig() *TxIndexConfig {
 	return DefaultTxIndexConfig()
 }
 
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *TxIndexConfig) ValidateBasic() error {
+	if cfg.RetainBlocks < 0 {
+		return errors.New("retain_blocks can't be negative")
+	}
+	for _, pattern := range cfg.IncludeEvents {
+		if err := validateEventPattern(pattern); err != nil {
+			return fmt.Errorf("include_events: %w", err)
+		}
+	}
+	for _, pattern := range cfg.ExcludeEvents {
+		if err := validateEventPattern(pattern); err != nil {
+			return fmt.Errorf("exclude_events: %w", err)
+		}
+	}
+	for _, pattern := range cfg.AddressEvents {
+		if err := validateEventPattern(pattern); err != nil {
+			return fmt.Errorf("address_events: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateEventPattern checks that pattern is either a composite key
+// ("type.key") or a prefix followed by '*'.
+func validateEventPattern(pattern string) error {
+	prefix := strings.TrimSuffix(pattern, "*")
+	switch {
+	case strings.Contains(prefix, "*"):
+		return fmt.Errorf("%q: '*' is only allowed at the end", pattern)
+	case prefix != pattern:
+		return nil
+	case strings.HasPrefix(pattern, ".") || strings.HasSuffix(pattern, ".") || !strings.Contains(pattern, "."):
+		return fmt.Errorf("%q: expected type.key", pattern)
+	}
+	return nil
+}
+
 //-----------------------------------------------------------------------------
 // InstrumentationConfig
 
@@ -75,6 +75,11 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 		"TimeoutBroadcastTxCommit",
 		"MaxBodyBytes",
 		"MaxHeaderBytes",
+		"RateLimitBurst",
+		"ExpensiveRateLimitBurst",
+		"ResponseCacheMaxBytes",
+		"MaxBatchSize",
+		"BatchCallTimeout",
 	}
 
 	for _, fieldName := range fieldsToTest {
@@ -82,6 +87,75 @@ func TestRPCConfigValidateBasic(t *testing.T) {
 		assert.Error(t, cfg.ValidateBasic())
 		reflect.ValueOf(cfg).Elem().FieldByName(fieldName).SetInt(0)
 	}
+
+	cfg.BatchParallelism = 0
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestRPCConfigEventReplay(t *testing.T) {
+	cfg := TestRPCConfig()
+	assert.False(t, cfg.CancelSlowClients())
+
+	cfg.SlowClientPolicy = SlowClientPolicyCancel
+	assert.True(t, cfg.CancelSlowClients())
+	cfg.SlowClientPolicy = SlowClientPolicyDropOldest
+	cfg.CloseOnSlowClient = true
+	assert.True(t, cfg.CancelSlowClients())
+
+	cfg.SlowClientPolicy = "block"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.SlowClientPolicy = SlowClientPolicyDropOldest
+	cfg.EventReplayBufferSize = 0
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.EventReplayBufferSize = 1
+	cfg.EventReplayIdleTimeout = -time.Second
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestRPCConfigAuthACL(t *testing.T) {
+	cfg := TestRPCConfig()
+	assert.False(t, cfg.IsAuthEnabled())
+
+	cfg.AuthKeys = []string{"k3y:read, broadcast_tx_sync", "4dm1n:*"}
+	assert.True(t, cfg.IsAuthEnabled())
+	acl, err := cfg.AuthACL()
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"k3y":   {"read", "broadcast_tx_sync"},
+		"4dm1n": {"*"},
+	}, acl)
+
+	for _, keys := range [][]string{
+		{"k3y"},
+		{":read"},
+		{"k3y:"},
+		{"k3y:read", "k3y:*"},
+	} {
+		cfg.AuthKeys = keys
+		assert.Error(t, cfg.ValidateBasic(), keys)
+	}
+}
+
+func TestRPCConfigCallTimeouts(t *testing.T) {
+	cfg := TestRPCConfig()
+	cfg.RouteTimeouts = []string{"tx_search:10s", " abci_query : 1m "}
+	timeouts, err := cfg.CallTimeouts()
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"tx_search":  10 * time.Second,
+		"abci_query": time.Minute,
+	}, timeouts)
+
+	for _, entries := range [][]string{
+		{"tx_search"},
+		{":10s"},
+		{"tx_search:10"},
+		{"tx_search:0s"},
+		{"tx_search:10s", "tx_search:1s"},
+	} {
+		cfg.RouteTimeouts = entries
+		assert.Error(t, cfg.ValidateBasic(), entries)
+	}
 }
 
 func TestP2PConfigValidateBasic(t *testing.T) {
@@ -182,6 +256,45 @@ func TestConsensusConfig_ValidateBasic(t *testing.T) {
 	}
 }
 
+func TestStorageConfigValidateBasic(t *testing.T) {
+	cfg := TestStorageConfig()
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.BlockStoreBackend = "segments"
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.BlockStoreBackend = "files"
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.BlockStoreBackend = "db"
+
+	cfg.CompactionInterval = 0
+	assert.Error(t, cfg.ValidateBasic())
+}
+
+func TestTxIndexConfigValidateBasic(t *testing.T) {
+	cfg := TestTxIndexConfig()
+	assert.NoError(t, cfg.ValidateBasic())
+
+	cfg.RetainBlocks = -1
+	assert.Error(t, cfg.ValidateBasic())
+	cfg.RetainBlocks = 0
+
+	cfg.IncludeEvents = []string{"transfer.recipient", "message.*", "*"}
+	cfg.ExcludeEvents = []string{"message.action"}
+	assert.NoError(t, cfg.ValidateBasic())
+
+	for _, pattern := range []string{"", "transfer", "transfer.", ".recipient", "mess*age.*"} {
+		cfg.ExcludeEvents = []string{pattern}
+		assert.Error(t, cfg.ValidateBasic(), pattern)
+	}
+	cfg.ExcludeEvents = nil
+
+	cfg.AddressEvents = []string{"transfer.sender", "transfer.recipient"}
+	assert.NoError(t, cfg.ValidateBasic())
+	cfg.AddressEvents = []string{"sender"}
+	assert.Error(t, cfg.ValidateBasic())
+}
+
 func TestInstrumentationConfigValidateBasic(t *testing.T) {
 	cfg := TestInstrumentationConfig()
 	assert.NoError(t, cfg.ValidateBasic())
@@ -172,7 +172,11 @@ cors_allowed_methods = [{{ range .RPC.CORSAllowedMethods }}{{ printf "%q, " . }}
 cors_allowed_headers = [{{ range .RPC.CORSAllowedHeaders }}{{ printf "%q, " . }}{{end}}]
 
 # TCP or UNIX socket address for the gRPC server to listen on
-# NOTE: This server only supports /broadcast_tx_commit
+# NOTE: This server only supports /broadcast_tx_commit, batch broadcasts and
+# the read-only queries of the QueryAPI service
+# auth_keys and the rate limits apply to its calls as to those of the
+# equivalent routes, with the API key passed in the "authorization" or
+# "x-api-key" metadata
 grpc_laddr = "{{ .RPC.GRPCListenAddress }}"
 
 # Maximum number of simultaneous connections.
@@ -233,6 +237,25 @@ experimental_websocket_write_buffer_size = {{ .RPC.WebSocketWriteBufferSize }}
 # predictability in subscription behaviour.
 experimental_close_on_slow_client = {{ .RPC.CloseOnSlowClient }}
 
+# The number of events matching each subscribed query kept by the node, so
+# that WebSocket clients can resume a subscription after reconnecting, by
+# passing the epoch of the events and the sequence number of the next event
+# they expect as "epoch" and "from_seq" to /subscribe.
+event_replay_buffer_size = {{ .RPC.EventReplayBufferSize }}
+
+# How long the events of a query are kept once it has no subscribers, which is
+# how long a WebSocket client can stay disconnected and resume its
+# subscription. 0 drops them right away.
+event_replay_idle_timeout = "{{ .RPC.EventReplayIdleTimeout }}"
+
+# What to do when a WebSocket client cannot read events fast enough:
+# - "drop_oldest": skip the events it missed. Clients can detect them from a
+#   gap in the sequence numbers of events, and resubscribe from the first
+#   missing one if it is still buffered.
+# - "cancel": cancel the subscription.
+# Setting experimental_close_on_slow_client is equivalent to "cancel".
+slow_client_policy = "{{ .RPC.SlowClientPolicy }}"
+
 # How long to wait for a tx to be committed during /broadcast_tx_commit.
 # WARNING: Using a value larger than 10s will result in increasing the
 # global HTTP write timeout, which applies to all connections and endpoints.
@@ -260,6 +283,65 @@ tls_cert_file = "{{ .RPC.TLSCertFile }}"
 # Otherwise, HTTP server is run.
 tls_key_file = "{{ .RPC.TLSKeyFile }}"
 
+# API keys allowed to call the RPC server, each as "<key>:<acl>", where <acl>
+# is a comma-separated list of route names and route groups ("read",
+# "broadcast", "unsafe", "tracks", or "*" for all routes), e.g.
+# ["k3y:read,broadcast_tx_sync", "4dm1n:*"].
+# Keys are passed in the "Authorization: Bearer <key>" or "X-API-Key: <key>"
+# header. If empty, the RPC server does not require authentication.
+# NOTE: unsafe routes are still only available if unsafe = true.
+auth_keys = [{{ range .RPC.AuthKeys }}{{ printf "%q, " . }}{{end}}]
+
+# Maximum average number of calls per second of each client, identified by its
+# API key if auth_keys is set, or else by its IP. 0 means no limit. The
+# requests with an invalid API key are also limited, by IP.
+# Calls to expensive_routes are limited by expensive_rate_limit instead.
+# Calls exceeding the limit fail with an HTTP 429 status, or a JSON-RPC error
+# within a batch or over the websocket.
+rate_limit = {{ .RPC.RateLimit }}
+
+# Maximum number of calls a client can make at once, within rate_limit.
+# If 0, it defaults to rate_limit.
+rate_limit_burst = {{ .RPC.RateLimitBurst }}
+
+# Maximum average number of calls per second of each client to
+# expensive_routes. 0 means no limit.
+expensive_rate_limit = {{ .RPC.ExpensiveRateLimit }}
+
+# Maximum number of calls to expensive_routes a client can make at once, within
+# expensive_rate_limit. If 0, it defaults to expensive_rate_limit.
+expensive_rate_limit_burst = {{ .RPC.ExpensiveRateLimitBurst }}
+
+# Routes limited by expensive_rate_limit instead of rate_limit.
+expensive_routes = [{{ range .RPC.ExpensiveRoutes }}{{ printf "%q, " . }}{{end}}]
+
+# Maximum size in bytes of the in-memory cache of the responses of cacheable
+# calls at explicit heights, e.g. block, commit or validators, and of genesis
+# and genesis_chunked. The other calls without a height, e.g. abci_info or
+# blockchain, are never cached, nor is the commit of the latest block, until
+# it is canonical. 0 disables the cache.
+response_cache_max_bytes = {{ .RPC.ResponseCacheMaxBytes }}
+
+# Maximum number of requests in a JSON-RPC batch request. Larger batches are
+# rejected. 0 means no limit.
+max_batch_size = {{ .RPC.MaxBatchSize }}
+
+# Maximum number of calls of a JSON-RPC batch request executed concurrently.
+# Broadcasts, check_tx and unsafe calls are always executed in order with the
+# other calls of the batch.
+batch_parallelism = {{ .RPC.BatchParallelism }}
+
+# Maximum duration of each call of a JSON-RPC batch request, after which it
+# fails, without holding up the rest of the batch. A call that timed out still
+# counts towards batch_parallelism until it returns. 0 means no timeout.
+batch_call_timeout = "{{ .RPC.BatchCallTimeout }}"
+
+# Maximum duration of the calls to some routes, each as "<route>:<duration>",
+# after which they fail and stop using resources, e.g. the index scans of
+# tx_search and block_search for clients that went away. Routes not listed
+# here have no timeout, besides batch_call_timeout within batches.
+route_timeouts = [{{ range .RPC.RouteTimeouts }}{{ printf "%q, " . }}{{end}}]
+
 # pprof listen address (https://golang.org/pkg/net/http/pprof)
 pprof_laddr = "{{ .RPC.PprofListenAddress }}"
 
@@ -397,6 +479,18 @@ ttl-duration = "{{ .Mempool.TTLDuration }}"
 # it's insertion time into the mempool is beyond ttl-duration.
 ttl-num-blocks = {{ .Mempool.TTLNumBlocks }}
 
+# v0-prioritized (default: false) makes the "v0" mempool take into account the
+# priority assigned by the application in CheckTx. When the mempool is full,
+# the lowest-priority transactions are evicted to make room for a
+# higher-priority one, and higher-priority transactions are gossiped first.
+# It has no effect on the "v1" mempool, which is always prioritized.
+v0-prioritized = {{ .Mempool.V0Prioritized }}
+
+# tx-lifecycle-size is the number of transactions whose lifecycle in the
+# mempool (received, CheckTx result, eviction, expiry, commit) is kept for the
+# tx_status RPC endpoint. Set to 0 to disable tracking.
+tx-lifecycle-size = {{ .Mempool.TxLifecycleSize }}
+
 #######################################################
 ###         State Sync Configuration Options        ###
 #######################################################
@@ -496,6 +590,25 @@ peer_query_maj23_sleep_duration = "{{ .Consensus.PeerQueryMaj23SleepDuration }}"
 # reindex events in the command-line tool.
 discard_abci_responses = {{ .Storage.DiscardABCIResponses}}
 
+# Where the blocks are stored
+#
+# Options:
+#   1) "db" (default) - in the blockstore database.
+#   2) "segments" - the blocks are appended to segment files, in the blockstore.segments
+#   directory of the data directory, and only their location and metadata are stored
+#   in the blockstore database, reducing the compactions of the database. The blocks
+#   stored before are moved with the migrate-block-store command, with the node stopped.
+block_store_backend = "{{ .Storage.BlockStoreBackend }}"
+
+# Compact the blockstore, state and tx_index databases in the background, once
+# compaction_interval heights were pruned from them, to reclaim the disk space
+# of the pruned data without stopping the node. Only the goleveldb, cleveldb
+# and rocksdb backends are compacted.
+compact = {{ .Storage.Compact }}
+
+# Number of heights pruned from a database between its compactions.
+compaction_interval = {{ .Storage.CompactionInterval }}
+
 #######################################################
 ###   Transaction Indexer Configuration Options     ###
 #######################################################
@@ -511,13 +624,36 @@ discard_abci_responses = {{ .Storage.DiscardABCIResponses}}
 #   2) "kv" (default) - the simplest possible indexer, backed by key-value storage (defaults to levelDB; see DBBackend).
 # 		- When "kv" is chosen "tx.height" and "tx.hash" will always be indexed.
 #   3) "psql" - the indexer services backed by PostgreSQL.
-# When "kv" or "psql" is chosen "tx.height" and "tx.hash" will always be indexed.
+#   4) "sqlite" - the indexer services backed by SQLite, in the tx_index.sqlite file of the data directory.
+# When "kv", "psql" or "sqlite" is chosen "tx.height" and "tx.hash" will always be indexed.
 indexer = "{{ .TxIndex.Indexer }}"
 
 # The PostgreSQL connection configuration, the connection format:
 #   postgresql://<user>:<password>@<host>:<port>/<db>?<opts>
 psql-conn = "{{ .TxIndex.PsqlConn }}"
 
+# Number of the latest heights whose transactions and block events are kept
+# indexed, the older ones being pruned every 100 heights by the "kv", "psql"
+# and "sqlite" indexers. The heights whose blocks were pruned, according to the
+# retain height set by the application, are pruned anyway. 0 only prunes those.
+retain_blocks = {{ .TxIndex.RetainBlocks }}
+
+# Composite keys ("type.key") of the event attributes indexed by the "kv"
+# indexer, among the ones the application flagged for indexing. A pattern
+# ending with '*' matches the keys starting with its prefix, e.g. "message.*".
+# If empty, all the flagged attributes are indexed.
+include_events = [{{ range .TxIndex.IncludeEvents }}{{ printf "%q, " . }}{{end}}]
+
+# Composite keys of the event attributes not indexed by the "kv" indexer, even
+# if included, e.g. ["transfer.memo"].
+exclude_events = [{{ range .TxIndex.ExcludeEvents }}{{ printf "%q, " . }}{{end}}]
+
+# Composite keys of the event attributes whose values are addresses, by which
+# the "kv" indexer indexes the txs, regardless of the index flag of the
+# attributes and of the filters above, e.g. ["transfer.sender",
+# "transfer.recipient"]. The account_txs RPC lists the txs of an address.
+address_events = [{{ range .TxIndex.AddressEvents }}{{ printf "%q, " . }}{{end}}]
+
 #######################################################
 ###       Instrumentation Configuration Options     ###
 #######################################################
@@ -290,7 +290,17 @@ func newConsensusStateForReplay(config cfg.BaseConfig, csConfig *cfg.ConsensusCo
 	if err != nil {
 		cmtos.Exit(err.Error())
 	}
-	blockStore := store.NewBlockStore(blockStoreDB)
+	// The blocks stored in segments are read from them, whatever the backend
+	// of the new blocks.
+	var options []store.Option
+	if cmtos.FileExists(config.BlockStoreSegmentsDir()) {
+		segments, err := store.OpenSegments(config.BlockStoreSegmentsDir(), store.DefaultMaxSegmentSize)
+		if err != nil {
+			cmtos.Exit(err.Error())
+		}
+		options = append(options, store.WithSegments(segments))
+	}
+	blockStore := store.NewBlockStore(blockStoreDB, options...)
 
 	// Get State
 	stateDB, err := dbm.NewDB("state", dbType, config.DBDir())
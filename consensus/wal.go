@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"hash/crc32"
 	"io"
+	"os"
 	"path/filepath"
 	"time"
 
@@ -282,6 +283,117 @@ func (wal *BaseWAL) SearchForEndHeight(
 	return nil, false, nil
 }
 
+// TruncateWAL removes the messages following the EndHeightMessage of height
+// from the WAL at walFile, as when rolling back the state to height, and
+// returns their number. Without such a message, all the messages are removed,
+// and the EndHeightMessage of height is written alone, for the consensus to
+// start from the next height. With dryRun, the messages are only counted. The
+// node must be stopped.
+func TruncateWAL(walFile string, height int64, dryRun bool) (int, error) {
+	if !cmtos.FileExists(walFile) {
+		return 0, nil
+	}
+	group, err := auto.OpenGroup(walFile)
+	if err != nil {
+		return 0, err
+	}
+	minIndex, maxIndex := group.MinIndex(), group.MaxIndex()
+	gr, err := group.NewReader(minIndex)
+	if err != nil {
+		group.Close()
+		return 0, err
+	}
+	out, removed, err := truncateWAL(gr, filepath.Dir(walFile), height, dryRun)
+	gr.Close()
+	group.Close()
+	if err != nil || dryRun {
+		return removed, err
+	}
+	defer os.Remove(out)
+
+	// The truncated WAL replaces the head, once the other files are removed.
+	for index := minIndex; index < maxIndex; index++ {
+		if err := os.Remove(fmt.Sprintf("%v.%03d", walFile, index)); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+	}
+	if err := os.Rename(out, walFile); err != nil {
+		return 0, err
+	}
+	return removed, nil
+}
+
+// truncateWAL writes the messages of rd up to the EndHeightMessage of height
+// to a temporary file of dir, unless dryRun, and returns its path along with
+// the number of messages left out.
+func truncateWAL(rd io.Reader, dir string, height int64, dryRun bool) (string, int, error) {
+	var (
+		out *os.File
+		enc *WALEncoder
+		err error
+	)
+	if !dryRun {
+		if out, err = os.CreateTemp(dir, ".rollback-"); err != nil {
+			return "", 0, err
+		}
+		defer out.Close()
+		enc = NewWALEncoder(out)
+	}
+
+	var (
+		dec            = NewWALDecoder(rd)
+		found          bool
+		total, removed int
+	)
+	for {
+		msg, err := dec.Decode()
+		if err == io.EOF {
+			break
+		} else if found && IsDataCorruptionError(err) {
+			// The corrupted messages are left out anyway.
+			break
+		} else if err != nil {
+			return "", 0, err
+		}
+
+		total++
+		if found {
+			removed++
+			continue
+		}
+		if enc != nil {
+			if err := enc.Encode(msg); err != nil {
+				return "", 0, err
+			}
+		}
+		if m, ok := msg.Msg.(EndHeightMessage); ok && m.Height == height {
+			found = true
+		}
+	}
+	if !found {
+		removed = total
+	}
+	if dryRun {
+		return "", removed, nil
+	}
+
+	if !found {
+		if err := out.Truncate(0); err != nil {
+			return "", 0, err
+		}
+		if _, err := out.Seek(0, io.SeekStart); err != nil {
+			return "", 0, err
+		}
+		if err := enc.Encode(&TimedWALMessage{cmttime.Now(), EndHeightMessage{height}}); err != nil {
+			return "", 0, err
+		}
+	}
+	if err := out.Sync(); err != nil {
+		return "", 0, err
+	}
+	return out.Name(), removed, out.Close()
+}
+
 // A WALEncoder writes custom-encoded WAL messages to an output stream.
 //
 // Format: 4 bytes CRC sum + 4 bytes length + arbitrary-length value
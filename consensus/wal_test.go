@@ -3,6 +3,7 @@ package consensus
 import (
 	"bytes"
 	"crypto/rand"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -175,6 +176,67 @@ func TestWALSearchForEndHeight(t *testing.T) {
 	assert.Equal(t, rs.Height, h+1, "wrong height")
 }
 
+func TestTruncateWAL(t *testing.T) {
+	writeWAL := func(t *testing.T) string {
+		walFile := filepath.Join(t.TempDir(), "wal")
+		f, err := os.Create(walFile)
+		require.NoError(t, err)
+		defer f.Close()
+		enc := NewWALEncoder(f)
+		for h := int64(0); h <= 5; h++ {
+			require.NoError(t, enc.Encode(&TimedWALMessage{cmttime.Now(), EndHeightMessage{h}}))
+			require.NoError(t, enc.Encode(&TimedWALMessage{cmttime.Now(), timeoutInfo{Height: h + 1}}))
+		}
+		return walFile
+	}
+	readWAL := func(t *testing.T, walFile string) []WALMessage {
+		f, err := os.Open(walFile)
+		require.NoError(t, err)
+		defer f.Close()
+		var msgs []WALMessage
+		dec := NewWALDecoder(f)
+		for {
+			msg, err := dec.Decode()
+			if err == io.EOF {
+				return msgs
+			}
+			require.NoError(t, err)
+			msgs = append(msgs, msg.Msg)
+		}
+	}
+
+	walFile := writeWAL(t)
+	removed, err := TruncateWAL(walFile, 3, true)
+	require.NoError(t, err)
+	assert.Equal(t, 5, removed)
+	assert.Len(t, readWAL(t, walFile), 12, "dry run")
+
+	removed, err = TruncateWAL(walFile, 3, false)
+	require.NoError(t, err)
+	assert.Equal(t, 5, removed)
+	msgs := readWAL(t, walFile)
+	require.Len(t, msgs, 7)
+	assert.Equal(t, EndHeightMessage{3}, msgs[6])
+
+	wal, err := NewWAL(walFile)
+	require.NoError(t, err)
+	gr, found, err := wal.SearchForEndHeight(3, &WALSearchOptions{})
+	require.NoError(t, err)
+	assert.True(t, found)
+	gr.Close()
+
+	// Without the end of the height, only its end is kept.
+	walFile = writeWAL(t)
+	removed, err = TruncateWAL(walFile, 7, false)
+	require.NoError(t, err)
+	assert.Equal(t, 12, removed)
+	assert.Equal(t, []WALMessage{EndHeightMessage{7}}, readWAL(t, walFile))
+
+	removed, err = TruncateWAL(filepath.Join(t.TempDir(), "wal"), 3, false)
+	require.NoError(t, err)
+	assert.Zero(t, removed)
+}
+
 func TestWALPeriodicSync(t *testing.T) {
 	walDir, err := os.MkdirTemp("", "wal")
 	require.NoError(t, err)
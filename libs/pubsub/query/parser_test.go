@@ -84,6 +84,34 @@ func TestParser(t *testing.T) {
 
 		{"hash='136E18F7E4C348B780CF873A0BF43922E5BAFA63'", true},
 		{"hash=136E18F7E4C348B780CF873A0BF43922E5BAFA63", false},
+
+		{"tm.events.type='NewBlock' OR tm.events.type='Tx'", true},
+		{"tm.events.type='NewBlock' or tm.events.type='Tx' AND tx.height=5", true},
+		{"tm.events.type='NewBlock' OR", false},
+		{"OR tm.events.type='NewBlock'", false},
+		{"(tm.events.type='NewBlock' OR tm.events.type='Tx') AND tx.height=5", true},
+		{"( tm.events.type='NewBlock' OR (tx.height=5) )", true},
+		{"(tm.events.type='NewBlock' OR tm.events.type='Tx'", false},
+		{"tm.events.type='NewBlock')", false},
+		{"()", false},
+
+		{"NOT tm.events.type='NewBlock'", true},
+		{"NOT (tx.height > 5 AND NOT slashing EXISTS)", true},
+		{"NOTE.type='NewBlock'", true},
+		{"NOT", false},
+		{"tm.events.type='NewBlock' AND NOT", false},
+
+		{"tm.events.type IN ('NewBlock', 'Tx')", true},
+		{"tx.height IN (1,2 , 3)", true},
+		{"tx.date IN (DATE 2013-05-03, DATE 2013-05-04)", true},
+		{"tx.height IN ()", false},
+		{"tx.height IN (1,)", false},
+		{"tx.height IN 1", false},
+
+		{"abci.account.name STARTS WITH 'Ig'", true},
+		{"abci.account.name starts with 'Ig'", true},
+		{"abci.account.name STARTS WITH 1", false},
+		{"abci.account.name STARTSWITH 'Ig'", false},
 	}
 
 	for _, c := range cases {
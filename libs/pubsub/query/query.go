@@ -2,6 +2,7 @@
 // subscriptions in CometBFT.
 //
 //	abci.invoice.number=22 AND abci.invoice.owner=Ivan
+//	abci.invoice.owner IN ('Ivan', 'Igor') OR NOT (abci.invoice.number < 22)
 //
 // See query.peg for the grammar, which is a https://en.wikipedia.org/wiki/Parsing_expression_grammar.
 // More: https://github.com/PhilippeSigaud/Pegged/wiki/PEG-Basics
@@ -23,18 +24,22 @@ var (
 	numRegex = regexp.MustCompile(`([0-9\.]+)`)
 )
 
-// Query holds the query string and the query parser.
+// Query holds the query string, the query parser and the expression parsed.
 type Query struct {
 	str    string
 	parser *QueryParser
+	expr   *expression
 }
 
 // Condition represents a single condition within a query and consists of composite key
-// (e.g. "tx.gas"), operator (e.g. "=") and operand (e.g. "7").
+// (e.g. "tx.gas"), operator (e.g. "=") and operand (e.g. "7"). The operand of
+// OpIn is a list of operands, and OpExists has none.
 type Condition struct {
 	CompositeKey string
 	Op           Operator
 	Operand      interface{}
+	// Whether the condition is preceded by NOT, see Query.Disjunction.
+	Negated bool
 }
 
 // New parses the given string and returns a query or error if the string is
@@ -45,7 +50,11 @@ func New(s string) (*Query, error) {
 	if err := p.Parse(); err != nil {
 		return nil, err
 	}
-	return &Query{str: s, parser: p}, nil
+	expr, err := newExpression(p.AST(), p.buffer)
+	if err != nil {
+		return nil, err
+	}
+	return &Query{str: s, parser: p, expr: expr}, nil
 }
 
 // MustParse turns the given string into a query or panics; for tests or others
@@ -82,6 +91,10 @@ const (
 	OpContains
 	// "EXISTS"; used to check if a certain event attribute is present.
 	OpExists
+	// "STARTS WITH"; used to check if a string starts with a certain prefix.
+	OpStartsWith
+	// "IN"; used to check if a value is equal to any operand of a list.
+	OpIn
 )
 
 const (
@@ -91,107 +104,250 @@ const (
 	TimeLayout = time.RFC3339
 )
 
-// Conditions returns a list of conditions. It returns an error if there is any
-// error with the provided grammar in the Query.
-func (q *Query) Conditions() ([]Condition, error) {
+// maxConjunctions bounds the number of conjunctions a query may expand to
+// when rewritten as a disjunction of conjunctions.
+const maxConjunctions = 64
+
+// expression is a node of the syntax tree of a query: either a condition or
+// the conjunction, disjunction or negation of its operands.
+type expression struct {
+	op        exprOp
+	operands  []*expression
+	condition Condition
+}
+
+type exprOp uint8
+
+const (
+	exprCondition exprOp = iota
+	exprAnd
+	exprOr
+	exprNot
+)
+
+// newExpression builds the expression of the given node of the syntax tree.
+func newExpression(node *node32, buffer []rune) (*expression, error) {
+	switch node.pegRule {
+	case rulee:
+		return newExpression(node.up, buffer)
+
+	case ruledisjunction, ruleconjunction:
+		e := &expression{op: exprAnd}
+		if node.pegRule == ruledisjunction {
+			e.op = exprOr
+		}
+		for child := node.up; child != nil; child = child.next {
+			if child.pegRule == ruleand || child.pegRule == ruleor {
+				continue
+			}
+			operand, err := newExpression(child, buffer)
+			if err != nil {
+				return nil, err
+			}
+			e.operands = append(e.operands, operand)
+		}
+		if len(e.operands) == 1 {
+			return e.operands[0], nil
+		}
+		return e, nil
+
+	case ruleterm:
+		// NOT term, ( disjunction ) or condition
+		if node.up.pegRule == rulenot {
+			operand, err := newExpression(node.up.next, buffer)
+			if err != nil {
+				return nil, err
+			}
+			return &expression{op: exprNot, operands: []*expression{operand}}, nil
+		}
+		return newExpression(node.up, buffer)
+
+	case rulecondition:
+		c, err := newCondition(node, buffer)
+		if err != nil {
+			return nil, err
+		}
+		return &expression{op: exprCondition, condition: c}, nil
+	}
+
+	return nil, fmt.Errorf("unexpected %v in the syntax tree (should never happen if the grammar is correct)", rul3s[node.pegRule])
+}
+
+// newCondition builds the condition of the given node of the syntax tree.
+// Its children must be in the following order: tag ("tx.gas") -> operator
+// ("=") -> operand(s) ("7").
+func newCondition(node *node32, buffer []rune) (Condition, error) {
 	var (
-		eventAttr string
-		op        Operator
+		c        Condition
+		operands []interface{}
 	)
 
-	conditions := make([]Condition, 0)
-	buffer, begin, end := q.parser.Buffer, 0, 0
-
-	// tokens must be in the following order: tag ("tx.gas") -> operator ("=") -> operand ("7")
-	for token := range q.parser.Tokens() {
-		switch token.pegRule {
-		case rulePegText:
-			begin, end = int(token.begin), int(token.end)
+	for child := node.up; child != nil; child = child.next {
+		text := string(buffer[child.begin:child.end])
 
+		switch child.pegRule {
 		case ruletag:
-			eventAttr = buffer[begin:end]
+			c.CompositeKey = text
 
 		case rulele:
-			op = OpLessEqual
+			c.Op = OpLessEqual
 
 		case rulege:
-			op = OpGreaterEqual
+			c.Op = OpGreaterEqual
 
 		case rulel:
-			op = OpLess
+			c.Op = OpLess
 
 		case ruleg:
-			op = OpGreater
+			c.Op = OpGreater
 
 		case ruleequal:
-			op = OpEqual
+			c.Op = OpEqual
 
 		case rulecontains:
-			op = OpContains
+			c.Op = OpContains
+
+		case rulestartswith:
+			c.Op = OpStartsWith
+
+		case rulein:
+			c.Op = OpIn
 
 		case ruleexists:
-			op = OpExists
-			conditions = append(conditions, Condition{eventAttr, op, nil})
+			c.Op = OpExists
 
 		case rulevalue:
 			// strip single quotes from value (i.e. "'NewBlock'" -> "NewBlock")
-			valueWithoutSingleQuotes := buffer[begin+1 : end-1]
-			conditions = append(conditions, Condition{eventAttr, op, valueWithoutSingleQuotes})
+			operands = append(operands, text[1:len(text)-1])
 
 		case rulenumber:
-			number := buffer[begin:end]
-			if strings.ContainsAny(number, ".") { // if it looks like a floating-point number
-				value, err := strconv.ParseFloat(number, 64)
+			if strings.ContainsAny(text, ".") { // if it looks like a floating-point number
+				value, err := strconv.ParseFloat(text, 64)
 				if err != nil {
-					err = fmt.Errorf(
+					return c, fmt.Errorf(
 						"got %v while trying to parse %s as float64 (should never happen if the grammar is correct)",
-						err, number,
+						err, text,
 					)
-					return nil, err
 				}
-
-				conditions = append(conditions, Condition{eventAttr, op, value})
+				operands = append(operands, value)
 			} else {
 				valueBig := new(big.Int)
-				_, ok := valueBig.SetString(number, 10)
+				_, ok := valueBig.SetString(text, 10)
 				if !ok {
-					err := fmt.Errorf(
+					return c, fmt.Errorf(
 						"problem parsing %s as bigint (should never happen if the grammar is correct)",
-						number,
+						text,
 					)
-					return nil, err
 				}
-				conditions = append(conditions, Condition{eventAttr, op, valueBig})
-
+				operands = append(operands, valueBig)
 			}
 
-		case ruletime:
-			value, err := time.Parse(TimeLayout, buffer[begin:end])
+		case ruletime, ruledate:
+			// strip the "TIME " or "DATE " prefix, outside of the text captured
+			text = string(buffer[child.up.begin:child.up.end])
+			layout := TimeLayout
+			if child.pegRule == ruledate {
+				layout = DateLayout
+			}
+			value, err := time.Parse(layout, text)
 			if err != nil {
-				err = fmt.Errorf(
-					"got %v while trying to parse %s as time.Time / RFC3339 (should never happen if the grammar is correct)",
-					err, buffer[begin:end],
+				return c, fmt.Errorf(
+					"got %v while trying to parse %s as time.Time / '%s' (should never happen if the grammar is correct)",
+					err, text, layout,
 				)
-				return nil, err
 			}
+			operands = append(operands, value)
+		}
+	}
+
+	switch {
+	case c.Op == OpIn:
+		c.Operand = operands
+	case len(operands) > 0:
+		c.Operand = operands[0]
+	}
+	return c, nil
+}
 
-			conditions = append(conditions, Condition{eventAttr, op, value})
+// Disjunction returns the query rewritten as a disjunction of conjunctions of
+// conditions, some of which may be negated: the query matches if all the
+// conditions of any of the conjunctions do. It returns an error if the query
+// expands to too many conjunctions.
+//
+// For example, "a = 1 AND NOT (b = 2 OR c = 3)" is rewritten as
+// [[a = 1, NOT b = 2, NOT c = 3]], and "(a = 1 OR b = 2) AND c = 3" as
+// [[a = 1, c = 3], [b = 2, c = 3]].
+func (q *Query) Disjunction() ([][]Condition, error) {
+	return q.expr.disjunction(false)
+}
 
-		case ruledate:
-			value, err := time.Parse("2006-01-02", buffer[begin:end])
+func (e *expression) disjunction(negated bool) ([][]Condition, error) {
+	switch e.op {
+	case exprCondition:
+		c := e.condition
+		c.Negated = negated
+		return [][]Condition{{c}}, nil
+
+	case exprNot:
+		return e.operands[0].disjunction(!negated)
+	}
+
+	// NOT (a AND b) is rewritten as NOT a OR NOT b, and NOT (a OR b) as
+	// NOT a AND NOT b.
+	if (e.op == exprOr) != negated {
+		var conjunctions [][]Condition
+		for _, operand := range e.operands {
+			d, err := operand.disjunction(negated)
 			if err != nil {
-				err = fmt.Errorf(
-					"got %v while trying to parse %s as time.Time / '2006-01-02' (should never happen if the grammar is correct)",
-					err, buffer[begin:end],
-				)
 				return nil, err
 			}
+			conjunctions = append(conjunctions, d...)
+			if len(conjunctions) > maxConjunctions {
+				return nil, fmt.Errorf("query expands to more than %d conjunctions", maxConjunctions)
+			}
+		}
+		return conjunctions, nil
+	}
 
-			conditions = append(conditions, Condition{eventAttr, op, value})
+	conjunctions := [][]Condition{nil}
+	for _, operand := range e.operands {
+		d, err := operand.disjunction(negated)
+		if err != nil {
+			return nil, err
+		}
+		if len(conjunctions)*len(d) > maxConjunctions {
+			return nil, fmt.Errorf("query expands to more than %d conjunctions", maxConjunctions)
+		}
+		product := make([][]Condition, 0, len(conjunctions)*len(d))
+		for _, left := range conjunctions {
+			for _, right := range d {
+				conjunction := make([]Condition, 0, len(left)+len(right))
+				conjunction = append(conjunction, left...)
+				product = append(product, append(conjunction, right...))
+			}
 		}
+		conjunctions = product
 	}
+	return conjunctions, nil
+}
 
-	return conditions, nil
+// Conditions returns a list of conditions, all of which must match. It
+// returns an error if the query has alternatives or negations, which require
+// Disjunction instead.
+func (q *Query) Conditions() ([]Condition, error) {
+	conjunctions, err := q.Disjunction()
+	if err != nil {
+		return nil, err
+	}
+	if len(conjunctions) != 1 {
+		return nil, fmt.Errorf("query %q is not a conjunction of conditions", q.str)
+	}
+	for _, c := range conjunctions[0] {
+		if c.Negated {
+			return nil, fmt.Errorf("query %q is not a conjunction of conditions", q.str)
+		}
+	}
+	return conjunctions[0], nil
 }
 
 // Matches returns true if the query matches against any event in the given set
@@ -200,167 +356,90 @@ func (q *Query) Conditions() ([]Condition, error) {
 // any attempted event match returns an error.
 //
 // For example, query "name=John" matches events = {"name": ["John", "Eric"]}.
+// A negated condition matches if the condition does not, e.g. query
+// "NOT name=John" matches events = {"name": ["Eric"]} and {"age": ["32"]}.
 // More examples could be found in parser_test.go and query_test.go.
 func (q *Query) Matches(events map[string][]string) (bool, error) {
 	if len(events) == 0 {
 		return false, nil
 	}
 
-	var (
-		eventAttr string
-		op        Operator
-	)
-
-	buffer, begin, end := q.parser.Buffer, 0, 0
-
-	// tokens must be in the following order:
-
-	// tag ("tx.gas") -> operator ("=") -> operand ("7")
-	for token := range q.parser.Tokens() {
-		switch token.pegRule {
-		case rulePegText:
-			begin, end = int(token.begin), int(token.end)
-
-		case ruletag:
-			eventAttr = buffer[begin:end]
-
-		case rulele:
-			op = OpLessEqual
-
-		case rulege:
-			op = OpGreaterEqual
-
-		case rulel:
-			op = OpLess
-
-		case ruleg:
-			op = OpGreater
-
-		case ruleequal:
-			op = OpEqual
-
-		case rulecontains:
-			op = OpContains
-		case ruleexists:
-			op = OpExists
-			if strings.Contains(eventAttr, ".") {
-				// Searching for a full "type.attribute" event.
-				_, ok := events[eventAttr]
-				if !ok {
-					return false, nil
-				}
-			} else {
-				foundEvent := false
-
-			loop:
-				for compositeKey := range events {
-					if strings.Index(compositeKey, eventAttr) == 0 {
-						foundEvent = true
-						break loop
-					}
-				}
-				if !foundEvent {
-					return false, nil
-				}
-			}
-
-		case rulevalue:
-			// strip single quotes from value (i.e. "'NewBlock'" -> "NewBlock")
-			valueWithoutSingleQuotes := buffer[begin+1 : end-1]
-
-			// see if the triplet (event attribute, operator, operand) matches any event
-			// "tx.gas", "=", "7", { "tx.gas": 7, "tx.ID": "4AE393495334" }
-			match, err := match(eventAttr, op, reflect.ValueOf(valueWithoutSingleQuotes), events)
-			if err != nil {
-				return false, err
-			}
-
-			if !match {
-				return false, nil
-			}
-
-		case rulenumber:
-			number := buffer[begin:end]
-			if strings.ContainsAny(number, ".") { // if it looks like a floating-point number
-				value, err := strconv.ParseFloat(number, 64)
-				if err != nil {
-					err = fmt.Errorf(
-						"got %v while trying to parse %s as float64 (should never happen if the grammar is correct)",
-						err, number,
-					)
-					return false, err
-				}
-
-				match, err := match(eventAttr, op, reflect.ValueOf(value), events)
-				if err != nil {
-					return false, err
-				}
-
-				if !match {
-					return false, nil
-				}
-			} else {
-				value := new(big.Int)
-				_, ok := value.SetString(number, 10)
-				if !ok {
-					err := fmt.Errorf(
-						"problem parsing %s as bigInt (should never happen if the grammar is correct)",
-						number,
-					)
-					return false, err
-				}
+	return q.expr.matches(events)
+}
 
-				match, err := match(eventAttr, op, reflect.ValueOf(value), events)
-				if err != nil {
-					return false, err
-				}
+func (e *expression) matches(events map[string][]string) (bool, error) {
+	switch e.op {
+	case exprCondition:
+		return e.condition.matches(events)
 
-				if !match {
-					return false, nil
-				}
-			}
-
-		case ruletime:
-			value, err := time.Parse(TimeLayout, buffer[begin:end])
-			if err != nil {
-				err = fmt.Errorf(
-					"got %v while trying to parse %s as time.Time / RFC3339 (should never happen if the grammar is correct)",
-					err, buffer[begin:end],
-				)
-				return false, err
-			}
+	case exprNot:
+		match, err := e.operands[0].matches(events)
+		return !match, err
+	}
 
-			match, err := match(eventAttr, op, reflect.ValueOf(value), events)
-			if err != nil {
-				return false, err
-			}
+	// stop at the first operand deciding the result
+	for _, operand := range e.operands {
+		match, err := operand.matches(events)
+		if err != nil {
+			return false, err
+		}
+		if match == (e.op == exprOr) {
+			return match, nil
+		}
+	}
+	return e.op == exprAnd, nil
+}
 
-			if !match {
-				return false, nil
+// matches returns true if the condition, ignoring Negated, matches the events.
+func (c Condition) matches(events map[string][]string) (bool, error) {
+	switch c.Op {
+	case OpExists:
+		if strings.Contains(c.CompositeKey, ".") {
+			// Searching for a full "type.attribute" event.
+			_, ok := events[c.CompositeKey]
+			return ok, nil
+		}
+		for compositeKey := range events {
+			if strings.Index(compositeKey, c.CompositeKey) == 0 {
+				return true, nil
 			}
+		}
+		return false, nil
 
-		case ruledate:
-			value, err := time.Parse("2006-01-02", buffer[begin:end])
-			if err != nil {
-				err = fmt.Errorf(
-					"got %v while trying to parse %s as time.Time / '2006-01-02' (should never happen if the grammar is correct)",
-					err, buffer[begin:end],
-				)
-				return false, err
+	case OpIn:
+		// see if any operand is equal to a value of the attribute
+		for _, operand := range c.Operand.([]interface{}) {
+			match, err := match(c.CompositeKey, OpEqual, reflect.ValueOf(operand), events)
+			if err != nil || match {
+				return match, err
 			}
+		}
+		return false, nil
+	}
 
-			match, err := match(eventAttr, op, reflect.ValueOf(value), events)
-			if err != nil {
-				return false, err
-			}
+	// see if the triplet (event attribute, operator, operand) matches any event
+	// "tx.gas", "=", "7", { "tx.gas": 7, "tx.ID": "4AE393495334" }
+	return match(c.CompositeKey, c.Op, reflect.ValueOf(c.Operand), events)
+}
 
-			if !match {
-				return false, nil
+// MatchesValue returns true if value, a value of the attribute with the
+// composite key of the condition, matches the condition, ignoring Negated. It
+// returns an error if value can't be compared to the operand.
+func (c Condition) MatchesValue(value string) (bool, error) {
+	switch c.Op {
+	case OpExists:
+		return true, nil
+
+	case OpIn:
+		for _, operand := range c.Operand.([]interface{}) {
+			match, err := matchValue(value, OpEqual, reflect.ValueOf(operand))
+			if err != nil || match {
+				return match, err
 			}
 		}
+		return false, nil
 	}
-
-	return true, nil
+	return matchValue(value, c.Op, reflect.ValueOf(c.Operand))
 }
 
 // match returns true if the given triplet (attribute, operator, operand) matches
@@ -512,6 +591,8 @@ func matchValue(value string, op Operator, operand reflect.Value) (bool, error)
 			return value == operand.String(), nil
 		case OpContains:
 			return strings.Contains(value, operand.String()), nil
+		case OpStartsWith:
+			return strings.HasPrefix(value, operand.String()), nil
 		}
 
 	default:
@@ -15,6 +15,9 @@ type pegRule uint8
 const (
 	ruleUnknown pegRule = iota
 	rulee
+	ruledisjunction
+	ruleconjunction
+	ruleterm
 	rulecondition
 	ruletag
 	rulevalue
@@ -26,8 +29,12 @@ const (
 	rulemonth
 	ruleday
 	ruleand
+	ruleor
+	rulenot
 	ruleequal
 	rulecontains
+	rulestartswith
+	rulein
 	ruleexists
 	rulele
 	rulege
@@ -43,6 +50,9 @@ const (
 var rul3s = [...]string{
 	"Unknown",
 	"e",
+	"disjunction",
+	"conjunction",
+	"term",
 	"condition",
 	"tag",
 	"value",
@@ -54,8 +64,12 @@ var rul3s = [...]string{
 	"month",
 	"day",
 	"and",
+	"or",
+	"not",
 	"equal",
 	"contains",
+	"startswith",
+	"in",
 	"exists",
 	"le",
 	"ge",
@@ -355,7 +369,7 @@ func (t *tokens32) Expand(index int) {
 type QueryParser struct {
 	Buffer string
 	buffer []rune
-	rules  [21]func() bool
+	rules  [28]func() bool
 	Parse  func(rule ...int) error
 	Reset  func()
 	Pretty bool
@@ -493,7 +507,7 @@ func (p *QueryParser) Init() {
 
 	_rules = [...]func() bool{
 		nil,
-		/* 0 e <- <('"' condition (' '+ and ' '+ condition)* '"' !.)> */
+		/* 0 e <- <('"' disjunction '"' !.)> */
 		func() bool {
 			position0, tokenIndex0, depth0 := position, tokenIndex, depth
 			{
@@ -503,1367 +517,2094 @@ func (p *QueryParser) Init() {
 					goto l0
 				}
 				position++
-				if !_rules[rulecondition]() {
+				if !_rules[ruledisjunction]() {
 					goto l0
 				}
-			l2:
+				if buffer[position] != rune('"') {
+					goto l0
+				}
+				position++
+				{
+					position2, tokenIndex2, depth2 := position, tokenIndex, depth
+					if !matchDot() {
+						goto l2
+					}
+					goto l0
+				l2:
+					position, tokenIndex, depth = position2, tokenIndex2, depth2
+				}
+				depth--
+				add(rulee, position1)
+			}
+			return true
+		l0:
+			position, tokenIndex, depth = position0, tokenIndex0, depth0
+			return false
+		},
+		/* 1 disjunction <- <(conjunction (' '+ or ' '+ conjunction)*)> */
+		func() bool {
+			position3, tokenIndex3, depth3 := position, tokenIndex, depth
+			{
+				position4 := position
+				depth++
+				if !_rules[ruleconjunction]() {
+					goto l3
+				}
+			l5:
 				{
-					position3, tokenIndex3, depth3 := position, tokenIndex, depth
+					position6, tokenIndex6, depth6 := position, tokenIndex, depth
 					if buffer[position] != rune(' ') {
-						goto l3
+						goto l6
 					}
 					position++
-				l4:
+				l7:
 					{
-						position5, tokenIndex5, depth5 := position, tokenIndex, depth
+						position8, tokenIndex8, depth8 := position, tokenIndex, depth
 						if buffer[position] != rune(' ') {
-							goto l5
+							goto l8
 						}
 						position++
-						goto l4
-					l5:
-						position, tokenIndex, depth = position5, tokenIndex5, depth5
+						goto l7
+					l8:
+						position, tokenIndex, depth = position8, tokenIndex8, depth8
 					}
-					{
-						position6 := position
-						depth++
-						{
-							position7, tokenIndex7, depth7 := position, tokenIndex, depth
-							if buffer[position] != rune('a') {
-								goto l8
-							}
-							position++
-							goto l7
-						l8:
-							position, tokenIndex, depth = position7, tokenIndex7, depth7
-							if buffer[position] != rune('A') {
-								goto l3
-							}
-							position++
-						}
-					l7:
-						{
-							position9, tokenIndex9, depth9 := position, tokenIndex, depth
-							if buffer[position] != rune('n') {
-								goto l10
-							}
-							position++
-							goto l9
-						l10:
-							position, tokenIndex, depth = position9, tokenIndex9, depth9
-							if buffer[position] != rune('N') {
-								goto l3
-							}
-							position++
-						}
-					l9:
-						{
-							position11, tokenIndex11, depth11 := position, tokenIndex, depth
-							if buffer[position] != rune('d') {
-								goto l12
-							}
-							position++
-							goto l11
-						l12:
-							position, tokenIndex, depth = position11, tokenIndex11, depth11
-							if buffer[position] != rune('D') {
-								goto l3
-							}
-							position++
-						}
-					l11:
-						depth--
-						add(ruleand, position6)
+					if !_rules[ruleor]() {
+						goto l6
 					}
 					if buffer[position] != rune(' ') {
-						goto l3
+						goto l6
 					}
 					position++
-				l13:
+				l9:
 					{
-						position14, tokenIndex14, depth14 := position, tokenIndex, depth
+						position10, tokenIndex10, depth10 := position, tokenIndex, depth
 						if buffer[position] != rune(' ') {
-							goto l14
+							goto l10
 						}
 						position++
-						goto l13
-					l14:
-						position, tokenIndex, depth = position14, tokenIndex14, depth14
+						goto l9
+					l10:
+						position, tokenIndex, depth = position10, tokenIndex10, depth10
 					}
-					if !_rules[rulecondition]() {
-						goto l3
+					if !_rules[ruleconjunction]() {
+						goto l6
 					}
-					goto l2
-				l3:
-					position, tokenIndex, depth = position3, tokenIndex3, depth3
+					goto l5
+				l6:
+					position, tokenIndex, depth = position6, tokenIndex6, depth6
 				}
-				if buffer[position] != rune('"') {
-					goto l0
+				depth--
+				add(ruledisjunction, position4)
+			}
+			return true
+		l3:
+			position, tokenIndex, depth = position3, tokenIndex3, depth3
+			return false
+		},
+		/* 2 conjunction <- <(term (' '+ and ' '+ term)*)> */
+		func() bool {
+			position11, tokenIndex11, depth11 := position, tokenIndex, depth
+			{
+				position12 := position
+				depth++
+				if !_rules[ruleterm]() {
+					goto l11
 				}
-				position++
+			l13:
 				{
-					position15, tokenIndex15, depth15 := position, tokenIndex, depth
-					if !matchDot() {
-						goto l15
+					position14, tokenIndex14, depth14 := position, tokenIndex, depth
+					if buffer[position] != rune(' ') {
+						goto l14
 					}
-					goto l0
+					position++
 				l15:
-					position, tokenIndex, depth = position15, tokenIndex15, depth15
+					{
+						position16, tokenIndex16, depth16 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l16
+						}
+						position++
+						goto l15
+					l16:
+						position, tokenIndex, depth = position16, tokenIndex16, depth16
+					}
+					if !_rules[ruleand]() {
+						goto l14
+					}
+					if buffer[position] != rune(' ') {
+						goto l14
+					}
+					position++
+				l17:
+					{
+						position18, tokenIndex18, depth18 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l18
+						}
+						position++
+						goto l17
+					l18:
+						position, tokenIndex, depth = position18, tokenIndex18, depth18
+					}
+					if !_rules[ruleterm]() {
+						goto l14
+					}
+					goto l13
+				l14:
+					position, tokenIndex, depth = position14, tokenIndex14, depth14
 				}
 				depth--
-				add(rulee, position1)
+				add(ruleconjunction, position12)
 			}
 			return true
-		l0:
-			position, tokenIndex, depth = position0, tokenIndex0, depth0
+		l11:
+			position, tokenIndex, depth = position11, tokenIndex11, depth11
 			return false
 		},
-		/* 1 condition <- <(tag ' '* ((le ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number))) / (ge ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number))) / ((&('E' | 'e') exists) | (&('=') (equal ' '* ((&('\'') value) | (&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('>') (g ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('<') (l ' '* ((&('D' | 'd') date) | (&('T' | 't') time) | (&('0' | '1' | '2' | '3' | '4' | '5' | '6' | '7' | '8' | '9') number)))) | (&('C' | 'c') (contains ' '* value)))))> */
+		/* 3 term <- <((not ' '+ term) / ('(' ' '* disjunction ' '* ')') / condition)> */
 		func() bool {
-			position16, tokenIndex16, depth16 := position, tokenIndex, depth
+			position19, tokenIndex19, depth19 := position, tokenIndex, depth
 			{
-				position17 := position
+				position20 := position
 				depth++
 				{
-					position18 := position
-					depth++
+					position21, tokenIndex21, depth21 := position, tokenIndex, depth
+					if !_rules[rulenot]() {
+						goto l22
+					}
+					if buffer[position] != rune(' ') {
+						goto l22
+					}
+					position++
+				l23:
 					{
-						position19 := position
-						depth++
-						{
-							position22, tokenIndex22, depth22 := position, tokenIndex, depth
-							{
-								switch buffer[position] {
-								case '<':
-									if buffer[position] != rune('<') {
-										goto l22
-									}
-									position++
-									break
-								case '>':
-									if buffer[position] != rune('>') {
-										goto l22
-									}
-									position++
-									break
-								case '=':
-									if buffer[position] != rune('=') {
-										goto l22
-									}
-									position++
-									break
-								case '\'':
-									if buffer[position] != rune('\'') {
-										goto l22
-									}
-									position++
-									break
-								case '"':
-									if buffer[position] != rune('"') {
-										goto l22
-									}
-									position++
-									break
-								case ')':
-									if buffer[position] != rune(')') {
-										goto l22
-									}
-									position++
-									break
-								case '(':
-									if buffer[position] != rune('(') {
-										goto l22
-									}
-									position++
-									break
-								case '\\':
-									if buffer[position] != rune('\\') {
-										goto l22
-									}
-									position++
-									break
-								case '\r':
-									if buffer[position] != rune('\r') {
-										goto l22
-									}
-									position++
-									break
-								case '\n':
-									if buffer[position] != rune('\n') {
-										goto l22
-									}
-									position++
-									break
-								case '\t':
-									if buffer[position] != rune('\t') {
-										goto l22
-									}
-									position++
-									break
-								default:
-									if buffer[position] != rune(' ') {
-										goto l22
-									}
-									position++
-									break
-								}
-							}
-
-							goto l16
-						l22:
-							position, tokenIndex, depth = position22, tokenIndex22, depth22
+						position24, tokenIndex24, depth24 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l24
 						}
-						if !matchDot() {
-							goto l16
+						position++
+						goto l23
+					l24:
+						position, tokenIndex, depth = position24, tokenIndex24, depth24
+					}
+					if !_rules[ruleterm]() {
+						goto l22
+					}
+					goto l21
+				l22:
+					position, tokenIndex, depth = position21, tokenIndex21, depth21
+					if buffer[position] != rune('(') {
+						goto l25
+					}
+					position++
+				l26:
+					{
+						position27, tokenIndex27, depth27 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l27
 						}
-					l20:
-						{
-							position21, tokenIndex21, depth21 := position, tokenIndex, depth
-							{
-								position24, tokenIndex24, depth24 := position, tokenIndex, depth
-								{
-									switch buffer[position] {
-									case '<':
-										if buffer[position] != rune('<') {
-											goto l24
-										}
-										position++
-										break
-									case '>':
-										if buffer[position] != rune('>') {
-											goto l24
-										}
-										position++
-										break
-									case '=':
-										if buffer[position] != rune('=') {
-											goto l24
-										}
-										position++
-										break
-									case '\'':
-										if buffer[position] != rune('\'') {
-											goto l24
-										}
-										position++
-										break
-									case '"':
-										if buffer[position] != rune('"') {
-											goto l24
-										}
-										position++
-										break
-									case ')':
-										if buffer[position] != rune(')') {
-											goto l24
-										}
-										position++
-										break
-									case '(':
-										if buffer[position] != rune('(') {
-											goto l24
-										}
-										position++
-										break
-									case '\\':
-										if buffer[position] != rune('\\') {
-											goto l24
-										}
-										position++
-										break
-									case '\r':
-										if buffer[position] != rune('\r') {
-											goto l24
-										}
-										position++
-										break
-									case '\n':
-										if buffer[position] != rune('\n') {
-											goto l24
-										}
-										position++
-										break
-									case '\t':
-										if buffer[position] != rune('\t') {
-											goto l24
-										}
-										position++
-										break
-									default:
-										if buffer[position] != rune(' ') {
-											goto l24
-										}
-										position++
-										break
-									}
-								}
-
-								goto l21
-							l24:
-								position, tokenIndex, depth = position24, tokenIndex24, depth24
-							}
-							if !matchDot() {
-								goto l21
-							}
-							goto l20
-						l21:
-							position, tokenIndex, depth = position21, tokenIndex21, depth21
+						position++
+						goto l26
+					l27:
+						position, tokenIndex, depth = position27, tokenIndex27, depth27
+					}
+					if !_rules[ruledisjunction]() {
+						goto l25
+					}
+				l28:
+					{
+						position29, tokenIndex29, depth29 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l29
 						}
-						depth--
-						add(rulePegText, position19)
+						position++
+						goto l28
+					l29:
+						position, tokenIndex, depth = position29, tokenIndex29, depth29
 					}
-					depth--
-					add(ruletag, position18)
+					if buffer[position] != rune(')') {
+						goto l25
+					}
+					position++
+					goto l21
+				l25:
+					position, tokenIndex, depth = position21, tokenIndex21, depth21
+					if !_rules[rulecondition]() {
+						goto l19
+					}
+				}
+			l21:
+				depth--
+				add(ruleterm, position20)
+			}
+			return true
+		l19:
+			position, tokenIndex, depth = position19, tokenIndex19, depth19
+			return false
+		},
+		/* 4 condition <- <(tag ' '* ((le ' '* (number / time / date)) / (ge ' '* (number / time / date)) / (l ' '* (number / time / date)) / (g ' '* (number / time / date)) / (equal ' '* (number / time / date / value)) / (contains ' '* value) / (startswith ' '* value) / (in ' '* '(' ' '* (number / time / date / value) (' '* ',' ' '* (number / time / date / value))* ' '* ')') / exists))> */
+		func() bool {
+			position30, tokenIndex30, depth30 := position, tokenIndex, depth
+			{
+				position31 := position
+				depth++
+				if !_rules[ruletag]() {
+					goto l30
 				}
-			l26:
+			l32:
 				{
-					position27, tokenIndex27, depth27 := position, tokenIndex, depth
+					position33, tokenIndex33, depth33 := position, tokenIndex, depth
 					if buffer[position] != rune(' ') {
-						goto l27
+						goto l33
 					}
 					position++
-					goto l26
-				l27:
-					position, tokenIndex, depth = position27, tokenIndex27, depth27
+					goto l32
+				l33:
+					position, tokenIndex, depth = position33, tokenIndex33, depth33
 				}
 				{
-					position28, tokenIndex28, depth28 := position, tokenIndex, depth
+					position34, tokenIndex34, depth34 := position, tokenIndex, depth
+					if !_rules[rulele]() {
+						goto l35
+					}
+				l36:
 					{
-						position30 := position
-						depth++
-						if buffer[position] != rune('<') {
-							goto l29
+						position37, tokenIndex37, depth37 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l37
 						}
 						position++
-						if buffer[position] != rune('=') {
-							goto l29
+						goto l36
+					l37:
+						position, tokenIndex, depth = position37, tokenIndex37, depth37
+					}
+					{
+						position38, tokenIndex38, depth38 := position, tokenIndex, depth
+						if !_rules[rulenumber]() {
+							goto l39
+						}
+						goto l38
+					l39:
+						position, tokenIndex, depth = position38, tokenIndex38, depth38
+						if !_rules[ruletime]() {
+							goto l40
+						}
+						goto l38
+					l40:
+						position, tokenIndex, depth = position38, tokenIndex38, depth38
+						if !_rules[ruledate]() {
+							goto l35
+						}
+					}
+				l38:
+					goto l34
+				l35:
+					position, tokenIndex, depth = position34, tokenIndex34, depth34
+					if !_rules[rulege]() {
+						goto l41
+					}
+				l42:
+					{
+						position43, tokenIndex43, depth43 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l43
 						}
 						position++
-						depth--
-						add(rulele, position30)
+						goto l42
+					l43:
+						position, tokenIndex, depth = position43, tokenIndex43, depth43
 					}
-				l31:
 					{
-						position32, tokenIndex32, depth32 := position, tokenIndex, depth
+						position44, tokenIndex44, depth44 := position, tokenIndex, depth
+						if !_rules[rulenumber]() {
+							goto l45
+						}
+						goto l44
+					l45:
+						position, tokenIndex, depth = position44, tokenIndex44, depth44
+						if !_rules[ruletime]() {
+							goto l46
+						}
+						goto l44
+					l46:
+						position, tokenIndex, depth = position44, tokenIndex44, depth44
+						if !_rules[ruledate]() {
+							goto l41
+						}
+					}
+				l44:
+					goto l34
+				l41:
+					position, tokenIndex, depth = position34, tokenIndex34, depth34
+					if !_rules[rulel]() {
+						goto l47
+					}
+				l48:
+					{
+						position49, tokenIndex49, depth49 := position, tokenIndex, depth
 						if buffer[position] != rune(' ') {
-							goto l32
+							goto l49
 						}
 						position++
-						goto l31
-					l32:
-						position, tokenIndex, depth = position32, tokenIndex32, depth32
+						goto l48
+					l49:
+						position, tokenIndex, depth = position49, tokenIndex49, depth49
 					}
 					{
-						switch buffer[position] {
-						case 'D', 'd':
-							if !_rules[ruledate]() {
-								goto l29
-							}
-							break
-						case 'T', 't':
-							if !_rules[ruletime]() {
-								goto l29
-							}
-							break
-						default:
-							if !_rules[rulenumber]() {
-								goto l29
-							}
-							break
+						position50, tokenIndex50, depth50 := position, tokenIndex, depth
+						if !_rules[rulenumber]() {
+							goto l51
+						}
+						goto l50
+					l51:
+						position, tokenIndex, depth = position50, tokenIndex50, depth50
+						if !_rules[ruletime]() {
+							goto l52
+						}
+						goto l50
+					l52:
+						position, tokenIndex, depth = position50, tokenIndex50, depth50
+						if !_rules[ruledate]() {
+							goto l47
 						}
 					}
-
-					goto l28
-				l29:
-					position, tokenIndex, depth = position28, tokenIndex28, depth28
+				l50:
+					goto l34
+				l47:
+					position, tokenIndex, depth = position34, tokenIndex34, depth34
+					if !_rules[ruleg]() {
+						goto l53
+					}
+				l54:
 					{
-						position35 := position
-						depth++
-						if buffer[position] != rune('>') {
-							goto l34
+						position55, tokenIndex55, depth55 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l55
 						}
 						position++
-						if buffer[position] != rune('=') {
-							goto l34
+						goto l54
+					l55:
+						position, tokenIndex, depth = position55, tokenIndex55, depth55
+					}
+					{
+						position56, tokenIndex56, depth56 := position, tokenIndex, depth
+						if !_rules[rulenumber]() {
+							goto l57
+						}
+						goto l56
+					l57:
+						position, tokenIndex, depth = position56, tokenIndex56, depth56
+						if !_rules[ruletime]() {
+							goto l58
+						}
+						goto l56
+					l58:
+						position, tokenIndex, depth = position56, tokenIndex56, depth56
+						if !_rules[ruledate]() {
+							goto l53
+						}
+					}
+				l56:
+					goto l34
+				l53:
+					position, tokenIndex, depth = position34, tokenIndex34, depth34
+					if !_rules[ruleequal]() {
+						goto l59
+					}
+				l60:
+					{
+						position61, tokenIndex61, depth61 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l61
 						}
 						position++
-						depth--
-						add(rulege, position35)
+						goto l60
+					l61:
+						position, tokenIndex, depth = position61, tokenIndex61, depth61
 					}
-				l36:
 					{
-						position37, tokenIndex37, depth37 := position, tokenIndex, depth
+						position62, tokenIndex62, depth62 := position, tokenIndex, depth
+						if !_rules[rulenumber]() {
+							goto l63
+						}
+						goto l62
+					l63:
+						position, tokenIndex, depth = position62, tokenIndex62, depth62
+						if !_rules[ruletime]() {
+							goto l64
+						}
+						goto l62
+					l64:
+						position, tokenIndex, depth = position62, tokenIndex62, depth62
+						if !_rules[ruledate]() {
+							goto l65
+						}
+						goto l62
+					l65:
+						position, tokenIndex, depth = position62, tokenIndex62, depth62
+						if !_rules[rulevalue]() {
+							goto l59
+						}
+					}
+				l62:
+					goto l34
+				l59:
+					position, tokenIndex, depth = position34, tokenIndex34, depth34
+					if !_rules[rulecontains]() {
+						goto l66
+					}
+				l67:
+					{
+						position68, tokenIndex68, depth68 := position, tokenIndex, depth
 						if buffer[position] != rune(' ') {
-							goto l37
+							goto l68
 						}
 						position++
-						goto l36
-					l37:
-						position, tokenIndex, depth = position37, tokenIndex37, depth37
+						goto l67
+					l68:
+						position, tokenIndex, depth = position68, tokenIndex68, depth68
+					}
+					if !_rules[rulevalue]() {
+						goto l66
 					}
+					goto l34
+				l66:
+					position, tokenIndex, depth = position34, tokenIndex34, depth34
+					if !_rules[rulestartswith]() {
+						goto l69
+					}
+				l70:
 					{
-						switch buffer[position] {
-						case 'D', 'd':
-							if !_rules[ruledate]() {
-								goto l34
-							}
-							break
-						case 'T', 't':
-							if !_rules[ruletime]() {
-								goto l34
-							}
-							break
-						default:
-							if !_rules[rulenumber]() {
-								goto l34
-							}
-							break
+						position71, tokenIndex71, depth71 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l71
 						}
+						position++
+						goto l70
+					l71:
+						position, tokenIndex, depth = position71, tokenIndex71, depth71
 					}
-
-					goto l28
-				l34:
-					position, tokenIndex, depth = position28, tokenIndex28, depth28
+					if !_rules[rulevalue]() {
+						goto l69
+					}
+					goto l34
+				l69:
+					position, tokenIndex, depth = position34, tokenIndex34, depth34
+					if !_rules[rulein]() {
+						goto l72
+					}
+				l73:
 					{
-						switch buffer[position] {
-						case 'E', 'e':
-							{
-								position40 := position
-								depth++
-								{
-									position41, tokenIndex41, depth41 := position, tokenIndex, depth
-									if buffer[position] != rune('e') {
-										goto l42
-									}
-									position++
-									goto l41
-								l42:
-									position, tokenIndex, depth = position41, tokenIndex41, depth41
-									if buffer[position] != rune('E') {
-										goto l16
-									}
-									position++
-								}
-							l41:
-								{
-									position43, tokenIndex43, depth43 := position, tokenIndex, depth
-									if buffer[position] != rune('x') {
-										goto l44
-									}
-									position++
-									goto l43
-								l44:
-									position, tokenIndex, depth = position43, tokenIndex43, depth43
-									if buffer[position] != rune('X') {
-										goto l16
-									}
-									position++
-								}
-							l43:
-								{
-									position45, tokenIndex45, depth45 := position, tokenIndex, depth
-									if buffer[position] != rune('i') {
-										goto l46
-									}
-									position++
-									goto l45
-								l46:
-									position, tokenIndex, depth = position45, tokenIndex45, depth45
-									if buffer[position] != rune('I') {
-										goto l16
-									}
-									position++
-								}
-							l45:
-								{
-									position47, tokenIndex47, depth47 := position, tokenIndex, depth
-									if buffer[position] != rune('s') {
-										goto l48
-									}
-									position++
-									goto l47
-								l48:
-									position, tokenIndex, depth = position47, tokenIndex47, depth47
-									if buffer[position] != rune('S') {
-										goto l16
-									}
-									position++
-								}
-							l47:
-								{
-									position49, tokenIndex49, depth49 := position, tokenIndex, depth
-									if buffer[position] != rune('t') {
-										goto l50
-									}
-									position++
-									goto l49
-								l50:
-									position, tokenIndex, depth = position49, tokenIndex49, depth49
-									if buffer[position] != rune('T') {
-										goto l16
-									}
-									position++
-								}
-							l49:
-								{
-									position51, tokenIndex51, depth51 := position, tokenIndex, depth
-									if buffer[position] != rune('s') {
-										goto l52
-									}
-									position++
-									goto l51
-								l52:
-									position, tokenIndex, depth = position51, tokenIndex51, depth51
-									if buffer[position] != rune('S') {
-										goto l16
-									}
-									position++
-								}
-							l51:
-								depth--
-								add(ruleexists, position40)
+						position74, tokenIndex74, depth74 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l74
+						}
+						position++
+						goto l73
+					l74:
+						position, tokenIndex, depth = position74, tokenIndex74, depth74
+					}
+					if buffer[position] != rune('(') {
+						goto l72
+					}
+					position++
+				l75:
+					{
+						position76, tokenIndex76, depth76 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l76
+						}
+						position++
+						goto l75
+					l76:
+						position, tokenIndex, depth = position76, tokenIndex76, depth76
+					}
+					{
+						position77, tokenIndex77, depth77 := position, tokenIndex, depth
+						if !_rules[rulenumber]() {
+							goto l78
+						}
+						goto l77
+					l78:
+						position, tokenIndex, depth = position77, tokenIndex77, depth77
+						if !_rules[ruletime]() {
+							goto l79
+						}
+						goto l77
+					l79:
+						position, tokenIndex, depth = position77, tokenIndex77, depth77
+						if !_rules[ruledate]() {
+							goto l80
+						}
+						goto l77
+					l80:
+						position, tokenIndex, depth = position77, tokenIndex77, depth77
+						if !_rules[rulevalue]() {
+							goto l72
+						}
+					}
+				l77:
+				l81:
+					{
+						position82, tokenIndex82, depth82 := position, tokenIndex, depth
+					l83:
+						{
+							position84, tokenIndex84, depth84 := position, tokenIndex, depth
+							if buffer[position] != rune(' ') {
+								goto l84
 							}
-							break
-						case '=':
-							{
-								position53 := position
-								depth++
-								if buffer[position] != rune('=') {
-									goto l16
-								}
-								position++
-								depth--
-								add(ruleequal, position53)
+							position++
+							goto l83
+						l84:
+							position, tokenIndex, depth = position84, tokenIndex84, depth84
+						}
+						if buffer[position] != rune(',') {
+							goto l82
+						}
+						position++
+					l85:
+						{
+							position86, tokenIndex86, depth86 := position, tokenIndex, depth
+							if buffer[position] != rune(' ') {
+								goto l86
 							}
-						l54:
-							{
-								position55, tokenIndex55, depth55 := position, tokenIndex, depth
-								if buffer[position] != rune(' ') {
-									goto l55
-								}
-								position++
-								goto l54
-							l55:
-								position, tokenIndex, depth = position55, tokenIndex55, depth55
+							position++
+							goto l85
+						l86:
+							position, tokenIndex, depth = position86, tokenIndex86, depth86
+						}
+						{
+							position87, tokenIndex87, depth87 := position, tokenIndex, depth
+							if !_rules[rulenumber]() {
+								goto l88
 							}
-							{
-								switch buffer[position] {
-								case '\'':
-									if !_rules[rulevalue]() {
-										goto l16
-									}
-									break
-								case 'D', 'd':
-									if !_rules[ruledate]() {
-										goto l16
-									}
-									break
-								case 'T', 't':
-									if !_rules[ruletime]() {
-										goto l16
-									}
-									break
-								default:
-									if !_rules[rulenumber]() {
-										goto l16
-									}
-									break
-								}
+							goto l87
+						l88:
+							position, tokenIndex, depth = position87, tokenIndex87, depth87
+							if !_rules[ruletime]() {
+								goto l89
 							}
-
-							break
-						case '>':
-							{
-								position57 := position
-								depth++
-								if buffer[position] != rune('>') {
-									goto l16
-								}
-								position++
-								depth--
-								add(ruleg, position57)
+							goto l87
+						l89:
+							position, tokenIndex, depth = position87, tokenIndex87, depth87
+							if !_rules[ruledate]() {
+								goto l90
 							}
-						l58:
-							{
-								position59, tokenIndex59, depth59 := position, tokenIndex, depth
-								if buffer[position] != rune(' ') {
-									goto l59
-								}
-								position++
-								goto l58
-							l59:
-								position, tokenIndex, depth = position59, tokenIndex59, depth59
+							goto l87
+						l90:
+							position, tokenIndex, depth = position87, tokenIndex87, depth87
+							if !_rules[rulevalue]() {
+								goto l82
 							}
-							{
-								switch buffer[position] {
-								case 'D', 'd':
-									if !_rules[ruledate]() {
-										goto l16
-									}
-									break
-								case 'T', 't':
-									if !_rules[ruletime]() {
-										goto l16
-									}
-									break
-								default:
-									if !_rules[rulenumber]() {
-										goto l16
-									}
-									break
-								}
+						}
+					l87:
+						goto l81
+					l82:
+						position, tokenIndex, depth = position82, tokenIndex82, depth82
+					}
+				l91:
+					{
+						position92, tokenIndex92, depth92 := position, tokenIndex, depth
+						if buffer[position] != rune(' ') {
+							goto l92
+						}
+						position++
+						goto l91
+					l92:
+						position, tokenIndex, depth = position92, tokenIndex92, depth92
+					}
+					if buffer[position] != rune(')') {
+						goto l72
+					}
+					position++
+					goto l34
+				l72:
+					position, tokenIndex, depth = position34, tokenIndex34, depth34
+					if !_rules[ruleexists]() {
+						goto l30
+					}
+				}
+			l34:
+				depth--
+				add(rulecondition, position31)
+			}
+			return true
+		l30:
+			position, tokenIndex, depth = position30, tokenIndex30, depth30
+			return false
+		},
+		/* 5 tag <- <<(!(' ' / '\t' / '\n' / '\r' / '\\' / '(' / ')' / '"' / '\'' / '=' / '>' / '<' / ',') .)+>> */
+		func() bool {
+			position93, tokenIndex93, depth93 := position, tokenIndex, depth
+			{
+				position94 := position
+				depth++
+				{
+					position95 := position
+					depth++
+					{
+						position96, tokenIndex96, depth96 := position, tokenIndex, depth
+						{
+							position97, tokenIndex97, depth97 := position, tokenIndex, depth
+							if buffer[position] != rune(' ') {
+								goto l98
 							}
-
-							break
-						case '<':
-							{
-								position61 := position
-								depth++
-								if buffer[position] != rune('<') {
-									goto l16
-								}
-								position++
-								depth--
-								add(rulel, position61)
+							position++
+							goto l97
+						l98:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('\t') {
+								goto l99
+							}
+							position++
+							goto l97
+						l99:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('\n') {
+								goto l100
+							}
+							position++
+							goto l97
+						l100:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('\r') {
+								goto l101
+							}
+							position++
+							goto l97
+						l101:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('\\') {
+								goto l102
+							}
+							position++
+							goto l97
+						l102:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('(') {
+								goto l103
 							}
-						l62:
+							position++
+							goto l97
+						l103:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune(')') {
+								goto l104
+							}
+							position++
+							goto l97
+						l104:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('"') {
+								goto l105
+							}
+							position++
+							goto l97
+						l105:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('\'') {
+								goto l106
+							}
+							position++
+							goto l97
+						l106:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('=') {
+								goto l107
+							}
+							position++
+							goto l97
+						l107:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('>') {
+								goto l108
+							}
+							position++
+							goto l97
+						l108:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune('<') {
+								goto l109
+							}
+							position++
+							goto l97
+						l109:
+							position, tokenIndex, depth = position97, tokenIndex97, depth97
+							if buffer[position] != rune(',') {
+								goto l96
+							}
+							position++
+						}
+					l97:
+						goto l93
+					l96:
+						position, tokenIndex, depth = position96, tokenIndex96, depth96
+					}
+					if !matchDot() {
+						goto l93
+					}
+				l110:
+					{
+						position111, tokenIndex111, depth111 := position, tokenIndex, depth
+						{
+							position112, tokenIndex112, depth112 := position, tokenIndex, depth
 							{
-								position63, tokenIndex63, depth63 := position, tokenIndex, depth
+								position113, tokenIndex113, depth113 := position, tokenIndex, depth
 								if buffer[position] != rune(' ') {
-									goto l63
+									goto l114
 								}
 								position++
-								goto l62
-							l63:
-								position, tokenIndex, depth = position63, tokenIndex63, depth63
-							}
-							{
-								switch buffer[position] {
-								case 'D', 'd':
-									if !_rules[ruledate]() {
-										goto l16
-									}
-									break
-								case 'T', 't':
-									if !_rules[ruletime]() {
-										goto l16
-									}
-									break
-								default:
-									if !_rules[rulenumber]() {
-										goto l16
-									}
-									break
+								goto l113
+							l114:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('\t') {
+									goto l115
 								}
-							}
-
-							break
-						default:
-							{
-								position65 := position
-								depth++
-								{
-									position66, tokenIndex66, depth66 := position, tokenIndex, depth
-									if buffer[position] != rune('c') {
-										goto l67
-									}
-									position++
-									goto l66
-								l67:
-									position, tokenIndex, depth = position66, tokenIndex66, depth66
-									if buffer[position] != rune('C') {
-										goto l16
-									}
-									position++
+								position++
+								goto l113
+							l115:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('\n') {
+									goto l116
+								}
+								position++
+								goto l113
+							l116:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('\r') {
+									goto l117
+								}
+								position++
+								goto l113
+							l117:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('\\') {
+									goto l118
 								}
-							l66:
-								{
-									position68, tokenIndex68, depth68 := position, tokenIndex, depth
-									if buffer[position] != rune('o') {
-										goto l69
-									}
-									position++
-									goto l68
-								l69:
-									position, tokenIndex, depth = position68, tokenIndex68, depth68
-									if buffer[position] != rune('O') {
-										goto l16
-									}
-									position++
+								position++
+								goto l113
+							l118:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('(') {
+									goto l119
 								}
-							l68:
-								{
-									position70, tokenIndex70, depth70 := position, tokenIndex, depth
-									if buffer[position] != rune('n') {
-										goto l71
-									}
-									position++
-									goto l70
-								l71:
-									position, tokenIndex, depth = position70, tokenIndex70, depth70
-									if buffer[position] != rune('N') {
-										goto l16
-									}
-									position++
+								position++
+								goto l113
+							l119:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune(')') {
+									goto l120
 								}
-							l70:
-								{
-									position72, tokenIndex72, depth72 := position, tokenIndex, depth
-									if buffer[position] != rune('t') {
-										goto l73
-									}
-									position++
-									goto l72
-								l73:
-									position, tokenIndex, depth = position72, tokenIndex72, depth72
-									if buffer[position] != rune('T') {
-										goto l16
-									}
-									position++
+								position++
+								goto l113
+							l120:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('"') {
+									goto l121
 								}
-							l72:
-								{
-									position74, tokenIndex74, depth74 := position, tokenIndex, depth
-									if buffer[position] != rune('a') {
-										goto l75
-									}
-									position++
-									goto l74
-								l75:
-									position, tokenIndex, depth = position74, tokenIndex74, depth74
-									if buffer[position] != rune('A') {
-										goto l16
-									}
-									position++
+								position++
+								goto l113
+							l121:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('\'') {
+									goto l122
 								}
-							l74:
-								{
-									position76, tokenIndex76, depth76 := position, tokenIndex, depth
-									if buffer[position] != rune('i') {
-										goto l77
-									}
-									position++
-									goto l76
-								l77:
-									position, tokenIndex, depth = position76, tokenIndex76, depth76
-									if buffer[position] != rune('I') {
-										goto l16
-									}
-									position++
+								position++
+								goto l113
+							l122:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('=') {
+									goto l123
 								}
-							l76:
-								{
-									position78, tokenIndex78, depth78 := position, tokenIndex, depth
-									if buffer[position] != rune('n') {
-										goto l79
-									}
-									position++
-									goto l78
-								l79:
-									position, tokenIndex, depth = position78, tokenIndex78, depth78
-									if buffer[position] != rune('N') {
-										goto l16
-									}
-									position++
+								position++
+								goto l113
+							l123:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('>') {
+									goto l124
 								}
-							l78:
-								{
-									position80, tokenIndex80, depth80 := position, tokenIndex, depth
-									if buffer[position] != rune('s') {
-										goto l81
-									}
-									position++
-									goto l80
-								l81:
-									position, tokenIndex, depth = position80, tokenIndex80, depth80
-									if buffer[position] != rune('S') {
-										goto l16
-									}
-									position++
+								position++
+								goto l113
+							l124:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune('<') {
+									goto l125
 								}
-							l80:
-								depth--
-								add(rulecontains, position65)
-							}
-						l82:
-							{
-								position83, tokenIndex83, depth83 := position, tokenIndex, depth
-								if buffer[position] != rune(' ') {
-									goto l83
+								position++
+								goto l113
+							l125:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
+								if buffer[position] != rune(',') {
+									goto l112
 								}
 								position++
-								goto l82
-							l83:
-								position, tokenIndex, depth = position83, tokenIndex83, depth83
 							}
-							if !_rules[rulevalue]() {
-								goto l16
-							}
-							break
+						l113:
+							goto l111
+						l112:
+							position, tokenIndex, depth = position112, tokenIndex112, depth112
 						}
+						if !matchDot() {
+							goto l111
+						}
+						goto l110
+					l111:
+						position, tokenIndex, depth = position111, tokenIndex111, depth111
 					}
-
+					depth--
+					add(rulePegText, position95)
 				}
-			l28:
 				depth--
-				add(rulecondition, position17)
+				add(ruletag, position94)
 			}
 			return true
-		l16:
-			position, tokenIndex, depth = position16, tokenIndex16, depth16
+		l93:
+			position, tokenIndex, depth = position93, tokenIndex93, depth93
 			return false
 		},
-		/* 2 tag <- <<(!((&('<') '<') | (&('>') '>') | (&('=') '=') | (&('\'') '\'') | (&('"') '"') | (&(')') ')') | (&('(') '(') | (&('\\') '\\') | (&('\r') '\r') | (&('\n') '\n') | (&('\t') '\t') | (&(' ') ' ')) .)+>> */
-		nil,
-		/* 3 value <- <<('\'' (!('"' / '\'') .)* '\'')>> */
+		/* 6 value <- <<('\'' (!('"' / '\'') .)* '\'')>> */
 		func() bool {
-			position85, tokenIndex85, depth85 := position, tokenIndex, depth
+			position126, tokenIndex126, depth126 := position, tokenIndex, depth
 			{
-				position86 := position
+				position127 := position
 				depth++
 				{
-					position87 := position
+					position128 := position
 					depth++
 					if buffer[position] != rune('\'') {
-						goto l85
+						goto l126
 					}
 					position++
-				l88:
+				l129:
 					{
-						position89, tokenIndex89, depth89 := position, tokenIndex, depth
+						position130, tokenIndex130, depth130 := position, tokenIndex, depth
 						{
-							position90, tokenIndex90, depth90 := position, tokenIndex, depth
+							position131, tokenIndex131, depth131 := position, tokenIndex, depth
 							{
-								position91, tokenIndex91, depth91 := position, tokenIndex, depth
+								position132, tokenIndex132, depth132 := position, tokenIndex, depth
 								if buffer[position] != rune('"') {
-									goto l92
+									goto l133
 								}
 								position++
-								goto l91
-							l92:
-								position, tokenIndex, depth = position91, tokenIndex91, depth91
+								goto l132
+							l133:
+								position, tokenIndex, depth = position132, tokenIndex132, depth132
 								if buffer[position] != rune('\'') {
-									goto l90
+									goto l131
 								}
 								position++
 							}
-						l91:
-							goto l89
-						l90:
-							position, tokenIndex, depth = position90, tokenIndex90, depth90
+						l132:
+							goto l130
+						l131:
+							position, tokenIndex, depth = position131, tokenIndex131, depth131
 						}
 						if !matchDot() {
-							goto l89
+							goto l130
 						}
-						goto l88
-					l89:
-						position, tokenIndex, depth = position89, tokenIndex89, depth89
+						goto l129
+					l130:
+						position, tokenIndex, depth = position130, tokenIndex130, depth130
 					}
 					if buffer[position] != rune('\'') {
-						goto l85
+						goto l126
 					}
 					position++
 					depth--
-					add(rulePegText, position87)
+					add(rulePegText, position128)
 				}
 				depth--
-				add(rulevalue, position86)
+				add(rulevalue, position127)
 			}
 			return true
-		l85:
-			position, tokenIndex, depth = position85, tokenIndex85, depth85
+		l126:
+			position, tokenIndex, depth = position126, tokenIndex126, depth126
 			return false
 		},
-		/* 4 number <- <<('0' / ([1-9] digit* ('.' digit*)?))>> */
+		/* 7 number <- <<('0' / ([1-9] digit* ('.' digit*)?))>> */
 		func() bool {
-			position93, tokenIndex93, depth93 := position, tokenIndex, depth
+			position134, tokenIndex134, depth134 := position, tokenIndex, depth
 			{
-				position94 := position
+				position135 := position
 				depth++
 				{
-					position95 := position
+					position136 := position
 					depth++
 					{
-						position96, tokenIndex96, depth96 := position, tokenIndex, depth
+						position137, tokenIndex137, depth137 := position, tokenIndex, depth
 						if buffer[position] != rune('0') {
-							goto l97
+							goto l138
 						}
 						position++
-						goto l96
-					l97:
-						position, tokenIndex, depth = position96, tokenIndex96, depth96
+						goto l137
+					l138:
+						position, tokenIndex, depth = position137, tokenIndex137, depth137
 						if c := buffer[position]; c < rune('1') || c > rune('9') {
-							goto l93
+							goto l134
 						}
 						position++
-					l98:
+					l139:
 						{
-							position99, tokenIndex99, depth99 := position, tokenIndex, depth
+							position140, tokenIndex140, depth140 := position, tokenIndex, depth
 							if !_rules[ruledigit]() {
-								goto l99
+								goto l140
 							}
-							goto l98
-						l99:
-							position, tokenIndex, depth = position99, tokenIndex99, depth99
+							goto l139
+						l140:
+							position, tokenIndex, depth = position140, tokenIndex140, depth140
 						}
 						{
-							position100, tokenIndex100, depth100 := position, tokenIndex, depth
+							position141, tokenIndex141, depth141 := position, tokenIndex, depth
 							if buffer[position] != rune('.') {
-								goto l100
+								goto l141
 							}
 							position++
-						l102:
+						l143:
 							{
-								position103, tokenIndex103, depth103 := position, tokenIndex, depth
+								position144, tokenIndex144, depth144 := position, tokenIndex, depth
 								if !_rules[ruledigit]() {
-									goto l103
+									goto l144
 								}
-								goto l102
-							l103:
-								position, tokenIndex, depth = position103, tokenIndex103, depth103
+								goto l143
+							l144:
+								position, tokenIndex, depth = position144, tokenIndex144, depth144
 							}
-							goto l101
-						l100:
-							position, tokenIndex, depth = position100, tokenIndex100, depth100
+							goto l142
+						l141:
+							position, tokenIndex, depth = position141, tokenIndex141, depth141
 						}
-					l101:
+					l142:
+					}
+				l137:
+					depth--
+					add(rulePegText, position136)
+				}
+				depth--
+				add(rulenumber, position135)
+			}
+			return true
+		l134:
+			position, tokenIndex, depth = position134, tokenIndex134, depth134
+			return false
+		},
+		/* 8 digit <- <[0-9]> */
+		func() bool {
+			position145, tokenIndex145, depth145 := position, tokenIndex, depth
+			{
+				position146 := position
+				depth++
+				if c := buffer[position]; c < rune('0') || c > rune('9') {
+					goto l145
+				}
+				position++
+				depth--
+				add(ruledigit, position146)
+			}
+			return true
+		l145:
+			position, tokenIndex, depth = position145, tokenIndex145, depth145
+			return false
+		},
+		/* 9 time <- <(('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E') ' ' <(year '-' month '-' day 'T' digit digit ':' digit digit ':' digit digit ((('-' / '+') digit digit ':' digit digit) / 'Z'))>)> */
+		func() bool {
+			position147, tokenIndex147, depth147 := position, tokenIndex, depth
+			{
+				position148 := position
+				depth++
+				{
+					position149, tokenIndex149, depth149 := position, tokenIndex, depth
+					if buffer[position] != rune('t') {
+						goto l150
+					}
+					position++
+					goto l149
+				l150:
+					position, tokenIndex, depth = position149, tokenIndex149, depth149
+					if buffer[position] != rune('T') {
+						goto l147
+					}
+					position++
+				}
+			l149:
+				{
+					position151, tokenIndex151, depth151 := position, tokenIndex, depth
+					if buffer[position] != rune('i') {
+						goto l152
+					}
+					position++
+					goto l151
+				l152:
+					position, tokenIndex, depth = position151, tokenIndex151, depth151
+					if buffer[position] != rune('I') {
+						goto l147
+					}
+					position++
+				}
+			l151:
+				{
+					position153, tokenIndex153, depth153 := position, tokenIndex, depth
+					if buffer[position] != rune('m') {
+						goto l154
+					}
+					position++
+					goto l153
+				l154:
+					position, tokenIndex, depth = position153, tokenIndex153, depth153
+					if buffer[position] != rune('M') {
+						goto l147
+					}
+					position++
+				}
+			l153:
+				{
+					position155, tokenIndex155, depth155 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l156
+					}
+					position++
+					goto l155
+				l156:
+					position, tokenIndex, depth = position155, tokenIndex155, depth155
+					if buffer[position] != rune('E') {
+						goto l147
+					}
+					position++
+				}
+			l155:
+				if buffer[position] != rune(' ') {
+					goto l147
+				}
+				position++
+				{
+					position157 := position
+					depth++
+					if !_rules[ruleyear]() {
+						goto l147
+					}
+					if buffer[position] != rune('-') {
+						goto l147
+					}
+					position++
+					if !_rules[rulemonth]() {
+						goto l147
+					}
+					if buffer[position] != rune('-') {
+						goto l147
+					}
+					position++
+					if !_rules[ruleday]() {
+						goto l147
+					}
+					if buffer[position] != rune('T') {
+						goto l147
+					}
+					position++
+					if !_rules[ruledigit]() {
+						goto l147
+					}
+					if !_rules[ruledigit]() {
+						goto l147
+					}
+					if buffer[position] != rune(':') {
+						goto l147
+					}
+					position++
+					if !_rules[ruledigit]() {
+						goto l147
+					}
+					if !_rules[ruledigit]() {
+						goto l147
+					}
+					if buffer[position] != rune(':') {
+						goto l147
+					}
+					position++
+					if !_rules[ruledigit]() {
+						goto l147
+					}
+					if !_rules[ruledigit]() {
+						goto l147
+					}
+					{
+						position158, tokenIndex158, depth158 := position, tokenIndex, depth
+						{
+							position160, tokenIndex160, depth160 := position, tokenIndex, depth
+							if buffer[position] != rune('-') {
+								goto l161
+							}
+							position++
+							goto l160
+						l161:
+							position, tokenIndex, depth = position160, tokenIndex160, depth160
+							if buffer[position] != rune('+') {
+								goto l159
+							}
+							position++
+						}
+					l160:
+						if !_rules[ruledigit]() {
+							goto l159
+						}
+						if !_rules[ruledigit]() {
+							goto l159
+						}
+						if buffer[position] != rune(':') {
+							goto l159
+						}
+						position++
+						if !_rules[ruledigit]() {
+							goto l159
+						}
+						if !_rules[ruledigit]() {
+							goto l159
+						}
+						goto l158
+					l159:
+						position, tokenIndex, depth = position158, tokenIndex158, depth158
+						if buffer[position] != rune('Z') {
+							goto l147
+						}
+						position++
+					}
+				l158:
+					depth--
+					add(rulePegText, position157)
+				}
+				depth--
+				add(ruletime, position148)
+			}
+			return true
+		l147:
+			position, tokenIndex, depth = position147, tokenIndex147, depth147
+			return false
+		},
+		/* 10 date <- <(('d' / 'D') ('a' / 'A') ('t' / 'T') ('e' / 'E') ' ' <(year '-' month '-' day)>)> */
+		func() bool {
+			position162, tokenIndex162, depth162 := position, tokenIndex, depth
+			{
+				position163 := position
+				depth++
+				{
+					position164, tokenIndex164, depth164 := position, tokenIndex, depth
+					if buffer[position] != rune('d') {
+						goto l165
+					}
+					position++
+					goto l164
+				l165:
+					position, tokenIndex, depth = position164, tokenIndex164, depth164
+					if buffer[position] != rune('D') {
+						goto l162
+					}
+					position++
+				}
+			l164:
+				{
+					position166, tokenIndex166, depth166 := position, tokenIndex, depth
+					if buffer[position] != rune('a') {
+						goto l167
+					}
+					position++
+					goto l166
+				l167:
+					position, tokenIndex, depth = position166, tokenIndex166, depth166
+					if buffer[position] != rune('A') {
+						goto l162
+					}
+					position++
+				}
+			l166:
+				{
+					position168, tokenIndex168, depth168 := position, tokenIndex, depth
+					if buffer[position] != rune('t') {
+						goto l169
+					}
+					position++
+					goto l168
+				l169:
+					position, tokenIndex, depth = position168, tokenIndex168, depth168
+					if buffer[position] != rune('T') {
+						goto l162
+					}
+					position++
+				}
+			l168:
+				{
+					position170, tokenIndex170, depth170 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l171
+					}
+					position++
+					goto l170
+				l171:
+					position, tokenIndex, depth = position170, tokenIndex170, depth170
+					if buffer[position] != rune('E') {
+						goto l162
+					}
+					position++
+				}
+			l170:
+				if buffer[position] != rune(' ') {
+					goto l162
+				}
+				position++
+				{
+					position172 := position
+					depth++
+					if !_rules[ruleyear]() {
+						goto l162
+					}
+					if buffer[position] != rune('-') {
+						goto l162
+					}
+					position++
+					if !_rules[rulemonth]() {
+						goto l162
+					}
+					if buffer[position] != rune('-') {
+						goto l162
+					}
+					position++
+					if !_rules[ruleday]() {
+						goto l162
+					}
+					depth--
+					add(rulePegText, position172)
+				}
+				depth--
+				add(ruledate, position163)
+			}
+			return true
+		l162:
+			position, tokenIndex, depth = position162, tokenIndex162, depth162
+			return false
+		},
+		/* 11 year <- <(('1' / '2') digit digit digit)> */
+		func() bool {
+			position173, tokenIndex173, depth173 := position, tokenIndex, depth
+			{
+				position174 := position
+				depth++
+				{
+					position175, tokenIndex175, depth175 := position, tokenIndex, depth
+					if buffer[position] != rune('1') {
+						goto l176
+					}
+					position++
+					goto l175
+				l176:
+					position, tokenIndex, depth = position175, tokenIndex175, depth175
+					if buffer[position] != rune('2') {
+						goto l173
+					}
+					position++
+				}
+			l175:
+				if !_rules[ruledigit]() {
+					goto l173
+				}
+				if !_rules[ruledigit]() {
+					goto l173
+				}
+				if !_rules[ruledigit]() {
+					goto l173
+				}
+				depth--
+				add(ruleyear, position174)
+			}
+			return true
+		l173:
+			position, tokenIndex, depth = position173, tokenIndex173, depth173
+			return false
+		},
+		/* 12 month <- <(('0' / '1') digit)> */
+		func() bool {
+			position177, tokenIndex177, depth177 := position, tokenIndex, depth
+			{
+				position178 := position
+				depth++
+				{
+					position179, tokenIndex179, depth179 := position, tokenIndex, depth
+					if buffer[position] != rune('0') {
+						goto l180
+					}
+					position++
+					goto l179
+				l180:
+					position, tokenIndex, depth = position179, tokenIndex179, depth179
+					if buffer[position] != rune('1') {
+						goto l177
+					}
+					position++
+				}
+			l179:
+				if !_rules[ruledigit]() {
+					goto l177
+				}
+				depth--
+				add(rulemonth, position178)
+			}
+			return true
+		l177:
+			position, tokenIndex, depth = position177, tokenIndex177, depth177
+			return false
+		},
+		/* 13 day <- <(((&('3') '3') | (&('2') '2') | (&('1') '1') | (&('0') '0')) digit)> */
+		func() bool {
+			position181, tokenIndex181, depth181 := position, tokenIndex, depth
+			{
+				position182 := position
+				depth++
+				{
+					switch buffer[position] {
+					case '3':
+						if buffer[position] != rune('3') {
+							goto l181
+						}
+						position++
+						break
+					case '2':
+						if buffer[position] != rune('2') {
+							goto l181
+						}
+						position++
+						break
+					case '1':
+						if buffer[position] != rune('1') {
+							goto l181
+						}
+						position++
+						break
+					default:
+						if buffer[position] != rune('0') {
+							goto l181
+						}
+						position++
+						break
+					}
+				}
+
+				if !_rules[ruledigit]() {
+					goto l181
+				}
+				depth--
+				add(ruleday, position182)
+			}
+			return true
+		l181:
+			position, tokenIndex, depth = position181, tokenIndex181, depth181
+			return false
+		},
+		/* 14 and <- <(('a' / 'A') ('n' / 'N') ('d' / 'D'))> */
+		func() bool {
+			position183, tokenIndex183, depth183 := position, tokenIndex, depth
+			{
+				position184 := position
+				depth++
+				{
+					position185, tokenIndex185, depth185 := position, tokenIndex, depth
+					if buffer[position] != rune('a') {
+						goto l186
+					}
+					position++
+					goto l185
+				l186:
+					position, tokenIndex, depth = position185, tokenIndex185, depth185
+					if buffer[position] != rune('A') {
+						goto l183
+					}
+					position++
+				}
+			l185:
+				{
+					position187, tokenIndex187, depth187 := position, tokenIndex, depth
+					if buffer[position] != rune('n') {
+						goto l188
+					}
+					position++
+					goto l187
+				l188:
+					position, tokenIndex, depth = position187, tokenIndex187, depth187
+					if buffer[position] != rune('N') {
+						goto l183
+					}
+					position++
+				}
+			l187:
+				{
+					position189, tokenIndex189, depth189 := position, tokenIndex, depth
+					if buffer[position] != rune('d') {
+						goto l190
+					}
+					position++
+					goto l189
+				l190:
+					position, tokenIndex, depth = position189, tokenIndex189, depth189
+					if buffer[position] != rune('D') {
+						goto l183
+					}
+					position++
+				}
+			l189:
+				depth--
+				add(ruleand, position184)
+			}
+			return true
+		l183:
+			position, tokenIndex, depth = position183, tokenIndex183, depth183
+			return false
+		},
+		/* 15 or <- <(('o' / 'O') ('r' / 'R'))> */
+		func() bool {
+			position191, tokenIndex191, depth191 := position, tokenIndex, depth
+			{
+				position192 := position
+				depth++
+				{
+					position193, tokenIndex193, depth193 := position, tokenIndex, depth
+					if buffer[position] != rune('o') {
+						goto l194
+					}
+					position++
+					goto l193
+				l194:
+					position, tokenIndex, depth = position193, tokenIndex193, depth193
+					if buffer[position] != rune('O') {
+						goto l191
+					}
+					position++
+				}
+			l193:
+				{
+					position195, tokenIndex195, depth195 := position, tokenIndex, depth
+					if buffer[position] != rune('r') {
+						goto l196
+					}
+					position++
+					goto l195
+				l196:
+					position, tokenIndex, depth = position195, tokenIndex195, depth195
+					if buffer[position] != rune('R') {
+						goto l191
+					}
+					position++
+				}
+			l195:
+				depth--
+				add(ruleor, position192)
+			}
+			return true
+		l191:
+			position, tokenIndex, depth = position191, tokenIndex191, depth191
+			return false
+		},
+		/* 16 not <- <(('n' / 'N') ('o' / 'O') ('t' / 'T'))> */
+		func() bool {
+			position197, tokenIndex197, depth197 := position, tokenIndex, depth
+			{
+				position198 := position
+				depth++
+				{
+					position199, tokenIndex199, depth199 := position, tokenIndex, depth
+					if buffer[position] != rune('n') {
+						goto l200
+					}
+					position++
+					goto l199
+				l200:
+					position, tokenIndex, depth = position199, tokenIndex199, depth199
+					if buffer[position] != rune('N') {
+						goto l197
 					}
-				l96:
-					depth--
-					add(rulePegText, position95)
+					position++
+				}
+			l199:
+				{
+					position201, tokenIndex201, depth201 := position, tokenIndex, depth
+					if buffer[position] != rune('o') {
+						goto l202
+					}
+					position++
+					goto l201
+				l202:
+					position, tokenIndex, depth = position201, tokenIndex201, depth201
+					if buffer[position] != rune('O') {
+						goto l197
+					}
+					position++
+				}
+			l201:
+				{
+					position203, tokenIndex203, depth203 := position, tokenIndex, depth
+					if buffer[position] != rune('t') {
+						goto l204
+					}
+					position++
+					goto l203
+				l204:
+					position, tokenIndex, depth = position203, tokenIndex203, depth203
+					if buffer[position] != rune('T') {
+						goto l197
+					}
+					position++
 				}
+			l203:
 				depth--
-				add(rulenumber, position94)
+				add(rulenot, position198)
 			}
 			return true
-		l93:
-			position, tokenIndex, depth = position93, tokenIndex93, depth93
+		l197:
+			position, tokenIndex, depth = position197, tokenIndex197, depth197
 			return false
 		},
-		/* 5 digit <- <[0-9]> */
+		/* 17 equal <- <'='> */
 		func() bool {
-			position104, tokenIndex104, depth104 := position, tokenIndex, depth
+			position205, tokenIndex205, depth205 := position, tokenIndex, depth
 			{
-				position105 := position
+				position206 := position
 				depth++
-				if c := buffer[position]; c < rune('0') || c > rune('9') {
-					goto l104
+				if buffer[position] != rune('=') {
+					goto l205
 				}
 				position++
 				depth--
-				add(ruledigit, position105)
+				add(ruleequal, position206)
 			}
 			return true
-		l104:
-			position, tokenIndex, depth = position104, tokenIndex104, depth104
+		l205:
+			position, tokenIndex, depth = position205, tokenIndex205, depth205
 			return false
 		},
-		/* 6 time <- <(('t' / 'T') ('i' / 'I') ('m' / 'M') ('e' / 'E') ' ' <(year '-' month '-' day 'T' digit digit ':' digit digit ':' digit digit ((('-' / '+') digit digit ':' digit digit) / 'Z'))>)> */
+		/* 18 contains <- <(('c' / 'C') ('o' / 'O') ('n' / 'N') ('t' / 'T') ('a' / 'A') ('i' / 'I') ('n' / 'N') ('s' / 'S'))> */
 		func() bool {
-			position106, tokenIndex106, depth106 := position, tokenIndex, depth
+			position207, tokenIndex207, depth207 := position, tokenIndex, depth
 			{
-				position107 := position
+				position208 := position
 				depth++
 				{
-					position108, tokenIndex108, depth108 := position, tokenIndex, depth
-					if buffer[position] != rune('t') {
-						goto l109
+					position209, tokenIndex209, depth209 := position, tokenIndex, depth
+					if buffer[position] != rune('c') {
+						goto l210
 					}
 					position++
-					goto l108
-				l109:
-					position, tokenIndex, depth = position108, tokenIndex108, depth108
-					if buffer[position] != rune('T') {
-						goto l106
+					goto l209
+				l210:
+					position, tokenIndex, depth = position209, tokenIndex209, depth209
+					if buffer[position] != rune('C') {
+						goto l207
 					}
 					position++
 				}
-			l108:
+			l209:
 				{
-					position110, tokenIndex110, depth110 := position, tokenIndex, depth
-					if buffer[position] != rune('i') {
-						goto l111
+					position211, tokenIndex211, depth211 := position, tokenIndex, depth
+					if buffer[position] != rune('o') {
+						goto l212
 					}
 					position++
-					goto l110
-				l111:
-					position, tokenIndex, depth = position110, tokenIndex110, depth110
-					if buffer[position] != rune('I') {
-						goto l106
+					goto l211
+				l212:
+					position, tokenIndex, depth = position211, tokenIndex211, depth211
+					if buffer[position] != rune('O') {
+						goto l207
 					}
 					position++
 				}
-			l110:
+			l211:
 				{
-					position112, tokenIndex112, depth112 := position, tokenIndex, depth
-					if buffer[position] != rune('m') {
-						goto l113
+					position213, tokenIndex213, depth213 := position, tokenIndex, depth
+					if buffer[position] != rune('n') {
+						goto l214
 					}
 					position++
-					goto l112
-				l113:
-					position, tokenIndex, depth = position112, tokenIndex112, depth112
-					if buffer[position] != rune('M') {
-						goto l106
+					goto l213
+				l214:
+					position, tokenIndex, depth = position213, tokenIndex213, depth213
+					if buffer[position] != rune('N') {
+						goto l207
 					}
 					position++
 				}
-			l112:
+			l213:
 				{
-					position114, tokenIndex114, depth114 := position, tokenIndex, depth
-					if buffer[position] != rune('e') {
-						goto l115
+					position215, tokenIndex215, depth215 := position, tokenIndex, depth
+					if buffer[position] != rune('t') {
+						goto l216
 					}
 					position++
-					goto l114
-				l115:
-					position, tokenIndex, depth = position114, tokenIndex114, depth114
-					if buffer[position] != rune('E') {
-						goto l106
+					goto l215
+				l216:
+					position, tokenIndex, depth = position215, tokenIndex215, depth215
+					if buffer[position] != rune('T') {
+						goto l207
 					}
 					position++
 				}
-			l114:
-				if buffer[position] != rune(' ') {
-					goto l106
-				}
-				position++
+			l215:
 				{
-					position116 := position
-					depth++
-					if !_rules[ruleyear]() {
-						goto l106
-					}
-					if buffer[position] != rune('-') {
-						goto l106
+					position217, tokenIndex217, depth217 := position, tokenIndex, depth
+					if buffer[position] != rune('a') {
+						goto l218
 					}
 					position++
-					if !_rules[rulemonth]() {
-						goto l106
-					}
-					if buffer[position] != rune('-') {
-						goto l106
+					goto l217
+				l218:
+					position, tokenIndex, depth = position217, tokenIndex217, depth217
+					if buffer[position] != rune('A') {
+						goto l207
 					}
 					position++
-					if !_rules[ruleday]() {
-						goto l106
-					}
-					if buffer[position] != rune('T') {
-						goto l106
+				}
+			l217:
+				{
+					position219, tokenIndex219, depth219 := position, tokenIndex, depth
+					if buffer[position] != rune('i') {
+						goto l220
 					}
 					position++
-					if !_rules[ruledigit]() {
-						goto l106
-					}
-					if !_rules[ruledigit]() {
-						goto l106
-					}
-					if buffer[position] != rune(':') {
-						goto l106
+					goto l219
+				l220:
+					position, tokenIndex, depth = position219, tokenIndex219, depth219
+					if buffer[position] != rune('I') {
+						goto l207
 					}
 					position++
-					if !_rules[ruledigit]() {
-						goto l106
-					}
-					if !_rules[ruledigit]() {
-						goto l106
-					}
-					if buffer[position] != rune(':') {
-						goto l106
+				}
+			l219:
+				{
+					position221, tokenIndex221, depth221 := position, tokenIndex, depth
+					if buffer[position] != rune('n') {
+						goto l222
 					}
 					position++
-					if !_rules[ruledigit]() {
-						goto l106
+					goto l221
+				l222:
+					position, tokenIndex, depth = position221, tokenIndex221, depth221
+					if buffer[position] != rune('N') {
+						goto l207
 					}
-					if !_rules[ruledigit]() {
-						goto l106
+					position++
+				}
+			l221:
+				{
+					position223, tokenIndex223, depth223 := position, tokenIndex, depth
+					if buffer[position] != rune('s') {
+						goto l224
 					}
-					{
-						position117, tokenIndex117, depth117 := position, tokenIndex, depth
-						{
-							position119, tokenIndex119, depth119 := position, tokenIndex, depth
-							if buffer[position] != rune('-') {
-								goto l120
-							}
-							position++
-							goto l119
-						l120:
-							position, tokenIndex, depth = position119, tokenIndex119, depth119
-							if buffer[position] != rune('+') {
-								goto l118
-							}
-							position++
-						}
-					l119:
-						if !_rules[ruledigit]() {
-							goto l118
-						}
-						if !_rules[ruledigit]() {
-							goto l118
-						}
-						if buffer[position] != rune(':') {
-							goto l118
-						}
-						position++
-						if !_rules[ruledigit]() {
-							goto l118
-						}
-						if !_rules[ruledigit]() {
-							goto l118
-						}
-						goto l117
-					l118:
-						position, tokenIndex, depth = position117, tokenIndex117, depth117
-						if buffer[position] != rune('Z') {
-							goto l106
-						}
-						position++
+					position++
+					goto l223
+				l224:
+					position, tokenIndex, depth = position223, tokenIndex223, depth223
+					if buffer[position] != rune('S') {
+						goto l207
 					}
-				l117:
-					depth--
-					add(rulePegText, position116)
+					position++
 				}
+			l223:
 				depth--
-				add(ruletime, position107)
+				add(rulecontains, position208)
 			}
 			return true
-		l106:
-			position, tokenIndex, depth = position106, tokenIndex106, depth106
+		l207:
+			position, tokenIndex, depth = position207, tokenIndex207, depth207
 			return false
 		},
-		/* 7 date <- <(('d' / 'D') ('a' / 'A') ('t' / 'T') ('e' / 'E') ' ' <(year '-' month '-' day)>)> */
+		/* 19 startswith <- <(('s' / 'S') ('t' / 'T') ('a' / 'A') ('r' / 'R') ('t' / 'T') ('s' / 'S') ' ' ('w' / 'W') ('i' / 'I') ('t' / 'T') ('h' / 'H'))> */
 		func() bool {
-			position121, tokenIndex121, depth121 := position, tokenIndex, depth
+			position225, tokenIndex225, depth225 := position, tokenIndex, depth
 			{
-				position122 := position
+				position226 := position
 				depth++
 				{
-					position123, tokenIndex123, depth123 := position, tokenIndex, depth
-					if buffer[position] != rune('d') {
-						goto l124
+					position227, tokenIndex227, depth227 := position, tokenIndex, depth
+					if buffer[position] != rune('s') {
+						goto l228
 					}
 					position++
-					goto l123
-				l124:
-					position, tokenIndex, depth = position123, tokenIndex123, depth123
-					if buffer[position] != rune('D') {
-						goto l121
+					goto l227
+				l228:
+					position, tokenIndex, depth = position227, tokenIndex227, depth227
+					if buffer[position] != rune('S') {
+						goto l225
+					}
+					position++
+				}
+			l227:
+				{
+					position229, tokenIndex229, depth229 := position, tokenIndex, depth
+					if buffer[position] != rune('t') {
+						goto l230
+					}
+					position++
+					goto l229
+				l230:
+					position, tokenIndex, depth = position229, tokenIndex229, depth229
+					if buffer[position] != rune('T') {
+						goto l225
 					}
 					position++
 				}
-			l123:
+			l229:
 				{
-					position125, tokenIndex125, depth125 := position, tokenIndex, depth
+					position231, tokenIndex231, depth231 := position, tokenIndex, depth
 					if buffer[position] != rune('a') {
-						goto l126
+						goto l232
 					}
 					position++
-					goto l125
-				l126:
-					position, tokenIndex, depth = position125, tokenIndex125, depth125
+					goto l231
+				l232:
+					position, tokenIndex, depth = position231, tokenIndex231, depth231
 					if buffer[position] != rune('A') {
-						goto l121
+						goto l225
+					}
+					position++
+				}
+			l231:
+				{
+					position233, tokenIndex233, depth233 := position, tokenIndex, depth
+					if buffer[position] != rune('r') {
+						goto l234
+					}
+					position++
+					goto l233
+				l234:
+					position, tokenIndex, depth = position233, tokenIndex233, depth233
+					if buffer[position] != rune('R') {
+						goto l225
 					}
 					position++
 				}
-			l125:
+			l233:
 				{
-					position127, tokenIndex127, depth127 := position, tokenIndex, depth
+					position235, tokenIndex235, depth235 := position, tokenIndex, depth
 					if buffer[position] != rune('t') {
-						goto l128
+						goto l236
 					}
 					position++
-					goto l127
-				l128:
-					position, tokenIndex, depth = position127, tokenIndex127, depth127
+					goto l235
+				l236:
+					position, tokenIndex, depth = position235, tokenIndex235, depth235
 					if buffer[position] != rune('T') {
-						goto l121
+						goto l225
 					}
 					position++
 				}
-			l127:
+			l235:
 				{
-					position129, tokenIndex129, depth129 := position, tokenIndex, depth
-					if buffer[position] != rune('e') {
-						goto l130
+					position237, tokenIndex237, depth237 := position, tokenIndex, depth
+					if buffer[position] != rune('s') {
+						goto l238
 					}
 					position++
-					goto l129
-				l130:
-					position, tokenIndex, depth = position129, tokenIndex129, depth129
-					if buffer[position] != rune('E') {
-						goto l121
+					goto l237
+				l238:
+					position, tokenIndex, depth = position237, tokenIndex237, depth237
+					if buffer[position] != rune('S') {
+						goto l225
 					}
 					position++
 				}
-			l129:
+			l237:
 				if buffer[position] != rune(' ') {
-					goto l121
+					goto l225
 				}
 				position++
 				{
-					position131 := position
-					depth++
-					if !_rules[ruleyear]() {
-						goto l121
+					position239, tokenIndex239, depth239 := position, tokenIndex, depth
+					if buffer[position] != rune('w') {
+						goto l240
 					}
-					if buffer[position] != rune('-') {
-						goto l121
+					position++
+					goto l239
+				l240:
+					position, tokenIndex, depth = position239, tokenIndex239, depth239
+					if buffer[position] != rune('W') {
+						goto l225
 					}
 					position++
-					if !_rules[rulemonth]() {
-						goto l121
+				}
+			l239:
+				{
+					position241, tokenIndex241, depth241 := position, tokenIndex, depth
+					if buffer[position] != rune('i') {
+						goto l242
 					}
-					if buffer[position] != rune('-') {
-						goto l121
+					position++
+					goto l241
+				l242:
+					position, tokenIndex, depth = position241, tokenIndex241, depth241
+					if buffer[position] != rune('I') {
+						goto l225
 					}
 					position++
-					if !_rules[ruleday]() {
-						goto l121
+				}
+			l241:
+				{
+					position243, tokenIndex243, depth243 := position, tokenIndex, depth
+					if buffer[position] != rune('t') {
+						goto l244
 					}
-					depth--
-					add(rulePegText, position131)
+					position++
+					goto l243
+				l244:
+					position, tokenIndex, depth = position243, tokenIndex243, depth243
+					if buffer[position] != rune('T') {
+						goto l225
+					}
+					position++
+				}
+			l243:
+				{
+					position245, tokenIndex245, depth245 := position, tokenIndex, depth
+					if buffer[position] != rune('h') {
+						goto l246
+					}
+					position++
+					goto l245
+				l246:
+					position, tokenIndex, depth = position245, tokenIndex245, depth245
+					if buffer[position] != rune('H') {
+						goto l225
+					}
+					position++
 				}
+			l245:
 				depth--
-				add(ruledate, position122)
+				add(rulestartswith, position226)
 			}
 			return true
-		l121:
-			position, tokenIndex, depth = position121, tokenIndex121, depth121
+		l225:
+			position, tokenIndex, depth = position225, tokenIndex225, depth225
 			return false
 		},
-		/* 8 year <- <(('1' / '2') digit digit digit)> */
+		/* 20 in <- <(('i' / 'I') ('n' / 'N'))> */
 		func() bool {
-			position132, tokenIndex132, depth132 := position, tokenIndex, depth
+			position247, tokenIndex247, depth247 := position, tokenIndex, depth
 			{
-				position133 := position
+				position248 := position
 				depth++
 				{
-					position134, tokenIndex134, depth134 := position, tokenIndex, depth
-					if buffer[position] != rune('1') {
-						goto l135
+					position249, tokenIndex249, depth249 := position, tokenIndex, depth
+					if buffer[position] != rune('i') {
+						goto l250
 					}
 					position++
-					goto l134
-				l135:
-					position, tokenIndex, depth = position134, tokenIndex134, depth134
-					if buffer[position] != rune('2') {
-						goto l132
+					goto l249
+				l250:
+					position, tokenIndex, depth = position249, tokenIndex249, depth249
+					if buffer[position] != rune('I') {
+						goto l247
 					}
 					position++
 				}
-			l134:
-				if !_rules[ruledigit]() {
-					goto l132
-				}
-				if !_rules[ruledigit]() {
-					goto l132
-				}
-				if !_rules[ruledigit]() {
-					goto l132
+			l249:
+				{
+					position251, tokenIndex251, depth251 := position, tokenIndex, depth
+					if buffer[position] != rune('n') {
+						goto l252
+					}
+					position++
+					goto l251
+				l252:
+					position, tokenIndex, depth = position251, tokenIndex251, depth251
+					if buffer[position] != rune('N') {
+						goto l247
+					}
+					position++
 				}
+			l251:
 				depth--
-				add(ruleyear, position133)
+				add(rulein, position248)
 			}
 			return true
-		l132:
-			position, tokenIndex, depth = position132, tokenIndex132, depth132
+		l247:
+			position, tokenIndex, depth = position247, tokenIndex247, depth247
 			return false
 		},
-		/* 9 month <- <(('0' / '1') digit)> */
+		/* 21 exists <- <(('e' / 'E') ('x' / 'X') ('i' / 'I') ('s' / 'S') ('t' / 'T') ('s' / 'S'))> */
 		func() bool {
-			position136, tokenIndex136, depth136 := position, tokenIndex, depth
+			position253, tokenIndex253, depth253 := position, tokenIndex, depth
 			{
-				position137 := position
+				position254 := position
 				depth++
 				{
-					position138, tokenIndex138, depth138 := position, tokenIndex, depth
-					if buffer[position] != rune('0') {
-						goto l139
+					position255, tokenIndex255, depth255 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l256
 					}
 					position++
-					goto l138
-				l139:
-					position, tokenIndex, depth = position138, tokenIndex138, depth138
-					if buffer[position] != rune('1') {
-						goto l136
+					goto l255
+				l256:
+					position, tokenIndex, depth = position255, tokenIndex255, depth255
+					if buffer[position] != rune('E') {
+						goto l253
 					}
 					position++
 				}
-			l138:
-				if !_rules[ruledigit]() {
-					goto l136
+			l255:
+				{
+					position257, tokenIndex257, depth257 := position, tokenIndex, depth
+					if buffer[position] != rune('x') {
+						goto l258
+					}
+					position++
+					goto l257
+				l258:
+					position, tokenIndex, depth = position257, tokenIndex257, depth257
+					if buffer[position] != rune('X') {
+						goto l253
+					}
+					position++
+				}
+			l257:
+				{
+					position259, tokenIndex259, depth259 := position, tokenIndex, depth
+					if buffer[position] != rune('i') {
+						goto l260
+					}
+					position++
+					goto l259
+				l260:
+					position, tokenIndex, depth = position259, tokenIndex259, depth259
+					if buffer[position] != rune('I') {
+						goto l253
+					}
+					position++
+				}
+			l259:
+				{
+					position261, tokenIndex261, depth261 := position, tokenIndex, depth
+					if buffer[position] != rune('s') {
+						goto l262
+					}
+					position++
+					goto l261
+				l262:
+					position, tokenIndex, depth = position261, tokenIndex261, depth261
+					if buffer[position] != rune('S') {
+						goto l253
+					}
+					position++
+				}
+			l261:
+				{
+					position263, tokenIndex263, depth263 := position, tokenIndex, depth
+					if buffer[position] != rune('t') {
+						goto l264
+					}
+					position++
+					goto l263
+				l264:
+					position, tokenIndex, depth = position263, tokenIndex263, depth263
+					if buffer[position] != rune('T') {
+						goto l253
+					}
+					position++
+				}
+			l263:
+				{
+					position265, tokenIndex265, depth265 := position, tokenIndex, depth
+					if buffer[position] != rune('s') {
+						goto l266
+					}
+					position++
+					goto l265
+				l266:
+					position, tokenIndex, depth = position265, tokenIndex265, depth265
+					if buffer[position] != rune('S') {
+						goto l253
+					}
+					position++
 				}
+			l265:
 				depth--
-				add(rulemonth, position137)
+				add(ruleexists, position254)
 			}
 			return true
-		l136:
-			position, tokenIndex, depth = position136, tokenIndex136, depth136
+		l253:
+			position, tokenIndex, depth = position253, tokenIndex253, depth253
 			return false
 		},
-		/* 10 day <- <(((&('3') '3') | (&('2') '2') | (&('1') '1') | (&('0') '0')) digit)> */
+		/* 22 le <- <('<' '=')> */
 		func() bool {
-			position140, tokenIndex140, depth140 := position, tokenIndex, depth
+			position267, tokenIndex267, depth267 := position, tokenIndex, depth
 			{
-				position141 := position
+				position268 := position
 				depth++
-				{
-					switch buffer[position] {
-					case '3':
-						if buffer[position] != rune('3') {
-							goto l140
-						}
-						position++
-						break
-					case '2':
-						if buffer[position] != rune('2') {
-							goto l140
-						}
-						position++
-						break
-					case '1':
-						if buffer[position] != rune('1') {
-							goto l140
-						}
-						position++
-						break
-					default:
-						if buffer[position] != rune('0') {
-							goto l140
-						}
-						position++
-						break
-					}
+				if buffer[position] != rune('<') {
+					goto l267
 				}
-
-				if !_rules[ruledigit]() {
-					goto l140
+				position++
+				if buffer[position] != rune('=') {
+					goto l267
 				}
+				position++
 				depth--
-				add(ruleday, position141)
+				add(rulele, position268)
 			}
 			return true
-		l140:
-			position, tokenIndex, depth = position140, tokenIndex140, depth140
+		l267:
+			position, tokenIndex, depth = position267, tokenIndex267, depth267
+			return false
+		},
+		/* 23 ge <- <('>' '=')> */
+		func() bool {
+			position269, tokenIndex269, depth269 := position, tokenIndex, depth
+			{
+				position270 := position
+				depth++
+				if buffer[position] != rune('>') {
+					goto l269
+				}
+				position++
+				if buffer[position] != rune('=') {
+					goto l269
+				}
+				position++
+				depth--
+				add(rulege, position270)
+			}
+			return true
+		l269:
+			position, tokenIndex, depth = position269, tokenIndex269, depth269
+			return false
+		},
+		/* 24 l <- <'<'> */
+		func() bool {
+			position271, tokenIndex271, depth271 := position, tokenIndex, depth
+			{
+				position272 := position
+				depth++
+				if buffer[position] != rune('<') {
+					goto l271
+				}
+				position++
+				depth--
+				add(rulel, position272)
+			}
+			return true
+		l271:
+			position, tokenIndex, depth = position271, tokenIndex271, depth271
+			return false
+		},
+		/* 25 g <- <'>'> */
+		func() bool {
+			position273, tokenIndex273, depth273 := position, tokenIndex, depth
+			{
+				position274 := position
+				depth++
+				if buffer[position] != rune('>') {
+					goto l273
+				}
+				position++
+				depth--
+				add(ruleg, position274)
+			}
+			return true
+		l273:
+			position, tokenIndex, depth = position273, tokenIndex273, depth273
 			return false
 		},
-		/* 11 and <- <(('a' / 'A') ('n' / 'N') ('d' / 'D'))> */
-		nil,
-		/* 12 equal <- <'='> */
-		nil,
-		/* 13 contains <- <(('c' / 'C') ('o' / 'O') ('n' / 'N') ('t' / 'T') ('a' / 'A') ('i' / 'I') ('n' / 'N') ('s' / 'S'))> */
-		nil,
-		/* 14 exists <- <(('e' / 'E') ('x' / 'X') ('i' / 'I') ('s' / 'S') ('t' / 'T') ('s' / 'S'))> */
-		nil,
-		/* 15 le <- <('<' '=')> */
-		nil,
-		/* 16 ge <- <('>' '=')> */
-		nil,
-		/* 17 l <- <'<'> */
-		nil,
-		/* 18 g <- <'>'> */
-		nil,
 		nil,
 	}
 	p.rules = _rules
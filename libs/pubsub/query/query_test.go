@@ -202,6 +202,33 @@ func TestMatches(t *testing.T) {
 			false,
 			false,
 		},
+		{"tx.gas < 7 OR tx.gas > 9", map[string][]string{"tx.gas": {"10"}}, false, true, false},
+		{"tx.gas < 7 OR tx.gas > 9", map[string][]string{"tx.gas": {"8"}}, false, false, false},
+		{
+			"(tm.events.type='NewBlock' OR tm.events.type='Tx') AND app.name = 'fuzzed'",
+			map[string][]string{"tm.events.type": {"Tx"}, "app.name": {"fuzzed"}},
+			false,
+			true,
+			false,
+		},
+		{
+			"tm.events.type='NewBlock' OR tm.events.type='Tx' AND app.name = 'other'",
+			map[string][]string{"tm.events.type": {"NewBlock"}, "app.name": {"fuzzed"}},
+			false,
+			true,
+			false,
+		},
+		{"NOT tx.gas > 7", map[string][]string{"tx.gas": {"8"}}, false, false, false},
+		{"NOT tx.gas > 7", map[string][]string{"tx.gas": {"6"}}, false, true, false},
+		{"NOT tx.gas > 7", map[string][]string{"tx.fee": {"6"}}, false, true, false},
+		{"NOT NOT tx.gas > 7", map[string][]string{"tx.gas": {"8"}}, false, true, false},
+		{"tx.gas > 7 AND NOT slash EXISTS", map[string][]string{"tx.gas": {"8"}, "slash.power": {"1"}}, false, false, false},
+		{"abci.owner.name IN ('Igor', 'Ivan')", map[string][]string{"abci.owner.name": {"Ivan"}}, false, true, false},
+		{"abci.owner.name IN ('Igor', 'Ivan')", map[string][]string{"abci.owner.name": {"Pavel"}}, false, false, false},
+		{"tx.gas IN (7, 8.5)", map[string][]string{"tx.gas": {"8.5"}}, false, true, false},
+		{"tx.date IN (DATE 2017-01-01)", map[string][]string{"tx.date": {txDate}}, false, true, false},
+		{"abci.owner.name STARTS WITH 'Ig'", map[string][]string{"abci.owner.name": {"Igor"}}, false, true, false},
+		{"abci.owner.name STARTS WITH 'Ig'", map[string][]string{"abci.owner.name": {"Ivan"}}, false, false, false},
 	}
 
 	for _, tc := range testCases {
@@ -228,6 +255,40 @@ func TestMustParse(t *testing.T) {
 	assert.NotPanics(t, func() { query.MustParse("tm.events.type='NewBlock'") })
 }
 
+func TestConditionMatchesValue(t *testing.T) {
+	testCases := []struct {
+		s       string
+		value   string
+		matches bool
+		err     bool
+	}{
+		{"tx.owner = 'Ivan'", "Ivan", true, false},
+		{"tx.owner = 'Ivan'", "Igor", false, false},
+		{"tx.owner STARTS WITH 'Iv'", "Ivan", true, false},
+		{"tx.owner CONTAINS 'va'", "Ivan", true, false},
+		{"tx.owner IN ('Igor', 'Ivan')", "Ivan", true, false},
+		{"tx.owner EXISTS", "Ivan", true, false},
+		{"tx.gas > 7", "8", true, false},
+		{"tx.gas > 7", "7", false, false},
+		{"tx.gas <= 7.5", "7.5", true, false},
+		{"NOT tx.gas = 7", "7", true, false},
+		{"tx.time > TIME 2013-05-03T14:45:00Z", "2013-05-03T14:46:00Z", true, false},
+		{"tx.time > TIME 2013-05-03T14:45:00Z", "today", false, true},
+	}
+
+	for _, tc := range testCases {
+		conjunctions, err := query.MustParse(tc.s).Disjunction()
+		require.NoError(t, err)
+		matches, err := conjunctions[0][0].MatchesValue(tc.value)
+		if tc.err {
+			assert.Error(t, err, tc.s)
+			continue
+		}
+		require.NoError(t, err, tc.s)
+		assert.Equal(t, tc.matches, matches, tc.s)
+	}
+}
+
 func TestConditions(t *testing.T) {
 	txTime, err := time.Parse(time.RFC3339, "2013-05-03T14:45:00Z")
 	require.NoError(t, err)
@@ -281,6 +342,13 @@ func TestConditions(t *testing.T) {
 				{CompositeKey: "slashing", Op: query.OpExists},
 			},
 		},
+		{
+			s: "(tx.gas > 7) AND NOT NOT tx.owner IN ('Ivan', 5)",
+			conditions: []query.Condition{
+				{CompositeKey: "tx.gas", Op: query.OpGreater, Operand: big.NewInt(7)},
+				{CompositeKey: "tx.owner", Op: query.OpIn, Operand: []interface{}{"Ivan", big.NewInt(5)}},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -291,4 +359,45 @@ func TestConditions(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, tc.conditions, c)
 	}
+
+	for _, s := range []string{"tx.gas > 7 OR tx.gas < 5", "NOT tx.gas > 7"} {
+		_, err := query.MustParse(s).Conditions()
+		assert.Error(t, err, s)
+	}
+}
+
+func TestDisjunction(t *testing.T) {
+	var (
+		a    = query.Condition{CompositeKey: "a", Op: query.OpEqual, Operand: big.NewInt(1)}
+		b    = query.Condition{CompositeKey: "b", Op: query.OpStartsWith, Operand: "x"}
+		c    = query.Condition{CompositeKey: "c", Op: query.OpExists}
+		notB = query.Condition{CompositeKey: "b", Op: query.OpStartsWith, Operand: "x", Negated: true}
+		notC = query.Condition{CompositeKey: "c", Op: query.OpExists, Negated: true}
+	)
+
+	testCases := []struct {
+		s            string
+		conjunctions [][]query.Condition
+	}{
+		{"a = 1", [][]query.Condition{{a}}},
+		{"a = 1 AND b STARTS WITH 'x' OR c EXISTS", [][]query.Condition{{a, b}, {c}}},
+		{"(a = 1 OR b STARTS WITH 'x') AND c EXISTS", [][]query.Condition{{a, c}, {b, c}}},
+		{"a = 1 AND NOT (b STARTS WITH 'x' OR c EXISTS)", [][]query.Condition{{a, notB, notC}}},
+		{"NOT (b STARTS WITH 'x' AND c EXISTS)", [][]query.Condition{{notB}, {notC}}},
+		{"NOT NOT c EXISTS", [][]query.Condition{{c}}},
+	}
+
+	for _, tc := range testCases {
+		conjunctions, err := query.MustParse(tc.s).Disjunction()
+		require.NoError(t, err, tc.s)
+		assert.Equal(t, tc.conjunctions, conjunctions, tc.s)
+	}
+
+	// 2^7 conjunctions
+	s := "(a = 1 OR a = 2)"
+	for i := 0; i < 6; i++ {
+		s += " AND (a = 1 OR a = 2)"
+	}
+	_, err := query.MustParse(s).Disjunction()
+	assert.Error(t, err)
 }
@@ -37,11 +37,14 @@ func RPCRoutes(c *lrpc.Client) map[string]*rpcserver.RPCFunc {
 		"consensus_params":     rpcserver.NewRPCFunc(makeConsensusParamsFunc(c), "height", rpcserver.Cacheable("height")),
 		"unconfirmed_txs":      rpcserver.NewRPCFunc(makeUnconfirmedTxsFunc(c), "limit"),
 		"num_unconfirmed_txs":  rpcserver.NewRPCFunc(makeNumUnconfirmedTxsFunc(c), ""),
+		"tx_status":            rpcserver.NewRPCFunc(makeTxStatusFunc(c), "hash"),
 
 		// tx broadcast API
 		"broadcast_tx_commit": rpcserver.NewRPCFunc(makeBroadcastTxCommitFunc(c), "tx"),
 		"broadcast_tx_sync":   rpcserver.NewRPCFunc(makeBroadcastTxSyncFunc(c), "tx"),
 		"broadcast_tx_async":  rpcserver.NewRPCFunc(makeBroadcastTxAsyncFunc(c), "tx"),
+		"broadcast_txs_sync":  rpcserver.NewRPCFunc(makeBroadcastTxsSyncFunc(c), "txs"),
+		"broadcast_txs_async": rpcserver.NewRPCFunc(makeBroadcastTxsAsyncFunc(c), "txs"),
 
 		// abci API
 		"abci_query": rpcserver.NewRPCFunc(makeABCIQueryFunc(c), "path,data,height,prove"),
@@ -244,6 +247,14 @@ func makeNumUnconfirmedTxsFunc(c *lrpc.Client) rpcNumUnconfirmedTxsFunc {
 	}
 }
 
+type rpcTxStatusFunc func(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultTxStatus, error)
+
+func makeTxStatusFunc(c *lrpc.Client) rpcTxStatusFunc {
+	return func(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultTxStatus, error) {
+		return c.TxStatus(ctx.Context(), hash)
+	}
+}
+
 type rpcBroadcastTxCommitFunc func(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error)
 
 func makeBroadcastTxCommitFunc(c *lrpc.Client) rpcBroadcastTxCommitFunc {
@@ -268,6 +279,20 @@ func makeBroadcastTxAsyncFunc(c *lrpc.Client) rpcBroadcastTxAsyncFunc {
 	}
 }
 
+type rpcBroadcastTxsFunc func(ctx *rpctypes.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error)
+
+func makeBroadcastTxsSyncFunc(c *lrpc.Client) rpcBroadcastTxsFunc {
+	return func(ctx *rpctypes.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+		return c.BroadcastTxsSync(ctx.Context(), txs)
+	}
+}
+
+func makeBroadcastTxsAsyncFunc(c *lrpc.Client) rpcBroadcastTxsFunc {
+	return func(ctx *rpctypes.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+		return c.BroadcastTxsAsync(ctx.Context(), txs)
+	}
+}
+
 type rpcABCIQueryFunc func(ctx *rpctypes.Context, path string,
 	data bytes.HexBytes, height int64, prove bool) (*ctypes.ResultABCIQuery, error)
 
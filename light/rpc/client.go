@@ -215,6 +215,18 @@ func (c *Client) CheckTx(ctx context.Context, tx types.Tx) (*ctypes.ResultCheckT
 	return c.next.CheckTx(ctx, tx)
 }
 
+func (c *Client) TxStatus(ctx context.Context, hash []byte) (*ctypes.ResultTxStatus, error) {
+	return c.next.TxStatus(ctx, hash)
+}
+
+func (c *Client) BroadcastTxsAsync(ctx context.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+	return c.next.BroadcastTxsAsync(ctx, txs)
+}
+
+func (c *Client) BroadcastTxsSync(ctx context.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+	return c.next.BroadcastTxsSync(ctx, txs)
+}
+
 func (c *Client) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error) {
 	return c.next.NetInfo(ctx)
 }
@@ -0,0 +1,163 @@
+package mempool
+
+import (
+	"container/list"
+	"time"
+
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/types"
+)
+
+// TxEventType is the kind of a step in the lifecycle of a transaction.
+type TxEventType string
+
+const (
+	// TxEventReceived is recorded when the mempool first receives a
+	// transaction, either from a peer or from the RPC.
+	TxEventReceived TxEventType = "received"
+	// TxEventRejected is recorded when a transaction is not added to the
+	// mempool, either because the application rejected it in CheckTx or
+	// because it failed one of the mempool's own checks.
+	TxEventRejected TxEventType = "rejected"
+	// TxEventAdded is recorded when a transaction passes CheckTx and is added
+	// to the mempool.
+	TxEventAdded TxEventType = "added"
+	// TxEventEvicted is recorded when a transaction in the mempool is dropped
+	// before being committed, e.g. because it became invalid on recheck or to
+	// make room for a higher-priority transaction.
+	TxEventEvicted TxEventType = "evicted"
+	// TxEventExpired is recorded when a transaction is dropped from the
+	// mempool because it exceeded its TTL.
+	TxEventExpired TxEventType = "expired"
+	// TxEventCommitted is recorded when a transaction is included in a block.
+	TxEventCommitted TxEventType = "committed"
+	// TxEventRemoved is recorded when a transaction is removed from the
+	// mempool explicitly, e.g. by RemoveTxByKey or Flush.
+	TxEventRemoved TxEventType = "removed"
+)
+
+// TxSourceRPC is the source recorded for transactions not received from a
+// peer.
+const TxSourceRPC = "rpc"
+
+// TxEvent is a single step in the lifecycle of a transaction.
+type TxEvent struct {
+	Type TxEventType `json:"type"`
+	Time time.Time   `json:"time"`
+	// Height is the height of the mempool when the event happened, or the
+	// height of the block that included the transaction.
+	Height int64 `json:"height"`
+	// Source is the peer ID the transaction was received from, or
+	// TxSourceRPC. Only set for TxEventReceived.
+	Source string `json:"source,omitempty"`
+	// Code is the CheckTx or DeliverTx response code, if any.
+	Code uint32 `json:"code"`
+	// Reason explains rejections, evictions and removals.
+	Reason string `json:"reason,omitempty"`
+}
+
+// TxLifecycle is the recorded history of a transaction, oldest event first.
+type TxLifecycle struct {
+	Events []TxEvent
+}
+
+// Last returns the most recent event of the lifecycle.
+func (l TxLifecycle) Last() TxEvent {
+	return l.Events[len(l.Events)-1]
+}
+
+// TxSource returns the source to record for a transaction received with the
+// given info.
+func TxSource(txInfo TxInfo) string {
+	if txInfo.SenderP2PID == "" {
+		return TxSourceRPC
+	}
+	return string(txInfo.SenderP2PID)
+}
+
+// TxLifecycleStore records the lifecycle of transactions seen by the mempool,
+// for a bounded number of transactions.
+type TxLifecycleStore interface {
+	// Record appends ev to the lifecycle of the transaction with the given key.
+	Record(key types.TxKey, ev TxEvent)
+
+	// Get returns the lifecycle of the transaction with the given key and
+	// whether it was found.
+	Get(key types.TxKey) (TxLifecycle, bool)
+}
+
+// maxTxEvents bounds the number of events kept per transaction. When
+// exceeded, the oldest events but the first one are dropped.
+const maxTxEvents = 16
+
+var _ TxLifecycleStore = (*LRUTxLifecycleStore)(nil)
+
+// LRUTxLifecycleStore is a thread-safe TxLifecycleStore that keeps the
+// lifecycles of the most recently updated transactions.
+type LRUTxLifecycleStore struct {
+	mtx      cmtsync.Mutex
+	size     int
+	entryMap map[types.TxKey]*list.Element
+	list     *list.List
+}
+
+type txLifecycleEntry struct {
+	key    types.TxKey
+	events []TxEvent
+}
+
+func NewLRUTxLifecycleStore(size int) *LRUTxLifecycleStore {
+	return &LRUTxLifecycleStore{
+		size:     size,
+		entryMap: make(map[types.TxKey]*list.Element, size),
+		list:     list.New(),
+	}
+}
+
+func (s *LRUTxLifecycleStore) Record(key types.TxKey, ev TxEvent) {
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if e, ok := s.entryMap[key]; ok {
+		entry := e.Value.(*txLifecycleEntry)
+		if len(entry.events) >= maxTxEvents {
+			entry.events = append(entry.events[:1], entry.events[2:]...)
+		}
+		entry.events = append(entry.events, ev)
+		s.list.MoveToBack(e)
+		return
+	}
+
+	if s.list.Len() >= s.size {
+		if front := s.list.Front(); front != nil {
+			delete(s.entryMap, front.Value.(*txLifecycleEntry).key)
+			s.list.Remove(front)
+		}
+	}
+
+	s.entryMap[key] = s.list.PushBack(&txLifecycleEntry{key: key, events: []TxEvent{ev}})
+}
+
+func (s *LRUTxLifecycleStore) Get(key types.TxKey) (TxLifecycle, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	e, ok := s.entryMap[key]
+	if !ok {
+		return TxLifecycle{}, false
+	}
+	events := e.Value.(*txLifecycleEntry).events
+	return TxLifecycle{Events: append([]TxEvent(nil), events...)}, true
+}
+
+// NopTxLifecycleStore defines a no-op transaction lifecycle store.
+type NopTxLifecycleStore struct{}
+
+var _ TxLifecycleStore = (*NopTxLifecycleStore)(nil)
+
+func (NopTxLifecycleStore) Record(types.TxKey, TxEvent)         {}
+func (NopTxLifecycleStore) Get(types.TxKey) (TxLifecycle, bool) { return TxLifecycle{}, false }
@@ -0,0 +1,45 @@
+package mempool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestLRUTxLifecycleStore(t *testing.T) {
+	store := NewLRUTxLifecycleStore(2)
+	tx1, tx2, tx3 := types.Tx("tx1"), types.Tx("tx2"), types.Tx("tx3")
+
+	store.Record(tx1.Key(), TxEvent{Type: TxEventReceived, Source: TxSourceRPC})
+	store.Record(tx1.Key(), TxEvent{Type: TxEventAdded})
+	store.Record(tx2.Key(), TxEvent{Type: TxEventReceived})
+
+	lifecycle, ok := store.Get(tx1.Key())
+	require.True(t, ok)
+	require.Len(t, lifecycle.Events, 2)
+	require.Equal(t, TxEventReceived, lifecycle.Events[0].Type)
+	require.Equal(t, TxSourceRPC, lifecycle.Events[0].Source)
+	require.False(t, lifecycle.Events[0].Time.IsZero())
+	require.Equal(t, TxEventAdded, lifecycle.Last().Type)
+
+	// Recording an event for tx1 makes tx2 the least recently updated, so it
+	// is dropped first.
+	store.Record(tx1.Key(), TxEvent{Type: TxEventCommitted, Height: 5})
+	store.Record(tx3.Key(), TxEvent{Type: TxEventReceived})
+	_, ok = store.Get(tx2.Key())
+	require.False(t, ok)
+	_, ok = store.Get(tx3.Key())
+	require.True(t, ok)
+
+	// The number of events per transaction is bounded, keeping the first one.
+	for i := 0; i < 2*maxTxEvents; i++ {
+		store.Record(tx1.Key(), TxEvent{Type: TxEventEvicted})
+	}
+	lifecycle, ok = store.Get(tx1.Key())
+	require.True(t, ok)
+	require.Len(t, lifecycle.Events, maxTxEvents)
+	require.Equal(t, TxEventReceived, lifecycle.Events[0].Type)
+	require.Equal(t, TxEventEvicted, lifecycle.Last().Type)
+}
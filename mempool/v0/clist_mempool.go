@@ -3,6 +3,7 @@ package v0
 import (
 	"bytes"
 	"errors"
+	"sort"
 	"sync"
 	"sync/atomic"
 
@@ -57,6 +58,9 @@ type CListMempool struct {
 	// This reduces the pressure on the proxyApp.
 	cache mempool.TxCache
 
+	// Records the lifecycle of recently seen txs.
+	txLifecycle mempool.TxLifecycleStore
+
 	logger  log.Logger
 	metrics *mempool.Metrics
 }
@@ -82,6 +86,7 @@ func NewCListMempool(
 		height:        height,
 		recheckCursor: nil,
 		recheckEnd:    nil,
+		txLifecycle:   mempool.NopTxLifecycleStore{},
 		logger:        log.NewNopLogger(),
 		metrics:       mempool.NopMetrics(),
 	}
@@ -130,6 +135,11 @@ func WithMetrics(metrics *mempool.Metrics) CListMempoolOption {
 	return func(mem *CListMempool) { mem.metrics = metrics }
 }
 
+// WithTxLifecycle sets the store recording the lifecycle of transactions.
+func WithTxLifecycle(s mempool.TxLifecycleStore) CListMempoolOption {
+	return func(mem *CListMempool) { mem.txLifecycle = s }
+}
+
 // Safe for concurrent use by multiple goroutines.
 func (mem *CListMempool) Lock() {
 	mem.updateMtx.Lock()
@@ -166,6 +176,10 @@ func (mem *CListMempool) Flush() {
 	for e := mem.txs.Front(); e != nil; e = e.Next() {
 		mem.txs.Remove(e)
 		e.DetachPrev()
+		mem.recordTx(e.Value.(*mempoolTx).tx, mempool.TxEvent{
+			Type:   mempool.TxEventRemoved,
+			Reason: "mempool flushed",
+		})
 	}
 
 	mem.txsMap.Range(func(key, _ interface{}) bool {
@@ -210,10 +224,30 @@ func (mem *CListMempool) CheckTx(
 	// use defer to unlock mutex because application (*local client*) might panic
 	defer mem.updateMtx.RUnlock()
 
+	err := mem.checkTx(tx, cb, txInfo)
+	if err != nil && !errors.Is(err, mempool.ErrTxInCache) {
+		mem.recordTx(tx, mempool.TxEvent{
+			Type:   mempool.TxEventRejected,
+			Reason: err.Error(),
+		})
+	}
+	return err
+}
+
+func (mem *CListMempool) checkTx(
+	tx types.Tx,
+	cb func(*abci.Response),
+	txInfo mempool.TxInfo,
+) error {
 	txSize := len(tx)
 
-	if err := mem.isFull(txSize); err != nil {
-		return err
+	// A prioritized mempool may be able to make room for the transaction by
+	// evicting lower-priority ones, which is decided once the application has
+	// assigned it a priority.
+	if !mem.config.V0Prioritized {
+		if err := mem.isFull(txSize); err != nil {
+			return err
+		}
 	}
 
 	if txSize > mem.config.MaxTxBytes {
@@ -251,6 +285,11 @@ func (mem *CListMempool) CheckTx(
 		return mempool.ErrTxInCache
 	}
 
+	mem.recordTx(tx, mempool.TxEvent{
+		Type:   mempool.TxEventReceived,
+		Source: mempool.TxSource(txInfo),
+	})
+
 	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
 	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, txInfo.SenderP2PID, cb))
 
@@ -340,6 +379,10 @@ func (mem *CListMempool) RemoveTxByKey(txKey types.TxKey) error {
 		memTx := e.(*clist.CElement).Value.(*mempoolTx)
 		if memTx != nil {
 			mem.removeTx(memTx.tx, e.(*clist.CElement), false)
+			mem.recordTx(memTx.tx, mempool.TxEvent{
+				Type:   mempool.TxEventRemoved,
+				Reason: "removed by key",
+			})
 			return nil
 		}
 		return errors.New("transaction not found")
@@ -382,15 +425,6 @@ func (mem *CListMempool) resCbFirstTime(
 			postCheckErr = mem.postCheck(tx, r.CheckTx)
 		}
 		if (r.CheckTx.Code == abci.CodeTypeOK) && postCheckErr == nil {
-			// Check mempool isn't full again to reduce the chance of exceeding the
-			// limits.
-			if err := mem.isFull(len(tx)); err != nil {
-				// remove from cache (mempool might have a space later)
-				mem.cache.Remove(tx)
-				mem.logger.Error(err.Error())
-				return
-			}
-
 			// Check transaction not already in the mempool
 			if e, ok := mem.txsMap.Load(types.Tx(tx).Key()); ok {
 				memTx := e.(*clist.CElement).Value.(*mempoolTx)
@@ -405,18 +439,44 @@ func (mem *CListMempool) resCbFirstTime(
 				return
 			}
 
+			// Check mempool isn't full again to reduce the chance of exceeding the
+			// limits. A prioritized mempool tries to make room first.
+			if err := mem.isFull(len(tx)); err != nil {
+				if mem.config.V0Prioritized {
+					err = mem.evictLowerPriority(tx, r.CheckTx.Priority, err)
+				}
+				if err != nil {
+					// remove from cache (mempool might have a space later)
+					mem.cache.Remove(tx)
+					mem.logger.Error(err.Error())
+					mem.metrics.RejectedTxs.Add(1)
+					mem.recordTx(tx, mempool.TxEvent{
+						Type:   mempool.TxEventRejected,
+						Code:   r.CheckTx.Code,
+						Reason: err.Error(),
+					})
+					return
+				}
+			}
+
 			memTx := &mempoolTx{
 				height:    mem.height,
 				gasWanted: r.CheckTx.GasWanted,
+				priority:  r.CheckTx.Priority,
 				tx:        tx,
 			}
 			memTx.senders.Store(peerID, true)
 			mem.addTx(memTx)
+			mem.recordTx(tx, mempool.TxEvent{
+				Type: mempool.TxEventAdded,
+				Code: r.CheckTx.Code,
+			})
 			mem.logger.Debug(
 				"added good transaction",
 				"tx", types.Tx(tx).Hash(),
 				"res", r,
 				"height", memTx.height,
+				"priority", memTx.priority,
 				"total", mem.Size(),
 			)
 			mem.notifyTxsAvailable()
@@ -430,6 +490,11 @@ func (mem *CListMempool) resCbFirstTime(
 				"err", postCheckErr,
 			)
 			mem.metrics.FailedTxs.Add(1)
+			mem.recordTx(tx, mempool.TxEvent{
+				Type:   mempool.TxEventRejected,
+				Code:   r.CheckTx.Code,
+				Reason: checkTxFailureReason(r.CheckTx, postCheckErr),
+			})
 
 			if !mem.config.KeepInvalidTxsInCache {
 				// remove from cache (it might be good later)
@@ -442,6 +507,60 @@ func (mem *CListMempool) resCbFirstTime(
 	}
 }
 
+// evictLowerPriority makes room for tx by evicting the lowest-priority
+// transactions whose priority is (strictly) lower than the given one. Among
+// transactions of equal priority, the most recently added are evicted first.
+// If not enough room can be made, nothing is evicted and fullErr is returned.
+//
+// Called from:
+//   - resCbFirstTime (lock not held) if the mempool is full
+func (mem *CListMempool) evictLowerPriority(tx types.Tx, priority int64, fullErr error) error {
+	var victims []*clist.CElement
+	for e := mem.txs.Back(); e != nil; e = e.Prev() {
+		if e.Value.(*mempoolTx).Priority() < priority {
+			victims = append(victims, e)
+		}
+	}
+	// Stable sort keeps the newest transactions first within a priority.
+	sort.SliceStable(victims, func(i, j int) bool {
+		return victims[i].Value.(*mempoolTx).Priority() < victims[j].Value.(*mempoolTx).Priority()
+	})
+
+	var (
+		numTxs   = mem.Size()
+		txsBytes = mem.SizeBytes()
+		txSize   = int64(len(tx))
+		n        int
+	)
+	for numTxs >= mem.config.Size || txsBytes+txSize > mem.config.MaxTxsBytes {
+		if n == len(victims) {
+			return fullErr
+		}
+		numTxs--
+		txsBytes -= int64(len(victims[n].Value.(*mempoolTx).tx))
+		n++
+	}
+
+	for _, e := range victims[:n] {
+		memTx := e.Value.(*mempoolTx)
+		mem.logger.Debug(
+			"evicted valid existing transaction; mempool full",
+			"old_tx", memTx.tx.Hash(),
+			"old_priority", memTx.Priority(),
+			"new_tx", tx.Hash(),
+			"new_priority", priority,
+		)
+		// NOTE: we remove tx from the cache so it can be resubmitted later
+		mem.removeTx(memTx.tx, e, true)
+		mem.metrics.EvictedTxs.Add(1)
+		mem.recordTx(memTx.tx, mempool.TxEvent{
+			Type:   mempool.TxEventEvicted,
+			Reason: "mempool full; evicted by a higher-priority tx",
+		})
+	}
+	return nil
+}
+
 // callback, which is called after the app rechecked the tx.
 //
 // The case where the app checks the tx for the first time is handled by the
@@ -486,12 +605,18 @@ func (mem *CListMempool) resCbRecheck(req *abci.Request, res *abci.Response) {
 		}
 
 		if (r.CheckTx.Code == abci.CodeTypeOK) && postCheckErr == nil {
-			// Good, nothing to do.
+			// Good, only the priority may have changed.
+			atomic.StoreInt64(&memTx.priority, r.CheckTx.Priority)
 		} else {
 			// Tx became invalidated due to newly committed block.
 			mem.logger.Debug("tx is no longer valid", "tx", types.Tx(tx).Hash(), "res", r, "err", postCheckErr)
 			// NOTE: we remove tx from the cache because it might be good later
 			mem.removeTx(tx, mem.recheckCursor, !mem.config.KeepInvalidTxsInCache)
+			mem.recordTx(tx, mempool.TxEvent{
+				Type:   mempool.TxEventEvicted,
+				Code:   r.CheckTx.Code,
+				Reason: "recheck failed: " + checkTxFailureReason(r.CheckTx, postCheckErr),
+			})
 		}
 		if mem.recheckCursor == mem.recheckEnd {
 			mem.recheckCursor = nil
@@ -630,6 +755,10 @@ func (mem *CListMempool) Update(
 		if e, ok := mem.txsMap.Load(tx.Key()); ok {
 			mem.removeTx(tx, e.(*clist.CElement), false)
 		}
+		mem.recordTx(tx, mempool.TxEvent{
+			Type: mempool.TxEventCommitted,
+			Code: deliverTxResponses[i].Code,
+		})
 	}
 
 	// Either recheck non-committed txs to see if they became invalid
@@ -652,6 +781,20 @@ func (mem *CListMempool) Update(
 	return nil
 }
 
+// recordTx records ev in the lifecycle of tx, at the current height.
+func (mem *CListMempool) recordTx(tx types.Tx, ev mempool.TxEvent) {
+	ev.Height = mem.height
+	mem.txLifecycle.Record(tx.Key(), ev)
+}
+
+// checkTxFailureReason describes why a tx failed CheckTx.
+func checkTxFailureReason(res *abci.ResponseCheckTx, postCheckErr error) string {
+	if postCheckErr != nil {
+		return postCheckErr.Error()
+	}
+	return res.Log
+}
+
 func (mem *CListMempool) recheckTxs() {
 	if mem.Size() == 0 {
 		panic("recheckTxs is called, but the mempool is empty")
@@ -679,6 +822,7 @@ func (mem *CListMempool) recheckTxs() {
 type mempoolTx struct {
 	height    int64    // height that this tx had been validated in
 	gasWanted int64    // amount of gas this tx states it will require
+	priority  int64    // priority assigned by the app in CheckTx
 	tx        types.Tx //
 
 	// ids of peers who've sent us this tx (as a map for quick lookups).
@@ -690,3 +834,9 @@ type mempoolTx struct {
 func (memTx *mempoolTx) Height() int64 {
 	return atomic.LoadInt64(&memTx.height)
 }
+
+// Priority returns the priority assigned to this transaction by the
+// application.
+func (memTx *mempoolTx) Priority() int64 {
+	return atomic.LoadInt64(&memTx.priority)
+}
@@ -1,6 +1,7 @@
 package v0
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/binary"
 	"fmt"
@@ -639,6 +640,108 @@ func TestMempoolTxsBytes(t *testing.T) {
 
 }
 
+// priorityApp extends the KV store application by assigning each transaction
+// the priority found after its last '=' (key=value=priority).
+type priorityApp struct {
+	*kvstore.Application
+}
+
+func (app *priorityApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	parts := bytes.Split(req.Tx, []byte("="))
+	priority, err := strconv.ParseInt(string(parts[len(parts)-1]), 10, 64)
+	if err != nil {
+		return abci.ResponseCheckTx{Code: 100}
+	}
+	return abci.ResponseCheckTx{Code: abci.CodeTypeOK, GasWanted: 1, Priority: priority}
+}
+
+func TestMempoolPriorityEviction(t *testing.T) {
+	app := &priorityApp{kvstore.NewApplication()}
+	cc := proxy.NewLocalClientCreator(app)
+
+	cfg := config.ResetTestRoot("mempool_test")
+	cfg.Mempool.V0Prioritized = true
+	cfg.Mempool.Size = 3
+	cfg.Mempool.MaxTxsBytes = 30
+	mp, cleanup := newMempoolWithAppAndConfig(cc, cfg)
+	defer cleanup()
+
+	txExists := func(tx string) bool {
+		_, ok := mp.txsMap.Load(types.Tx(tx).Key())
+		return ok
+	}
+
+	for _, tx := range []string{"a=0=5", "b=0=1", "c=0=1"} {
+		require.NoError(t, mp.CheckTx([]byte(tx), nil, mempool.TxInfo{}))
+	}
+	require.Equal(t, 3, mp.Size())
+
+	// A full mempool no longer rejects transactions upfront, but a transaction
+	// without a lower-priority one to replace is discarded.
+	require.NoError(t, mp.CheckTx([]byte("d=0=1"), nil, mempool.TxInfo{}))
+	require.False(t, txExists("d=0=1"))
+	require.Equal(t, 3, mp.Size())
+
+	// A higher-priority transaction evicts the newest of the lowest-priority
+	// ones, which is also removed from the cache.
+	require.NoError(t, mp.CheckTx([]byte("e=0=3"), nil, mempool.TxInfo{}))
+	require.True(t, txExists("e=0=3"))
+	require.False(t, txExists("c=0=1"))
+	require.True(t, txExists("b=0=1"))
+	require.False(t, mp.cache.Has([]byte("c=0=1")))
+	require.Equal(t, 3, mp.Size())
+
+	// Enough transactions are evicted to make room in bytes as well.
+	big := "f=0123456789abcdefghij=4"
+	require.NoError(t, mp.CheckTx([]byte(big), nil, mempool.TxInfo{}))
+	require.True(t, txExists(big))
+	require.True(t, txExists("a=0=5"))
+	require.False(t, txExists("b=0=1"))
+	require.False(t, txExists("e=0=3"))
+	assert.EqualValues(t, len("a=0=5")+len(big), mp.SizeBytes())
+
+	// A transaction that cannot fit even after evicting every lower-priority
+	// transaction is discarded without evicting anything.
+	require.NoError(t, mp.CheckTx([]byte("g=0123456789abcdefghijklmnopqrstuvwxyz=9"), nil, mempool.TxInfo{}))
+	require.Equal(t, 2, mp.Size())
+	require.True(t, txExists(big))
+}
+
+func TestMempoolTxLifecycle(t *testing.T) {
+	app := kvstore.NewApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mp, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+	lifecycles := mempool.NewLRUTxLifecycleStore(10)
+	WithTxLifecycle(lifecycles)(mp)
+
+	eventTypes := func(tx types.Tx) []mempool.TxEventType {
+		lifecycle, ok := lifecycles.Get(tx.Key())
+		require.True(t, ok)
+		evTypes := make([]mempool.TxEventType, len(lifecycle.Events))
+		for i, ev := range lifecycle.Events {
+			evTypes[i] = ev.Type
+		}
+		return evTypes
+	}
+
+	tx := types.Tx("key=value")
+	require.NoError(t, mp.CheckTx(tx, nil, mempool.TxInfo{}))
+	require.Equal(t, []mempool.TxEventType{mempool.TxEventReceived, mempool.TxEventAdded}, eventTypes(tx))
+
+	mp.Lock()
+	err := mp.Update(1, []types.Tx{tx}, abciResponses(1, abci.CodeTypeOK), nil, nil)
+	mp.Unlock()
+	require.NoError(t, err)
+	lifecycle, _ := lifecycles.Get(tx.Key())
+	require.Equal(t, mempool.TxEventCommitted, lifecycle.Last().Type)
+	require.EqualValues(t, 1, lifecycle.Last().Height)
+
+	tooLarge := make(types.Tx, mp.config.MaxTxBytes+1)
+	require.Error(t, mp.CheckTx(tooLarge, nil, mempool.TxInfo{}))
+	require.Equal(t, []mempool.TxEventType{mempool.TxEventRejected}, eventTypes(tooLarge))
+}
+
 func TestMempoolNoCacheOverflow(t *testing.T) {
 	sockPath := fmt.Sprintf("unix:///tmp/echo_%v.sock", cmtrand.Str(6))
 	app := kvstore.NewApplication()
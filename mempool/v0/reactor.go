@@ -3,6 +3,7 @@ package v0
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/gogo/protobuf/proto"
@@ -260,7 +261,14 @@ func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 		// NOTE: Transaction batching was disabled due to
 		// https://github.com/tendermint/tendermint/issues/5796
 
-		if _, ok := memTx.senders.Load(peerID); !ok {
+		if memR.config.V0Prioritized {
+			last, success := memR.sendPendingByPriority(peer, peerID, peerState.GetHeight(), next)
+			if !success {
+				time.Sleep(mempool.PeerCatchupSleepIntervalMS * time.Millisecond)
+				continue
+			}
+			next = last
+		} else if _, ok := memTx.senders.Load(peerID); !ok {
 			success := p2p.SendEnvelopeShim(peer, p2p.Envelope{ //nolint: staticcheck
 				ChannelID: mempool.MempoolChannel,
 				Message:   &protomem.Txs{Txs: [][]byte{memTx.tx}},
@@ -283,6 +291,53 @@ func (memR *Reactor) broadcastTxRoutine(peer p2p.Peer) {
 	}
 }
 
+// sendPendingByPriority sends the peer every transaction from next up to the
+// back of the mempool, highest priority first, skipping those the peer is not
+// ready for (see broadcastTxRoutine) and those the peer sent us. It returns
+// the last element that was considered, from which the caller keeps waiting
+// for new transactions, and false if a transaction could not be sent.
+func (memR *Reactor) sendPendingByPriority(
+	peer p2p.Peer,
+	peerID uint16,
+	peerHeight int64,
+	next *clist.CElement,
+) (*clist.CElement, bool) {
+	var (
+		pending []*mempoolTx
+		last    = next
+	)
+	for e := next; e != nil; e = e.Next() {
+		memTx := e.Value.(*mempoolTx)
+		// Allow for a lag of 1 block.
+		if peerHeight < memTx.Height()-1 {
+			break
+		}
+		last = e
+		if e.Removed() {
+			continue
+		}
+		if _, ok := memTx.senders.Load(peerID); !ok {
+			pending = append(pending, memTx)
+		}
+	}
+
+	// Stable sort keeps the FIFO order within a priority.
+	sort.SliceStable(pending, func(i, j int) bool {
+		return pending[i].Priority() > pending[j].Priority()
+	})
+
+	for _, memTx := range pending {
+		success := p2p.SendEnvelopeShim(peer, p2p.Envelope{ //nolint: staticcheck
+			ChannelID: mempool.MempoolChannel,
+			Message:   &protomem.Txs{Txs: [][]byte{memTx.tx}},
+		}, memR.Logger)
+		if !success {
+			return next, false
+		}
+	}
+	return last, true
+}
+
 // TxsMessage is a Message containing transactions.
 type TxsMessage struct {
 	Txs []types.Tx
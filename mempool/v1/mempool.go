@@ -1,6 +1,7 @@
 package v1
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"sort"
@@ -40,6 +41,7 @@ type TxMempool struct {
 	proxyAppConn proxy.AppConnMempool
 	metrics      *mempool.Metrics
 	cache        mempool.TxCache // seen transactions
+	txLifecycle  mempool.TxLifecycleStore
 
 	// Atomically-updated fields
 	txsBytes int64 // atomic: the total size of all transactions in the mempool, in bytes
@@ -73,6 +75,7 @@ func NewTxMempool(
 		proxyAppConn: proxyAppConn,
 		metrics:      mempool.NopMetrics(),
 		cache:        mempool.NopTxCache{},
+		txLifecycle:  mempool.NopTxLifecycleStore{},
 		txs:          clist.New(),
 		mtx:          new(sync.RWMutex),
 		height:       height,
@@ -109,6 +112,11 @@ func WithMetrics(metrics *mempool.Metrics) TxMempoolOption {
 	return func(txmp *TxMempool) { txmp.metrics = metrics }
 }
 
+// WithTxLifecycle sets the store recording the lifecycle of transactions.
+func WithTxLifecycle(s mempool.TxLifecycleStore) TxMempoolOption {
+	return func(txmp *TxMempool) { txmp.txLifecycle = s }
+}
+
 // Lock obtains a write-lock on the mempool. A caller must be sure to explicitly
 // release the lock when finished.
 func (txmp *TxMempool) Lock() { txmp.mtx.Lock() }
@@ -185,13 +193,13 @@ func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo memp
 
 		// Reject transactions in excess of the configured maximum transaction size.
 		if len(tx) > txmp.config.MaxTxBytes {
-			return 0, mempool.ErrTxTooLarge{Max: txmp.config.MaxTxBytes, Actual: len(tx)}
+			return txmp.height, mempool.ErrTxTooLarge{Max: txmp.config.MaxTxBytes, Actual: len(tx)}
 		}
 
 		// If a precheck hook is defined, call it before invoking the application.
 		if txmp.preCheck != nil {
 			if err := txmp.preCheck(tx); err != nil {
-				return 0, mempool.ErrPreCheck{Reason: err}
+				return txmp.height, mempool.ErrPreCheck{Reason: err}
 			}
 		}
 
@@ -214,8 +222,18 @@ func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo memp
 		return txmp.height, nil
 	}()
 	if err != nil {
+		if !errors.Is(err, mempool.ErrTxInCache) {
+			txmp.recordTx(tx, height, mempool.TxEvent{
+				Type:   mempool.TxEventRejected,
+				Reason: err.Error(),
+			})
+		}
 		return err
 	}
+	txmp.recordTx(tx, height, mempool.TxEvent{
+		Type:   mempool.TxEventReceived,
+		Source: mempool.TxSource(txInfo),
+	})
 
 	// Invoke an ABCI CheckTx for this transaction.
 	rsp, err := txmp.proxyAppConn.CheckTxSync(abci.RequestCheckTx{Tx: tx})
@@ -243,7 +261,15 @@ func (txmp *TxMempool) CheckTx(tx types.Tx, cb func(*abci.Response), txInfo memp
 func (txmp *TxMempool) RemoveTxByKey(txKey types.TxKey) error {
 	txmp.mtx.Lock()
 	defer txmp.mtx.Unlock()
-	return txmp.removeTxByKey(txKey)
+	if err := txmp.removeTxByKey(txKey); err != nil {
+		return err
+	}
+	txmp.txLifecycle.Record(txKey, mempool.TxEvent{
+		Type:   mempool.TxEventRemoved,
+		Height: txmp.height,
+		Reason: "removed by key",
+	})
+	return nil
 }
 
 // removeTxByKey removes the specified transaction key from the mempool.
@@ -286,6 +312,10 @@ func (txmp *TxMempool) Flush() {
 	for cur != nil {
 		next := cur.Next()
 		txmp.removeTxByElement(cur)
+		txmp.recordTx(cur.Value.(*WrappedTx).tx, txmp.height, mempool.TxEvent{
+			Type:   mempool.TxEventRemoved,
+			Reason: "mempool flushed",
+		})
 		cur = next
 	}
 	txmp.cache.Reset()
@@ -412,6 +442,10 @@ func (txmp *TxMempool) Update(
 
 		// Regardless of success, remove the transaction from the mempool.
 		_ = txmp.removeTxByKey(tx.Key())
+		txmp.recordTx(tx, blockHeight, mempool.TxEvent{
+			Type: mempool.TxEventCommitted,
+			Code: deliverTxResponses[i].Code,
+		})
 	}
 
 	txmp.purgeExpiredTxs(blockHeight)
@@ -473,9 +507,16 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 
 		// If there was a post-check error, record its text in the result for
 		// debugging purposes.
+		reason := checkTxRes.Log
 		if err != nil {
 			checkTxRes.MempoolError = err.Error()
+			reason = err.Error()
 		}
+		txmp.recordTx(wtx.tx, txmp.height, mempool.TxEvent{
+			Type:   mempool.TxEventRejected,
+			Code:   checkTxRes.Code,
+			Reason: reason,
+		})
 		return
 	}
 
@@ -498,6 +539,11 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 				fmt.Sprintf("rejected valid incoming transaction; tx already exists for sender %q (%X)",
 					sender, w.tx.Hash())
 			txmp.metrics.RejectedTxs.Add(1)
+			txmp.recordTx(wtx.tx, txmp.height, mempool.TxEvent{
+				Type:   mempool.TxEventRejected,
+				Code:   checkTxRes.Code,
+				Reason: checkTxRes.MempoolError,
+			})
 			return
 		}
 	}
@@ -533,6 +579,11 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 				fmt.Sprintf("rejected valid incoming transaction; mempool is full (%X)",
 					wtx.tx.Hash())
 			txmp.metrics.RejectedTxs.Add(1)
+			txmp.recordTx(wtx.tx, txmp.height, mempool.TxEvent{
+				Type:   mempool.TxEventRejected,
+				Code:   checkTxRes.Code,
+				Reason: checkTxRes.MempoolError,
+			})
 			return
 		}
 
@@ -565,6 +616,10 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 			txmp.removeTxByElement(vic)
 			txmp.cache.Remove(w.tx)
 			txmp.metrics.EvictedTxs.Add(1)
+			txmp.recordTx(w.tx, txmp.height, mempool.TxEvent{
+				Type:   mempool.TxEventEvicted,
+				Reason: "mempool full; evicted by a higher-priority tx",
+			})
 
 			// We may not need to evict all the eligible transactions.  Bail out
 			// early if we have made enough room.
@@ -579,6 +634,10 @@ func (txmp *TxMempool) addNewTransaction(wtx *WrappedTx, checkTxRes *abci.Respon
 	wtx.SetPriority(priority)
 	wtx.SetSender(sender)
 	txmp.insertTx(wtx)
+	txmp.recordTx(wtx.tx, txmp.height, mempool.TxEvent{
+		Type: mempool.TxEventAdded,
+		Code: checkTxRes.Code,
+	})
 
 	txmp.metrics.TxSizeBytes.Observe(float64(wtx.Size()))
 	txmp.metrics.Size.Set(float64(txmp.Size()))
@@ -645,6 +704,15 @@ func (txmp *TxMempool) handleRecheckResult(tx types.Tx, checkTxRes *abci.Respons
 	if !txmp.config.KeepInvalidTxsInCache {
 		txmp.cache.Remove(wtx.tx)
 	}
+	reason := checkTxRes.Log
+	if err != nil {
+		reason = err.Error()
+	}
+	txmp.recordTx(wtx.tx, txmp.height, mempool.TxEvent{
+		Type:   mempool.TxEventEvicted,
+		Code:   checkTxRes.Code,
+		Reason: "recheck failed: " + reason,
+	})
 	txmp.metrics.Size.Set(float64(txmp.Size()))
 }
 
@@ -743,15 +811,30 @@ func (txmp *TxMempool) purgeExpiredTxs(blockHeight int64) {
 			txmp.removeTxByElement(cur)
 			txmp.cache.Remove(w.tx)
 			txmp.metrics.EvictedTxs.Add(1)
+			txmp.recordTx(w.tx, blockHeight, mempool.TxEvent{
+				Type:   mempool.TxEventExpired,
+				Reason: "exceeded ttl-num-blocks",
+			})
 		} else if txmp.config.TTLDuration > 0 && now.Sub(w.timestamp) > txmp.config.TTLDuration {
 			txmp.removeTxByElement(cur)
 			txmp.cache.Remove(w.tx)
 			txmp.metrics.EvictedTxs.Add(1)
+			txmp.recordTx(w.tx, blockHeight, mempool.TxEvent{
+				Type:   mempool.TxEventExpired,
+				Reason: "exceeded ttl-duration",
+			})
 		}
 		cur = next
 	}
 }
 
+// recordTx records ev, which happened at the given height, in the lifecycle
+// of tx.
+func (txmp *TxMempool) recordTx(tx types.Tx, height int64, ev mempool.TxEvent) {
+	ev.Height = height
+	txmp.txLifecycle.Record(tx.Key(), ev)
+}
+
 func (txmp *TxMempool) notifyTxsAvailable() {
 	if txmp.Size() == 0 {
 		return // nothing to do
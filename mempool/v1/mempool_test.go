@@ -206,6 +206,58 @@ func TestTxMempool_Size(t *testing.T) {
 	require.Equal(t, int64(2850), txmp.SizeBytes())
 }
 
+func TestTxMempool_TxLifecycle(t *testing.T) {
+	lifecycles := mempool.NewLRUTxLifecycleStore(10)
+	txmp := setup(t, 100, WithTxLifecycle(lifecycles))
+	txmp.config.Size = 2
+
+	lastEvent := func(spec string) mempool.TxEvent {
+		lifecycle, ok := lifecycles.Get(types.Tx(spec).Key())
+		require.True(t, ok, spec)
+		return lifecycle.Last()
+	}
+
+	// Pending.
+	mustCheckTx(t, txmp, "a=0000=5")
+	lifecycle, ok := lifecycles.Get(types.Tx("a=0000=5").Key())
+	require.True(t, ok)
+	require.Len(t, lifecycle.Events, 2)
+	require.Equal(t, mempool.TxEventReceived, lifecycle.Events[0].Type)
+	require.Equal(t, mempool.TxEventAdded, lifecycle.Events[1].Type)
+	mustCheckTx(t, txmp, "b=00010000=3")
+	require.Equal(t, mempool.TxEventAdded, lastEvent("b=00010000=3").Type)
+
+	// Rejected by the application, or before being checked by it.
+	mustCheckTx(t, txmp, "bad")
+	ev := lastEvent("bad")
+	require.Equal(t, mempool.TxEventRejected, ev.Type)
+	require.EqualValues(t, 101, ev.Code)
+	tooLarge := make(types.Tx, txmp.config.MaxTxBytes+1)
+	require.Error(t, txmp.CheckTx(tooLarge, nil, mempool.TxInfo{}))
+	require.Equal(t, mempool.TxEventRejected, lastEvent(string(tooLarge)).Type)
+
+	// Evicted by a higher-priority tx, while a lower-priority one is rejected
+	// as the mempool is full.
+	mustCheckTx(t, txmp, "c=0002=10")
+	require.Equal(t, mempool.TxEventAdded, lastEvent("c=0002=10").Type)
+	require.Equal(t, mempool.TxEventEvicted, lastEvent("b=00010000=3").Type)
+	require.Equal(t, mempool.TxEventAdded, lastEvent("a=0000=5").Type)
+	mustCheckTx(t, txmp, "d=0003=1")
+	ev = lastEvent("d=0003=1")
+	require.Equal(t, mempool.TxEventRejected, ev.Type)
+	require.Contains(t, ev.Reason, "mempool is full")
+
+	// Committed.
+	txmp.Lock()
+	require.NoError(t, txmp.Update(1, []types.Tx{types.Tx("a=0000=5")},
+		[]*abci.ResponseDeliverTx{{Code: abci.CodeTypeOK}}, nil, nil))
+	txmp.Unlock()
+	ev = lastEvent("a=0000=5")
+	require.Equal(t, mempool.TxEventCommitted, ev.Type)
+	require.EqualValues(t, 1, ev.Height)
+	require.Equal(t, mempool.TxEventAdded, lastEvent("c=0002=10").Type)
+}
+
 func TestTxMempool_Eviction(t *testing.T) {
 	txmp := setup(t, 1000)
 	txmp.config.Size = 5
@@ -0,0 +1,133 @@
+package node
+
+import (
+	"time"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/syndtr/goleveldb/leveldb/util"
+
+	"github.com/tendermint/tendermint/libs/service"
+)
+
+// compactionCheckInterval is the interval at which the pruned heights are
+// checked.
+const compactionCheckInterval = time.Minute
+
+// dbCompacters compact the databases of the backends built with tags, and
+// return false for the other ones.
+var dbCompacters []func(db dbm.DB) (bool, error)
+
+// compactDB compacts the whole key range of db, and returns false if its
+// backend can't be compacted while open.
+func compactDB(db dbm.DB) (bool, error) {
+	if db, ok := db.(*dbm.GoLevelDB); ok {
+		return true, db.DB().CompactRange(util.Range{})
+	}
+	for _, compact := range dbCompacters {
+		if ok, err := compact(db); ok || err != nil {
+			return ok, err
+		}
+	}
+	return false, nil
+}
+
+// recordDBs returns a DBProvider recording the databases provided by
+// dbProvider in dbs, by ID.
+func recordDBs(dbProvider DBProvider, dbs map[string]dbm.DB) DBProvider {
+	return func(ctx *DBContext) (dbm.DB, error) {
+		db, err := dbProvider(ctx)
+		if err == nil {
+			dbs[ctx.ID] = db
+		}
+		return db, err
+	}
+}
+
+// compactedDB is a database compacted once enough heights were pruned from
+// it.
+type compactedDB struct {
+	name string
+	db   dbm.DB
+	// base returns the lowest height kept in the database.
+	base func() int64
+
+	compactedBase int64
+}
+
+// dbCompactor compacts the databases of the node in the background, after
+// pruning, for their disk space to be reclaimed without stopping the node.
+type dbCompactor struct {
+	service.BaseService
+
+	interval int64
+	dbs      []*compactedDB
+	done     chan struct{}
+}
+
+// newDBCompactor returns a compactor compacting each database once interval
+// heights were pruned from it since the last compaction.
+func newDBCompactor(interval int64, dbs ...*compactedDB) *dbCompactor {
+	c := &dbCompactor{interval: interval, dbs: dbs, done: make(chan struct{})}
+	c.BaseService = *service.NewBaseService(nil, "DBCompactor", c)
+	return c
+}
+
+// OnStart implements service.Service.
+func (c *dbCompactor) OnStart() error {
+	for _, db := range c.dbs {
+		db.compactedBase = db.base()
+	}
+	go c.run()
+	return nil
+}
+
+// OnStop implements service.Service by waiting for the compaction in
+// progress, if any.
+func (c *dbCompactor) OnStop() {
+	<-c.done
+}
+
+func (c *dbCompactor) run() {
+	defer close(c.done)
+
+	ticker := time.NewTicker(compactionCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Quit():
+			return
+		case <-ticker.C:
+		}
+
+		for _, db := range c.dbs {
+			base := db.base()
+			if base-db.compactedBase < c.interval {
+				continue
+			}
+			select {
+			case <-c.Quit():
+				return
+			default:
+			}
+			c.compact(db, base)
+		}
+	}
+}
+
+// compact compacts db, whose heights below base were pruned.
+func (c *dbCompactor) compact(db *compactedDB, base int64) {
+	c.Logger.Info("compacting database", "db", db.name, "base", base)
+	start := time.Now()
+	ok, err := compactDB(db.db)
+	switch {
+	case err != nil:
+		// The compaction is retried at the next check.
+		c.Logger.Error("failed to compact database", "db", db.name, "err", err)
+		return
+	case !ok:
+		c.Logger.Debug("the database backend can't be compacted online", "db", db.name)
+	default:
+		c.Logger.Info("compacted database", "db", db.name, "duration", time.Since(start))
+	}
+	db.compactedBase = base
+}
@@ -0,0 +1,20 @@
+//go:build cleveldb
+// +build cleveldb
+
+package node
+
+import (
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/jmhodges/levigo"
+)
+
+func init() {
+	dbCompacters = append(dbCompacters, func(db dbm.DB) (bool, error) {
+		cdb, ok := db.(*dbm.CLevelDB)
+		if !ok {
+			return false, nil
+		}
+		cdb.DB().CompactRange(levigo.Range{})
+		return true, nil
+	})
+}
@@ -0,0 +1,20 @@
+//go:build rocksdb
+// +build rocksdb
+
+package node
+
+import (
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/tecbot/gorocksdb"
+)
+
+func init() {
+	dbCompacters = append(dbCompacters, func(db dbm.DB) (bool, error) {
+		rdb, ok := db.(*dbm.RocksDB)
+		if !ok {
+			return false, nil
+		}
+		rdb.DB().CompactRange(gorocksdb.Range{})
+		return true, nil
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"path/filepath"
 	"strings"
 	"time"
 
@@ -44,6 +45,7 @@ import (
 	blockidxkv "github.com/tendermint/tendermint/state/indexer/block/kv"
 	blockidxnull "github.com/tendermint/tendermint/state/indexer/block/null"
 	"github.com/tendermint/tendermint/state/indexer/sink/psql"
+	"github.com/tendermint/tendermint/state/indexer/sink/sqlite"
 	"github.com/tendermint/tendermint/state/txindex"
 	"github.com/tendermint/tendermint/state/txindex/kv"
 	"github.com/tendermint/tendermint/state/txindex/null"
@@ -216,6 +218,7 @@ type Node struct {
 	bcReactor         p2p.Reactor       // for fast-syncing
 	mempoolReactor    p2p.Reactor       // for gossipping transactions
 	mempool           mempl.Mempool
+	txLifecycle       mempl.TxLifecycleStore  // lifecycle of recently seen txs
 	stateSync         bool                    // whether the node should state sync on startup
 	stateSyncReactor  *statesync.Reactor      // for hosting and restoring state sync snapshots
 	stateSyncProvider statesync.StateProvider // provides state data for bootstrapping a node
@@ -229,6 +232,7 @@ type Node struct {
 	txIndexer         txindex.TxIndexer
 	blockIndexer      indexer.BlockIndexer
 	indexerService    *txindex.IndexerService
+	compactor         *dbCompactor
 	prometheusSrv     *http.Server
 }
 
@@ -238,7 +242,16 @@ func initDBs(config *cfg.Config, dbProvider DBProvider) (blockStore *store.Block
 	if err != nil {
 		return
 	}
-	blockStore = store.NewBlockStore(blockStoreDB)
+	var options []store.Option
+	if config.Storage.BlockStoreBackend == "segments" {
+		var segments *store.Segments
+		segments, err = store.OpenSegments(config.BlockStoreSegmentsDir(), store.DefaultMaxSegmentSize)
+		if err != nil {
+			return
+		}
+		options = append(options, store.WithSegments(segments))
+	}
+	blockStore = store.NewBlockStore(blockStoreDB, options...)
 
 	stateDB, err = dbProvider(&DBContext{"state", config})
 	if err != nil {
@@ -271,23 +284,35 @@ func createAndStartIndexerService(
 	chainID string,
 	dbProvider DBProvider,
 	eventBus *types.EventBus,
+	blockStore txindex.BlockSource,
+	stateStore sm.Store,
 	logger log.Logger,
 	stationType string,
 ) (*txindex.IndexerService, txindex.TxIndexer, indexer.BlockIndexer, error) {
 	var (
 		txIndexer    txindex.TxIndexer
 		blockIndexer indexer.BlockIndexer
+		store        dbm.DB
 	)
 
 	switch config.TxIndex.Indexer {
-	case "kv":
-		store, err := dbProvider(&DBContext{"tx_index", config})
+	case "kv", "psql", "sqlite":
+		// The tx_index database stores the indexed heights of all the
+		// indexers, and the indexes of the "kv" indexer.
+		var err error
+		store, err = dbProvider(&DBContext{"tx_index", config})
 		if err != nil {
 			return nil, nil, nil, err
 		}
+	}
 
-		txIndexer = kv.NewTxIndex(store)
-		blockIndexer = blockidxkv.New(dbm.NewPrefixDB(store, []byte("block_events")))
+	switch config.TxIndex.Indexer {
+	case "kv":
+		filter := indexer.NewEventFilter(config.TxIndex.IncludeEvents, config.TxIndex.ExcludeEvents)
+		txIndexer = kv.NewTxIndex(store, kv.WithEventFilter(filter),
+			kv.WithAddressIndex(config.TxIndex.AddressEvents))
+		blockIndexer = blockidxkv.New(dbm.NewPrefixDB(store, []byte("block_events")),
+			blockidxkv.WithEventFilter(filter))
 
 	case "psql":
 		if config.TxIndex.PsqlConn == "" {
@@ -300,6 +325,14 @@ func createAndStartIndexerService(
 		txIndexer = es.TxIndexer()
 		blockIndexer = es.BlockIndexer()
 
+	case "sqlite":
+		es, err := sqlite.NewEventSink(filepath.Join(config.DBDir(), "tx_index.sqlite"), chainID)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("creating sqlite indexer: %w", err)
+		}
+		txIndexer = es.TxIndexer()
+		blockIndexer = es.BlockIndexer()
+
 	default:
 		txIndexer = &null.TxIndex{}
 		blockIndexer = &blockidxnull.BlockerIndexer{}
@@ -307,6 +340,11 @@ func createAndStartIndexerService(
 
 	indexerService := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, stationType, false)
 	indexerService.SetLogger(logger.With("module", "txindex"))
+	indexerService.SetPruning(blockStore, config.TxIndex.RetainBlocks)
+	if store != nil {
+		progress := dbm.NewPrefixDB(store, []byte("indexer_progress/"+config.TxIndex.Indexer+"/"))
+		indexerService.SetCatchUp(progress, blockStore, stateStore)
+	}
 
 	if err := indexerService.Start(); err != nil {
 		return nil, nil, nil, err
@@ -373,6 +411,7 @@ func createMempoolAndMempoolReactor(
 	proxyApp proxy.AppConns,
 	state sm.State,
 	memplMetrics *mempl.Metrics,
+	txLifecycle mempl.TxLifecycleStore,
 	logger log.Logger,
 ) (mempl.Mempool, p2p.Reactor) {
 	switch config.Mempool.Version {
@@ -385,6 +424,7 @@ func createMempoolAndMempoolReactor(
 			mempoolv1.WithMetrics(memplMetrics),
 			mempoolv1.WithPreCheck(sm.TxPreCheck(state)),
 			mempoolv1.WithPostCheck(sm.TxPostCheck(state)),
+			mempoolv1.WithTxLifecycle(txLifecycle),
 		)
 
 		reactor := mempoolv1.NewReactor(
@@ -405,6 +445,7 @@ func createMempoolAndMempoolReactor(
 			mempoolv0.WithMetrics(memplMetrics),
 			mempoolv0.WithPreCheck(sm.TxPreCheck(state)),
 			mempoolv0.WithPostCheck(sm.TxPostCheck(state)),
+			mempoolv0.WithTxLifecycle(txLifecycle),
 		)
 
 		mp.SetLogger(logger)
@@ -716,6 +757,10 @@ func NewNode(config *cfg.Config,
 	logger log.Logger,
 	options ...Option,
 ) (*Node, error) {
+	// The databases are recorded to be compacted after pruning.
+	dbs := make(map[string]dbm.DB)
+	dbProvider = recordDBs(dbProvider, dbs)
+
 	blockStore, stateDB, err := initDBs(config, dbProvider)
 	if err != nil {
 		return nil, err
@@ -749,7 +794,7 @@ func NewNode(config *cfg.Config,
 	stationType := config.RPC.TrackStationType // evm / cosmwasm / svm
 
 	indexerService, txIndexer, blockIndexer, err := createAndStartIndexerService(config,
-		genDoc.ChainID, dbProvider, eventBus, logger, stationType)
+		genDoc.ChainID, dbProvider, eventBus, blockStore, stateStore, logger, stationType)
 	if err != nil {
 		return nil, err
 	}
@@ -802,7 +847,11 @@ func NewNode(config *cfg.Config,
 	csMetrics, p2pMetrics, memplMetrics, smMetrics := metricsProvider(genDoc.ChainID)
 
 	// Make MempoolReactor
-	mempool, mempoolReactor := createMempoolAndMempoolReactor(config, proxyApp, state, memplMetrics, logger)
+	var txLifecycle mempl.TxLifecycleStore = mempl.NopTxLifecycleStore{}
+	if config.Mempool.TxLifecycleSize > 0 {
+		txLifecycle = mempl.NewLRUTxLifecycleStore(config.Mempool.TxLifecycleSize)
+	}
+	mempool, mempoolReactor := createMempoolAndMempoolReactor(config, proxyApp, state, memplMetrics, txLifecycle, logger)
 
 	// Make Evidence Reactor
 	evidenceReactor, evidencePool, err := createEvidenceReactor(config, dbProvider, stateDB, blockStore, logger)
@@ -921,6 +970,7 @@ func NewNode(config *cfg.Config,
 		bcReactor:        bcReactor,
 		mempoolReactor:   mempoolReactor,
 		mempool:          mempool,
+		txLifecycle:      txLifecycle,
 		consensusState:   consensusState,
 		consensusReactor: consensusReactor,
 		stateSyncReactor: stateSyncReactor,
@@ -934,6 +984,19 @@ func NewNode(config *cfg.Config,
 		blockIndexer:     blockIndexer,
 		eventBus:         eventBus,
 	}
+
+	if config.Storage.Compact {
+		// The states are pruned along with the blocks.
+		compacted := []*compactedDB{
+			{name: "blockstore", db: dbs["blockstore"], base: blockStore.Base},
+			{name: "state", db: dbs["state"], base: blockStore.Base},
+		}
+		if db, ok := dbs["tx_index"]; ok {
+			compacted = append(compacted, &compactedDB{name: "tx_index", db: db, base: indexerService.PrunedHeight})
+		}
+		node.compactor = newDBCompactor(config.Storage.CompactionInterval, compacted...)
+		node.compactor.SetLogger(logger.With("module", "compactor"))
+	}
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
 
 	for _, option := range options {
@@ -970,6 +1033,12 @@ func (n *Node) OnStart() error {
 		n.prometheusSrv = n.startPrometheusServer(n.config.Instrumentation.PrometheusListenAddr)
 	}
 
+	if n.compactor != nil {
+		if err := n.compactor.Start(); err != nil {
+			return err
+		}
+	}
+
 	// Start the transport.
 	addr, err := p2p.NewNetAddressString(p2p.IDAddressString(n.nodeKey.ID(), n.config.P2P.ListenAddress))
 	if err != nil {
@@ -1022,6 +1091,11 @@ func (n *Node) OnStop() {
 	if err := n.indexerService.Stop(); err != nil {
 		n.Logger.Error("Error closing indexerService", "err", err)
 	}
+	if n.compactor != nil {
+		if err := n.compactor.Stop(); err != nil {
+			n.Logger.Error("Error closing compactor", "err", err)
+		}
+	}
 
 	// now stop the reactors
 	if err := n.sw.Stop(); err != nil {
@@ -1087,13 +1161,16 @@ func (n *Node) ConfigureRPC() error {
 		GenDoc:           n.genesisDoc,
 		TxIndexer:        n.txIndexer,
 		BlockIndexer:     n.blockIndexer,
+		IndexerService:   n.indexerService,
 		ConsensusReactor: n.consensusReactor,
 		EventBus:         n.eventBus,
 		Mempool:          n.mempool,
+		TxLifecycle:      n.txLifecycle,
 
 		Logger: n.Logger.With("module", "rpc"),
 
 		Config: *n.config.RPC,
+		DBDir:  n.config.DBDir(),
 	})
 	if err := rpccore.InitGenesisChunks(); err != nil {
 		return err
@@ -1114,6 +1191,51 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 		rpccore.AddUnsafeRoutes()
 	}
 
+	var auth *rpcserver.APIKeyAuth
+	if n.config.RPC.IsAuthEnabled() {
+		acl, err := n.config.RPC.AuthACL()
+		if err != nil {
+			return nil, err
+		}
+		for key, names := range acl {
+			if acl[key], err = rpccore.ExpandACL(names); err != nil {
+				return nil, fmt.Errorf("rpc.auth_keys: %w", err)
+			}
+		}
+		auth = rpcserver.NewAPIKeyAuth(acl)
+	}
+
+	callTimeouts, err := n.config.RPC.CallTimeouts()
+	if err != nil {
+		return nil, err
+	}
+	for route := range callTimeouts {
+		if _, ok := rpccore.Routes[route]; !ok {
+			return nil, fmt.Errorf("rpc.route_timeouts: unknown route %q", route)
+		}
+	}
+
+	rpcMetrics := rpcserver.NopMetrics()
+	if n.config.Instrumentation.Prometheus &&
+		(n.config.RPC.IsRateLimitEnabled() || n.config.RPC.ResponseCacheMaxBytes > 0) {
+		rpcMetrics = rpcserver.PrometheusMetrics(n.config.Instrumentation.Namespace, "chain_id", n.genesisDoc.ChainID)
+	}
+
+	var rateLimiter *rpcserver.RateLimiter
+	if n.config.RPC.IsRateLimitEnabled() {
+		rateLimiter = rpcserver.NewRateLimiter(
+			rpcserver.RateLimit{Rate: n.config.RPC.RateLimit, Burst: n.config.RPC.RateLimitBurst},
+			rpcserver.RateLimit{Rate: n.config.RPC.ExpensiveRateLimit, Burst: n.config.RPC.ExpensiveRateLimitBurst},
+			n.config.RPC.ExpensiveRoutes,
+			rpcMetrics,
+		)
+	}
+
+	var responseCache *rpcserver.ResponseCache
+	if n.config.RPC.ResponseCacheMaxBytes > 0 {
+		responseCache = rpcserver.NewResponseCache(n.config.RPC.ResponseCacheMaxBytes, rpcMetrics)
+	}
+
 	config := rpcserver.DefaultConfig()
 	config.MaxBodyBytes = n.config.RPC.MaxBodyBytes
 	config.MaxHeaderBytes = n.config.RPC.MaxHeaderBytes
@@ -1142,8 +1264,28 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 			rpcserver.WriteChanCapacity(n.config.RPC.WebSocketWriteBufferSize),
 		)
 		wm.SetLogger(wmLogger)
+		wm.SetAPIKeyAuth(auth)
+		wm.SetRateLimiter(rateLimiter)
+		wm.SetCallTimeouts(callTimeouts)
 		mux.HandleFunc("/websocket", wm.WebsocketHandler)
-		rpcserver.RegisterRPCFuncs(mux, rpccore.Routes, rpcLogger)
+		handlerOpts := []rpcserver.HandlerOption{
+			rpcserver.RequireAPIKey(auth),
+			rpcserver.RateLimitCalls(rateLimiter),
+			rpcserver.CacheResponses(responseCache),
+			rpcserver.LimitBatches(rpcserver.BatchLimits{
+				MaxSize:     n.config.RPC.MaxBatchSize,
+				Parallelism: n.config.RPC.BatchParallelism,
+				CallTimeout: n.config.RPC.BatchCallTimeout,
+			}),
+			rpcserver.TimeoutCalls(callTimeouts),
+		}
+		rpcserver.RegisterDocument(mux, "/openrpc.json", func() interface{} {
+			return rpcserver.OpenRPCDocument(rpccore.Routes, "CometBFT RPC", version.TMCoreSemVer)
+		}, rpcLogger, handlerOpts...)
+		rpcserver.RegisterDocument(mux, "/openapi.json", func() interface{} {
+			return rpcserver.OpenAPIDocument(rpccore.Routes, "CometBFT RPC", version.TMCoreSemVer)
+		}, rpcLogger, handlerOpts...)
+		rpcserver.RegisterRPCFuncs(mux, rpccore.Routes, rpcLogger, handlerOpts...)
 		listener, err := rpcserver.Listen(
 			listenAddr,
 			config,
@@ -1209,7 +1351,11 @@ func (n *Node) startRPC() ([]net.Listener, error) {
 			return nil, err
 		}
 		go func() {
-			if err := grpccore.StartGRPCServer(listener); err != nil {
+			if err := grpccore.StartGRPCServer(
+				listener,
+				grpccore.RequireAPIKey(auth),
+				grpccore.RateLimitCalls(rateLimiter),
+			); err != nil {
 				n.Logger.Error("Error starting gRPC server", "err", err)
 			}
 		}()
@@ -484,3 +484,26 @@ func state(nVals int, height int64) (sm.State, dbm.DB, []types.PrivValidator) {
 	}
 	return s, stateDB, privVals
 }
+
+func TestCompactDB(t *testing.T) {
+	db, err := dbm.NewGoLevelDB("test", t.TempDir())
+	require.NoError(t, err)
+	defer db.Close()
+	for i := 0; i < 1000; i++ {
+		require.NoError(t, db.Set([]byte(fmt.Sprintf("key%04d", i)), cmtrand.Bytes(100)))
+	}
+	for i := 0; i < 900; i++ {
+		require.NoError(t, db.Delete([]byte(fmt.Sprintf("key%04d", i))))
+	}
+
+	ok, err := compactDB(db)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	value, err := db.Get([]byte("key0950"))
+	require.NoError(t, err)
+	assert.Len(t, value, 100)
+
+	ok, err = compactDB(dbm.NewMemDB())
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
@@ -11,9 +11,12 @@ import (
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	cmtjson "github.com/tendermint/tendermint/libs/json"
 	cmtrand "github.com/tendermint/tendermint/libs/rand"
 	"github.com/tendermint/tendermint/rpc/client"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+	rpctest "github.com/tendermint/tendermint/rpc/test"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -72,7 +75,10 @@ func TestBlockEvents(t *testing.T) {
 
 			const subscriber = "TestBlockEvents"
 
-			eventCh, err := c.Subscribe(context.Background(), subscriber, types.QueryForEvent(types.EventNewBlock).String())
+			// The blocks of the test node are made in bursts, so the channel
+			// holds all the events read below.
+			eventCh, err := c.Subscribe(context.Background(), subscriber,
+				types.QueryForEvent(types.EventNewBlock).String(), 3)
 			require.NoError(t, err)
 			t.Cleanup(func() {
 				if err := c.UnsubscribeAll(context.Background(), subscriber); err != nil {
@@ -98,6 +104,66 @@ func TestBlockEvents(t *testing.T) {
 	}
 }
 
+// resume a subscription from a sequence number after reconnecting
+func TestResumeSubscription(t *testing.T) {
+	query := types.QueryForEvent(types.EventNewBlockHeader).String()
+	nextEvent := func(c *rpcclient.WSClient) ctypes.ResultEvent {
+		select {
+		case resp := <-c.ResponsesCh:
+			require.Nil(t, resp.Error)
+			var event ctypes.ResultEvent
+			require.NoError(t, cmtjson.Unmarshal(resp.Result, &event))
+			return event
+		case <-time.After(waitForEventTimeout):
+			t.Fatal("timed out waiting for event")
+			return ctypes.ResultEvent{}
+		}
+	}
+	newWSClient := func() *rpcclient.WSClient {
+		c, err := rpcclient.NewWS(rpctest.GetConfig().RPC.ListenAddress, "/websocket")
+		require.NoError(t, err)
+		require.NoError(t, c.Start())
+		return c
+	}
+
+	c := newWSClient()
+	require.NoError(t, c.Subscribe(context.Background(), query))
+	nextEvent(c) // response to subscribe
+	first := nextEvent(c)
+	require.Positive(t, first.Seq)
+	second := nextEvent(c)
+	require.Equal(t, first.Seq+1, second.Seq)
+	require.NoError(t, c.Stop())
+
+	// The events since the first one are replayed.
+	c = newWSClient()
+	t.Cleanup(func() {
+		if err := c.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	require.NoError(t, c.SubscribeFrom(context.Background(), query, first.Epoch, first.Seq))
+	nextEvent(c) // response to subscribe
+	assert.Equal(t, first, nextEvent(c))
+	assert.Equal(t, second, nextEvent(c))
+
+	// The sequence numbers of a query can't be resumed from with another.
+	blockQuery := types.QueryForEvent(types.EventNewBlock).String()
+	require.NoError(t, c.SubscribeFrom(context.Background(), blockQuery, first.Epoch, first.Seq))
+	for {
+		select {
+		case resp := <-c.ResponsesCh:
+			if resp.Error == nil { // event of the first subscription
+				continue
+			}
+			assert.Contains(t, resp.Error.Data, "no longer available")
+			return
+		case <-time.After(waitForEventTimeout):
+			t.Fatal("timed out waiting for error")
+		}
+	}
+}
+
 func TestTxEventsSentWithBroadcastTxAsync(t *testing.T) { testTxEventsSent(t, "async") }
 func TestTxEventsSentWithBroadcastTxSync(t *testing.T)  { testTxEventsSent(t, "sync") }
 
@@ -118,32 +184,42 @@ func testTxEventsSent(t *testing.T, broadcastMethod string) {
 				})
 			}
 
+			// subscribe before sending the tx, for its event not to be missed
+			const subscriber = "TestTxEventsSent"
+			ctx, cancel := context.WithTimeout(context.Background(), waitForEventTimeout)
+			defer cancel()
+			eventCh, err := c.Subscribe(ctx, subscriber, types.QueryForEvent(types.EventTx).String())
+			require.NoError(t, err)
+			t.Cleanup(func() {
+				if err := c.UnsubscribeAll(context.Background(), subscriber); err != nil {
+					t.Error(err)
+				}
+			})
+
 			// make the tx
 			_, _, tx := MakeTxKV()
 
 			// send
-			go func() {
-				var (
-					txres *ctypes.ResultBroadcastTx
-					err   error
-					ctx   = context.Background()
-				)
-				switch broadcastMethod {
-				case "async":
-					txres, err = c.BroadcastTxAsync(ctx, tx)
-				case "sync":
-					txres, err = c.BroadcastTxSync(ctx, tx)
-				default:
-					panic(fmt.Sprintf("Unknown broadcastMethod %s", broadcastMethod))
-				}
-				if assert.NoError(t, err) {
-					assert.Equal(t, txres.Code, abci.CodeTypeOK)
-				}
-			}()
+			var txres *ctypes.ResultBroadcastTx
+			switch broadcastMethod {
+			case "async":
+				txres, err = c.BroadcastTxAsync(ctx, tx)
+			case "sync":
+				txres, err = c.BroadcastTxSync(ctx, tx)
+			default:
+				panic(fmt.Sprintf("Unknown broadcastMethod %s", broadcastMethod))
+			}
+			require.NoError(t, err)
+			require.Equal(t, abci.CodeTypeOK, txres.Code)
 
 			// and wait for confirmation
-			evt, err := client.WaitForOneEvent(c, types.EventTx, waitForEventTimeout)
-			require.Nil(t, err)
+			var evt types.TMEventData
+			select {
+			case event := <-eventCh:
+				evt = event.Data
+			case <-ctx.Done():
+				t.Fatal("timed out waiting for event")
+			}
 
 			// and make sure it has the proper info
 			txe, ok := evt.(types.EventDataTx)
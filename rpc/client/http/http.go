@@ -16,6 +16,7 @@ import (
 	rpcclient "github.com/tendermint/tendermint/rpc/client"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	jsonrpcclient "github.com/tendermint/tendermint/rpc/jsonrpc/client"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -318,6 +319,36 @@ func (c *baseRPCClient) CheckTx(ctx context.Context, tx types.Tx) (*ctypes.Resul
 	return result, nil
 }
 
+func (c *baseRPCClient) TxStatus(ctx context.Context, hash []byte) (*ctypes.ResultTxStatus, error) {
+	result := new(ctypes.ResultTxStatus)
+	_, err := c.caller.Call(ctx, "tx_status", map[string]interface{}{"hash": hash}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *baseRPCClient) BroadcastTxsAsync(ctx context.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+	return c.broadcastTxs(ctx, "broadcast_txs_async", txs)
+}
+
+func (c *baseRPCClient) BroadcastTxsSync(ctx context.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+	return c.broadcastTxs(ctx, "broadcast_txs_sync", txs)
+}
+
+func (c *baseRPCClient) broadcastTxs(
+	ctx context.Context,
+	route string,
+	txs types.Txs,
+) (*ctypes.ResultBroadcastTxs, error) {
+	result := new(ctypes.ResultBroadcastTxs)
+	_, err := c.caller.Call(ctx, route, map[string]interface{}{"txs": txs}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error) {
 	result := new(ctypes.ResultNetInfo)
 	_, err := c.caller.Call(ctx, "net_info", map[string]interface{}{}, result)
@@ -327,6 +358,15 @@ func (c *baseRPCClient) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, err
 	return result, nil
 }
 
+func (c *baseRPCClient) DiskUsage(ctx context.Context) (*ctypes.ResultDiskUsage, error) {
+	result := new(ctypes.ResultDiskUsage)
+	_, err := c.caller.Call(ctx, "disk_usage", map[string]interface{}{}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) DumpConsensusState(ctx context.Context) (*ctypes.ResultDumpConsensusState, error) {
 	result := new(ctypes.ResultDumpConsensusState)
 	_, err := c.caller.Call(ctx, "dump_consensus_state", map[string]interface{}{}, result)
@@ -501,6 +541,120 @@ func (c *baseRPCClient) TxSearch(
 	return result, nil
 }
 
+// TxSearchAfter returns the transactions matching query following cursor,
+// the NextCursor of a previous result, or the first ones if cursor is empty.
+// The transactions are not counted: use TxCount to get the total count.
+func (c *baseRPCClient) TxSearchAfter(
+	ctx context.Context,
+	query string,
+	prove bool,
+	cursor string,
+	perPage *int,
+	orderBy string,
+) (*ctypes.ResultTxSearch, error) {
+
+	result := new(ctypes.ResultTxSearch)
+	params := map[string]interface{}{
+		"query":    query,
+		"prove":    prove,
+		"cursor":   cursor,
+		"order_by": orderBy,
+		"count":    false,
+	}
+
+	if perPage != nil {
+		params["per_page"] = perPage
+	}
+
+	_, err := c.caller.Call(ctx, "tx_search", params, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AccountTxs returns the transactions involving address following cursor,
+// the NextCursor of a previous result, or the first ones if cursor is empty.
+func (c *baseRPCClient) AccountTxs(
+	ctx context.Context,
+	address string,
+	prove bool,
+	cursor string,
+	perPage *int,
+	orderBy string,
+) (*ctypes.ResultAccountTxs, error) {
+	result := new(ctypes.ResultAccountTxs)
+	params := map[string]interface{}{
+		"address":  address,
+		"prove":    prove,
+		"cursor":   cursor,
+		"order_by": orderBy,
+	}
+	if perPage != nil {
+		params["per_page"] = perPage
+	}
+	_, err := c.caller.Call(ctx, "account_txs", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// TxCount returns the number of transactions matching query.
+func (c *baseRPCClient) TxCount(ctx context.Context, query string, matchEvents bool) (*ctypes.ResultTxCount, error) {
+	result := new(ctypes.ResultTxCount)
+	params := map[string]interface{}{
+		"query":        query,
+		"match_events": matchEvents,
+	}
+	_, err := c.caller.Call(ctx, "tx_count", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// BlockCount returns the number of blocks matching query.
+func (c *baseRPCClient) BlockCount(ctx context.Context, query string, matchEvents bool) (*ctypes.ResultBlockCount, error) {
+	result := new(ctypes.ResultBlockCount)
+	params := map[string]interface{}{
+		"query":        query,
+		"match_events": matchEvents,
+	}
+	_, err := c.caller.Call(ctx, "block_count", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// EventHistogram returns the number of transactions matching query by
+// buckets of bucketSize heights, and the sum, minimum and maximum of the
+// integer values of their attribute, if not empty.
+func (c *baseRPCClient) EventHistogram(
+	ctx context.Context,
+	query string,
+	attribute string,
+	bucketSize *int64,
+	matchEvents bool,
+) (*ctypes.ResultEventHistogram, error) {
+	result := new(ctypes.ResultEventHistogram)
+	params := map[string]interface{}{
+		"query":        query,
+		"attribute":    attribute,
+		"match_events": matchEvents,
+	}
+	if bucketSize != nil {
+		params["bucket_size"] = bucketSize
+	}
+	_, err := c.caller.Call(ctx, "event_histogram", params, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func (c *baseRPCClient) BlockSearch(
 	ctx context.Context,
 	query string,
@@ -529,6 +683,35 @@ func (c *baseRPCClient) BlockSearch(
 	return result, nil
 }
 
+// BlockSearchAfter returns the blocks matching query following cursor, the
+// NextCursor of a previous result, or the first ones if cursor is empty.
+func (c *baseRPCClient) BlockSearchAfter(
+	ctx context.Context,
+	query string,
+	cursor string,
+	perPage *int,
+	orderBy string,
+) (*ctypes.ResultBlockSearch, error) {
+
+	result := new(ctypes.ResultBlockSearch)
+	params := map[string]interface{}{
+		"query":    query,
+		"cursor":   cursor,
+		"order_by": orderBy,
+	}
+
+	if perPage != nil {
+		params["per_page"] = perPage
+	}
+
+	_, err := c.caller.Call(ctx, "block_search", params, result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (c *baseRPCClient) Validators(
 	ctx context.Context,
 	height *int64,
@@ -578,7 +761,9 @@ type WSEvents struct {
 	ws       *jsonrpcclient.WSClient
 
 	mtx           cmtsync.RWMutex
-	subscriptions map[string]chan ctypes.ResultEvent // query -> chan
+	subscriptions map[string]chan ctypes.ResultEvent   // query -> chan
+	pending       map[rpctypes.JSONRPCIntID]chan error // subscribe request ID -> result
+	lastSubID     rpctypes.JSONRPCIntID
 }
 
 func newWSEvents(remote, endpoint string) (*WSEvents, error) {
@@ -586,6 +771,8 @@ func newWSEvents(remote, endpoint string) (*WSEvents, error) {
 		endpoint:      endpoint,
 		remote:        remote,
 		subscriptions: make(map[string]chan ctypes.ResultEvent),
+		pending:       make(map[rpctypes.JSONRPCIntID]chan error),
+		lastSubID:     -1,
 	}
 	w.BaseService = *service.NewBaseService(nil, "WSEvents", w)
 
@@ -621,8 +808,9 @@ func (w *WSEvents) OnStop() {
 }
 
 // Subscribe implements EventsClient by using WSClient to subscribe given
-// subscriber to query. By default, returns a channel with cap=1. Error is
-// returned if it fails to subscribe.
+// subscriber to query. By default, returns a channel with cap=1. It waits for
+// the server to confirm the subscription, and returns an error if it fails to
+// subscribe.
 //
 // Channel is never closed to prevent clients from seeing an erroneous event.
 //
@@ -634,25 +822,68 @@ func (w *WSEvents) Subscribe(ctx context.Context, subscriber, query string,
 		return nil, errNotRunning
 	}
 
-	if err := w.ws.Subscribe(ctx, query); err != nil {
-		return nil, err
-	}
-
 	outCap := 1
 	if len(outCapacity) > 0 {
 		outCap = outCapacity[0]
 	}
 
+	// The channel is registered before subscribing, so that the events
+	// published right after the server has subscribed are not dropped.
 	outc := make(chan ctypes.ResultEvent, outCap)
+	done := make(chan error, 1)
 	w.mtx.Lock()
 	// subscriber param is ignored because CometBFT will override it with
 	// remote IP anyway.
+	prev, hadPrev := w.subscriptions[query]
 	w.subscriptions[query] = outc
+	// The subscribe requests count down from -2, so as not to collide with
+	// the IDs of the WSClient requests, nor with the -1 of the server errors.
+	w.lastSubID--
+	id := w.lastSubID
+	w.pending[id] = done
 	w.mtx.Unlock()
 
+	err = w.subscribe(ctx, id, query, done)
+	if err != nil {
+		w.mtx.Lock()
+		delete(w.pending, id)
+		if w.subscriptions[query] == outc {
+			if hadPrev {
+				w.subscriptions[query] = prev
+			} else {
+				delete(w.subscriptions, query)
+			}
+		}
+		w.mtx.Unlock()
+		return nil, err
+	}
+
 	return outc, nil
 }
 
+// subscribe sends the subscribe request with the given ID and waits for the
+// server to respond, so that no events are missed once Subscribe returns.
+func (w *WSEvents) subscribe(ctx context.Context, id rpctypes.JSONRPCIntID, query string,
+	done <-chan error) error {
+	request, err := rpctypes.MapToRequest(id, "subscribe",
+		map[string]interface{}{"query": query})
+	if err != nil {
+		return err
+	}
+	if err := w.ws.Send(ctx, request); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-w.Quit():
+		return errNotRunning
+	}
+}
+
 // Unsubscribe implements EventsClient by using WSClient to unsubscribe given
 // subscriber from query.
 //
@@ -723,6 +954,22 @@ func (w *WSEvents) eventListener() {
 				return
 			}
 
+			// The response to a pending subscribe request (or the first event
+			// published to it) confirms or refuses the subscription.
+			if id, ok := resp.ID.(rpctypes.JSONRPCIntID); ok && id < -1 {
+				w.mtx.Lock()
+				done, ok := w.pending[id]
+				delete(w.pending, id)
+				w.mtx.Unlock()
+				if ok {
+					if resp.Error != nil {
+						done <- resp.Error
+						continue
+					}
+					done <- nil
+				}
+			}
+
 			if resp.Error != nil {
 				w.Logger.Error("WS error", "err", resp.Error.Error())
 				// Error can be ErrAlreadySubscribed or max client (subscriptions per
@@ -743,6 +990,10 @@ func (w *WSEvents) eventListener() {
 				w.Logger.Error("failed to unmarshal response", "err", err)
 				continue
 			}
+			if result.Query == "" {
+				// the empty result of a subscribe request
+				continue
+			}
 
 			w.mtx.RLock()
 			if out, ok := w.subscriptions[result.Query]; ok {
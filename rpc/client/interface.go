@@ -135,6 +135,9 @@ type MempoolClient interface {
 	UnconfirmedTxs(ctx context.Context, limit *int) (*ctypes.ResultUnconfirmedTxs, error)
 	NumUnconfirmedTxs(context.Context) (*ctypes.ResultUnconfirmedTxs, error)
 	CheckTx(context.Context, types.Tx) (*ctypes.ResultCheckTx, error)
+	TxStatus(ctx context.Context, hash []byte) (*ctypes.ResultTxStatus, error)
+	BroadcastTxsAsync(context.Context, types.Txs) (*ctypes.ResultBroadcastTxs, error)
+	BroadcastTxsSync(context.Context, types.Txs) (*ctypes.ResultBroadcastTxs, error)
 }
 
 // EvidenceClient is used for submitting an evidence of the malicious
@@ -111,10 +111,26 @@ func (c *Local) CheckTx(ctx context.Context, tx types.Tx) (*ctypes.ResultCheckTx
 	return core.CheckTx(c.ctx, tx)
 }
 
+func (c *Local) TxStatus(ctx context.Context, hash []byte) (*ctypes.ResultTxStatus, error) {
+	return core.TxStatus(c.ctx, hash)
+}
+
+func (c *Local) BroadcastTxsAsync(ctx context.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+	return core.BroadcastTxsAsync(c.ctx, txs)
+}
+
+func (c *Local) BroadcastTxsSync(ctx context.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+	return core.BroadcastTxsSync(c.ctx, txs)
+}
+
 func (c *Local) NetInfo(ctx context.Context) (*ctypes.ResultNetInfo, error) {
 	return core.NetInfo(c.ctx)
 }
 
+func (c *Local) DiskUsage(ctx context.Context) (*ctypes.ResultDiskUsage, error) {
+	return core.DiskUsage(c.ctx)
+}
+
 func (c *Local) DumpConsensusState(ctx context.Context) (*ctypes.ResultDumpConsensusState, error) {
 	return core.DumpConsensusState(c.ctx)
 }
@@ -192,6 +208,56 @@ func (c *Local) TxSearch(
 	return core.TxSearch(c.ctx, query, prove, page, perPage, orderBy)
 }
 
+// TxSearchAfter returns the transactions matching query following cursor,
+// the NextCursor of a previous result, or the first ones if cursor is empty.
+// The transactions are not counted: use TxCount to get the total count.
+func (c *Local) TxSearchAfter(
+	_ context.Context,
+	query string,
+	prove bool,
+	cursor string,
+	perPage *int,
+	orderBy string,
+) (*ctypes.ResultTxSearch, error) {
+	return core.TxSearchAfter(c.ctx, query, prove, cursor, perPage, orderBy)
+}
+
+// AccountTxs returns the transactions involving address following cursor,
+// the NextCursor of a previous result, or the first ones if cursor is empty.
+func (c *Local) AccountTxs(
+	_ context.Context,
+	address string,
+	prove bool,
+	cursor string,
+	perPage *int,
+	orderBy string,
+) (*ctypes.ResultAccountTxs, error) {
+	return core.AccountTxs(c.ctx, address, prove, cursor, perPage, orderBy)
+}
+
+// TxCount returns the number of transactions matching query.
+func (c *Local) TxCount(_ context.Context, query string, matchEvents bool) (*ctypes.ResultTxCount, error) {
+	return core.TxCount(c.ctx, query, matchEvents)
+}
+
+// BlockCount returns the number of blocks matching query.
+func (c *Local) BlockCount(_ context.Context, query string, matchEvents bool) (*ctypes.ResultBlockCount, error) {
+	return core.BlockCount(c.ctx, query, matchEvents)
+}
+
+// EventHistogram returns the number of transactions matching query by
+// buckets of bucketSize heights, and the sum, minimum and maximum of the
+// integer values of their attribute, if not empty.
+func (c *Local) EventHistogram(
+	_ context.Context,
+	query string,
+	attribute string,
+	bucketSize *int64,
+	matchEvents bool,
+) (*ctypes.ResultEventHistogram, error) {
+	return core.EventHistogram(c.ctx, query, attribute, bucketSize, matchEvents)
+}
+
 func (c *Local) BlockSearch(
 	_ context.Context,
 	query string,
@@ -201,6 +267,18 @@ func (c *Local) BlockSearch(
 	return core.BlockSearch(c.ctx, query, page, perPage, orderBy)
 }
 
+// BlockSearchAfter returns the blocks matching query following cursor, the
+// NextCursor of a previous result, or the first ones if cursor is empty.
+func (c *Local) BlockSearchAfter(
+	_ context.Context,
+	query string,
+	cursor string,
+	perPage *int,
+	orderBy string,
+) (*ctypes.ResultBlockSearch, error) {
+	return core.BlockSearchAfter(c.ctx, query, cursor, perPage, orderBy)
+}
+
 func (c *Local) BroadcastEvidence(ctx context.Context, ev types.Evidence) (*ctypes.ResultBroadcastEvidence, error) {
 	return core.BroadcastEvidence(c.ctx, ev)
 }
@@ -365,6 +365,43 @@ func TestBroadcastTxCommit(t *testing.T) {
 	}
 }
 
+func TestBroadcastTxsSync(t *testing.T) {
+	for i, c := range GetClients() {
+		_, _, tx1 := MakeTxKV()
+		_, _, tx2 := MakeTxKV()
+		res, err := c.BroadcastTxsSync(context.Background(), types.Txs{tx1, tx2, tx1})
+		require.NoError(t, err, "%d: %+v", i, err)
+		require.Len(t, res.Txs, 3)
+		for j, r := range res.Txs[:2] {
+			assert.Equal(t, abci.CodeTypeOK, r.Code, "%d/%d", i, j)
+			assert.Empty(t, r.Error, "%d/%d", i, j)
+		}
+		assert.EqualValues(t, types.Tx(tx1).Hash(), res.Txs[0].Hash)
+		// The duplicate is rejected by the mempool cache.
+		assert.NotEmpty(t, res.Txs[2].Error)
+	}
+}
+
+func TestTxStatus(t *testing.T) {
+	for i, c := range GetClients() {
+		_, _, tx := MakeTxKV()
+		bres, err := c.BroadcastTxCommit(context.Background(), tx)
+		require.NoError(t, err, "%d: %+v", i, err)
+
+		res, err := c.TxStatus(context.Background(), bres.Hash)
+		require.NoError(t, err, "%d: %+v", i, err)
+		assert.Equal(t, ctypes.TxStatusCommitted, res.Status)
+		assert.Equal(t, bres.Height, res.Height)
+		require.NotEmpty(t, res.Events)
+		assert.Equal(t, mempl.TxEventReceived, res.Events[0].Type)
+		assert.Equal(t, mempl.TxSourceRPC, res.Events[0].Source)
+
+		res, err = c.TxStatus(context.Background(), types.Tx("unknown").Hash())
+		require.NoError(t, err, "%d: %+v", i, err)
+		assert.Equal(t, ctypes.TxStatusUnknown, res.Status)
+	}
+}
+
 func TestUnconfirmedTxs(t *testing.T) {
 	_, _, tx := MakeTxKV()
 
@@ -532,6 +569,20 @@ func TestBlockSearch(t *testing.T) {
 	require.Equal(t, blockCount, 0)
 
 }
+
+func TestBlockCount(t *testing.T) {
+	c := getHTTPClient()
+	require.NoError(t, client.WaitForHeight(c, 3, nil))
+
+	result, err := c.BlockCount(context.Background(), "block.height <= 3", false)
+	require.NoError(t, err)
+	require.Equal(t, 3, result.Count)
+
+	result, err = c.BlockCount(context.Background(), "begin_event.foo = 100", false)
+	require.NoError(t, err)
+	require.Zero(t, result.Count)
+}
+
 func TestTxSearch(t *testing.T) {
 	c := getHTTPClient()
 
@@ -614,19 +665,23 @@ func TestTxSearch(t *testing.T) {
 		require.Nil(t, err)
 		for k := 0; k < len(result.Txs)-1; k++ {
 			require.LessOrEqual(t, result.Txs[k].Height, result.Txs[k+1].Height)
-			require.LessOrEqual(t, result.Txs[k].Index, result.Txs[k+1].Index)
+			if result.Txs[k].Height == result.Txs[k+1].Height {
+				require.LessOrEqual(t, result.Txs[k].Index, result.Txs[k+1].Index)
+			}
 		}
 
 		result, err = c.TxSearch(context.Background(), "tx.height >= 1", false, nil, nil, "desc")
 		require.Nil(t, err)
 		for k := 0; k < len(result.Txs)-1; k++ {
 			require.GreaterOrEqual(t, result.Txs[k].Height, result.Txs[k+1].Height)
-			require.GreaterOrEqual(t, result.Txs[k].Index, result.Txs[k+1].Index)
+			if result.Txs[k].Height == result.Txs[k+1].Height {
+				require.GreaterOrEqual(t, result.Txs[k].Index, result.Txs[k+1].Index)
+			}
 		}
 		// check pagination
 		perPage = 3
 		var (
-			seen      = map[int64]bool{}
+			seen      = map[string]bool{}
 			maxHeight int64
 			pages     = int(math.Ceil(float64(txCount) / float64(perPage)))
 		)
@@ -643,11 +698,11 @@ func TestTxSearch(t *testing.T) {
 			}
 			totalTx = totalTx + len(result.Txs)
 			for _, tx := range result.Txs {
-				require.False(t, seen[tx.Height],
-					"Found duplicate height %v in page %v", tx.Height, page)
-				require.Greater(t, tx.Height, maxHeight,
+				require.False(t, seen[tx.Hash.String()],
+					"Found duplicate tx %v in page %v", tx.Hash, page)
+				require.GreaterOrEqual(t, tx.Height, maxHeight,
 					"Found decreasing height %v (max seen %v) in page %v", tx.Height, maxHeight, page)
-				seen[tx.Height] = true
+				seen[tx.Hash.String()] = true
 				maxHeight = tx.Height
 			}
 		}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"fmt"
+
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/bytes"
 	"github.com/tendermint/tendermint/proxy"
@@ -17,17 +19,32 @@ func ABCIQuery(
 	height int64,
 	prove bool,
 ) (*ctypes.ResultABCIQuery, error) {
-	resQuery, err := env.ProxyAppQuery.QuerySync(abci.RequestQuery{
-		Path:   path,
-		Data:   data,
-		Height: height,
-		Prove:  prove,
-	})
-	if err != nil {
-		return nil, err
+	type queryResult struct {
+		res *abci.ResponseQuery
+		err error
 	}
+	done := make(chan queryResult, 1)
+	go func() {
+		res, err := env.ProxyAppQuery.QuerySync(abci.RequestQuery{
+			Path:   path,
+			Data:   data,
+			Height: height,
+			Prove:  prove,
+		})
+		done <- queryResult{res, err}
+	}()
 
-	return &ctypes.ResultABCIQuery{Response: *resQuery}, nil
+	// ABCI queries can't be cancelled, but the caller doesn't wait for the
+	// application once the context is done.
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return &ctypes.ResultABCIQuery{Response: *res.res}, nil
+	case <-ctx.Context().Done():
+		return nil, fmt.Errorf("query aborted: %w", ctx.Context().Err())
+	}
 }
 
 // ABCIInfo gets some info about the application.
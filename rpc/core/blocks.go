@@ -1,14 +1,17 @@
 package core
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"sort"
 
 	cmtmath "github.com/tendermint/tendermint/libs/math"
+	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	cmtquery "github.com/tendermint/tendermint/libs/pubsub/query"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/state/indexer"
 	blockidxnull "github.com/tendermint/tendermint/state/indexer/block/null"
 	"github.com/tendermint/tendermint/types"
 )
@@ -167,19 +170,100 @@ func BlockResults(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlockR
 	}, nil
 }
 
+// StreamBlocks calls send with every block and its results, in order, starting
+// from fromHeight: first for the blocks already in the block store, then for
+// new blocks as they are committed. It returns when ctx is done, send fails or
+// the subscription to new blocks is cancelled, e.g. because the subscriber is
+// too slow; the caller can then resume from the height following the last
+// block sent. A fromHeight of 0 starts from the earliest available block.
+func StreamBlocks(
+	ctx context.Context,
+	client string,
+	fromHeight int64,
+	send func(*ctypes.ResultBlock, *ctypes.ResultBlockResults) error,
+) error {
+	base := env.BlockStore.Base()
+	if fromHeight == 0 {
+		fromHeight = cmtmath.MaxInt64(base, 1)
+	} else if fromHeight < base {
+		return fmt.Errorf("height %d is not available, lowest height is %d", fromHeight, base)
+	}
+
+	next := fromHeight
+	sendUpTo := func(height int64) error {
+		for ; next <= height; next++ {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			h := next
+			block, err := Block(&rpctypes.Context{}, &h)
+			if err != nil {
+				return err
+			}
+			results, err := BlockResults(&rpctypes.Context{}, &h)
+			if err != nil {
+				return err
+			}
+			if err := send(block, results); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Catch up with the block store before subscribing, as the buffer of the
+	// subscription could overflow meanwhile, until the blocks committed while
+	// catching up are sent too.
+	for next <= env.BlockStore.Height() {
+		if err := sendUpTo(env.BlockStore.Height()); err != nil {
+			return err
+		}
+	}
+	sub, unsubscribe, err := SubscribeStream(ctx, client, types.EventQueryNewBlock.String())
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	// The blocks committed before subscribing are sent from the block store.
+	if err := sendUpTo(env.BlockStore.Height()); err != nil {
+		return err
+	}
+	for {
+		select {
+		case msg := <-sub.Out():
+			data, ok := msg.Data().(types.EventDataNewBlock)
+			if !ok {
+				return fmt.Errorf("unexpected event data %T", msg.Data())
+			}
+			if err := sendUpTo(data.Block.Height); err != nil {
+				return err
+			}
+		case <-sub.Cancelled():
+			if err := sub.Err(); err != nil && err != cmtpubsub.ErrUnsubscribed {
+				return fmt.Errorf("subscription was cancelled (reason: %w)", err)
+			}
+			return errors.New("subscription was cancelled (reason: CometBFT exited)")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
 func BlockSearchMatchEvents(
 	ctx *rpctypes.Context,
 	query string,
 	pagePtr, perPagePtr *int,
 	orderBy string,
 	matchEvents bool,
+	cursor string,
 ) (*ctypes.ResultBlockSearch, error) {
 	if matchEvents {
-		query = "match.events = 1 AND " + query
+		query = "match.events = 1 AND (" + query + ")"
 	} else {
-		query = "match.events = 0 AND " + query
+		query = "match.events = 0 AND (" + query + ")"
 	}
-	return BlockSearch(ctx, query, pagePtr, perPagePtr, orderBy)
+	return blockSearch(ctx, query, pagePtr, perPagePtr, orderBy, cursor)
 }
 
 // BlockSearch searches for a paginated set of blocks matching BeginBlock and
@@ -190,11 +274,34 @@ func BlockSearch(
 	pagePtr, perPagePtr *int,
 	orderBy string,
 ) (*ctypes.ResultBlockSearch, error) {
+	return blockSearch(ctx, query, pagePtr, perPagePtr, orderBy, "")
+}
 
+// BlockSearchAfter is like BlockSearch, but returns the blocks following
+// cursor, the next_cursor of a previous result, or the first ones if cursor
+// is empty. Unlike pages, cursors are not shifted by new blocks.
+func BlockSearchAfter(
+	ctx *rpctypes.Context,
+	query string,
+	cursor string,
+	perPagePtr *int,
+	orderBy string,
+) (*ctypes.ResultBlockSearch, error) {
+	return blockSearch(ctx, query, nil, perPagePtr, orderBy, cursor)
+}
+
+// BlockCount returns the number of blocks matching BeginBlock and EndBlock
+// event search criteria, without loading them.
+func BlockCount(ctx *rpctypes.Context, query string, matchEvents bool) (*ctypes.ResultBlockCount, error) {
 	// skip if block indexing is disabled
 	if _, ok := env.BlockIndexer.(*blockidxnull.BlockerIndexer); ok {
 		return nil, errors.New("block indexing is disabled")
 	}
+	if matchEvents {
+		query = "match.events = 1 AND (" + query + ")"
+	} else {
+		query = "match.events = 0 AND (" + query + ")"
+	}
 	q, err := cmtquery.New(query)
 	if err != nil {
 		return nil, err
@@ -204,34 +311,57 @@ func BlockSearch(
 	if err != nil {
 		return nil, err
 	}
+	if err := ctx.Context().Err(); err != nil {
+		return nil, fmt.Errorf("search aborted: %w", err)
+	}
+	return &ctypes.ResultBlockCount{Count: len(results)}, nil
+}
 
-	// sort results (must be done before pagination)
-	switch orderBy {
-	case "desc", "":
-		sort.Slice(results, func(i, j int) bool { return results[i] > results[j] })
-
-	case "asc":
-		sort.Slice(results, func(i, j int) bool { return results[i] < results[j] })
+func blockSearch(
+	ctx *rpctypes.Context,
+	query string,
+	pagePtr, perPagePtr *int,
+	orderBy string,
+	cursor string,
+) (*ctypes.ResultBlockSearch, error) {
 
-	default:
-		return nil, errors.New("expected order_by to be either `asc` or `desc` or empty")
+	// skip if block indexing is disabled
+	if _, ok := env.BlockIndexer.(*blockidxnull.BlockerIndexer); ok {
+		return nil, errors.New("block indexing is disabled")
+	}
+	q, err := cmtquery.New(query)
+	if err != nil {
+		return nil, err
 	}
 
-	// paginate results
-	totalCount := len(results)
-	perPage := validatePerPage(perPagePtr)
+	page, err := searchPage(pagePtr, perPagePtr, orderBy, "desc", cursor)
+	if err != nil {
+		return nil, err
+	}
 
-	page, err := validatePage(pagePtr, perPage, totalCount)
+	results, err := env.BlockIndexer.Search(ctx.Context(), q)
 	if err != nil {
 		return nil, err
 	}
+	// The search stops early once the context is done, with partial results.
+	if err := ctx.Context().Err(); err != nil {
+		return nil, fmt.Errorf("search aborted: %w", err)
+	}
 
-	skipCount := validateSkipCount(page, perPage)
-	pageSize := cmtmath.MinInt(perPage, totalCount-skipCount)
+	// sort results (must be done before pagination)
+	cursorOf := func(i int) indexer.Cursor { return indexer.Cursor{Height: results[i]} }
+	sort.Slice(results, func(i, j int) bool { return page.Less(cursorOf(i), cursorOf(j)) })
 
-	apiResults := make([]*ctypes.ResultBlock, 0, pageSize)
-	for i := skipCount; i < skipCount+pageSize; i++ {
-		block := env.BlockStore.LoadBlock(results[i])
+	// paginate results
+	totalCount := len(results)
+	if _, err := validatePage(pagePtr, page.Limit, totalCount); err != nil {
+		return nil, err
+	}
+	start, end, next := page.Slice(totalCount, cursorOf)
+
+	apiResults := make([]*ctypes.ResultBlock, 0, end-start)
+	for _, height := range results[start:end] {
+		block := env.BlockStore.LoadBlock(height)
 		if block != nil {
 			blockMeta := env.BlockStore.LoadBlockMeta(block.Height)
 			if blockMeta != nil {
@@ -243,5 +373,9 @@ func BlockSearch(
 		}
 	}
 
-	return &ctypes.ResultBlockSearch{Blocks: apiResults, TotalCount: totalCount}, nil
+	result := &ctypes.ResultBlockSearch{Blocks: apiResults, TotalCount: totalCount}
+	if next != nil {
+		result.NextCursor = next.String()
+	}
+	return result, nil
 }
@@ -1,7 +1,10 @@
 package core
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -10,6 +13,8 @@ import (
 	dbm "github.com/cometbft/cometbft-db"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
 	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
@@ -134,3 +139,72 @@ func (mockBlockStore) LoadSeenCommit(height int64) *types.Commit         { retur
 func (mockBlockStore) PruneBlocks(height int64) (uint64, error)          { return 0, nil }
 func (mockBlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, seenCommit *types.Commit) {
 }
+
+func TestStreamBlocksCatchesUpBeforeSubscribing(t *testing.T) {
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	store := &streamBlockStore{}
+	store.height.Store(5)
+	config := cfg.DefaultRPCConfig()
+	config.SubscriptionBufferSize = 2
+	SetEnvironment(&Environment{
+		BlockStore: store,
+		StateStore: sm.NewStore(dbm.NewMemDB(), sm.StoreOptions{}),
+		EventBus:   eventBus,
+		Config:     *config,
+		Logger:     log.NewNopLogger(),
+	})
+	for height := int64(1); height <= 11; height++ {
+		require.NoError(t, env.StateStore.SaveABCIResponses(height, &cmtstate.ABCIResponses{
+			BeginBlock: &abci.ResponseBeginBlock{},
+			EndBlock:   &abci.ResponseEndBlock{},
+		}))
+	}
+	commit := func(height int64) {
+		store.height.Store(height)
+		require.NoError(t, eventBus.PublishEventNewBlock(types.EventDataNewBlock{Block: store.LoadBlock(height)}))
+	}
+
+	// More blocks than the subscription buffer holds are committed while
+	// catching up, then one more while following the new blocks.
+	errDone := errors.New("done")
+	var heights []int64
+	err := StreamBlocks(context.Background(), "client", 1,
+		func(block *ctypes.ResultBlock, results *ctypes.ResultBlockResults) error {
+			height := block.Block.Height
+			heights = append(heights, height)
+			switch height {
+			case 1:
+				for h := int64(6); h <= 10; h++ {
+					commit(h)
+				}
+			case 10:
+				go commit(11)
+			case 11:
+				return errDone
+			}
+			return nil
+		})
+	require.ErrorIs(t, err, errDone)
+	require.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11}, heights)
+}
+
+// streamBlockStore stores the blocks up to a height growing concurrently.
+type streamBlockStore struct {
+	mockBlockStore
+	height atomic.Int64
+}
+
+func (store *streamBlockStore) Height() int64 { return store.height.Load() }
+
+func (store *streamBlockStore) LoadBlock(height int64) *types.Block {
+	if height > store.height.Load() {
+		return nil
+	}
+	return &types.Block{Header: types.Header{Height: height}}
+}
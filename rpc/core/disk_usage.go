@@ -0,0 +1,69 @@
+package core
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+// DiskUsage returns the size on disk of each database of the node, and of
+// the other entries of its data directory, such as the consensus WAL.
+// More: https://docs.cometbft.com/v0.34/rpc/#/Info/disk_usage
+func DiskUsage(ctx *rpctypes.Context) (*ctypes.ResultDiskUsage, error) {
+	if env.DBDir == "" {
+		return nil, errors.New("the data directory is unknown")
+	}
+	entries, err := os.ReadDir(env.DBDir)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ctypes.ResultDiskUsage{Databases: make([]ctypes.DatabaseSize, 0, len(entries))}
+	for _, entry := range entries {
+		size, err := diskSize(filepath.Join(env.DBDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		result.Databases = append(result.Databases, ctypes.DatabaseSize{
+			Name: strings.TrimSuffix(entry.Name(), ".db"),
+			Size: size,
+		})
+		result.Total += size
+	}
+	sort.Slice(result.Databases, func(i, j int) bool {
+		return result.Databases[i].Name < result.Databases[j].Name
+	})
+	return result, nil
+}
+
+// diskSize returns the size of the file at path, or of the files under it if
+// it is a directory. The files removed meanwhile, as by compactions, are
+// skipped.
+func diskSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
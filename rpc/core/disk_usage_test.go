@@ -0,0 +1,39 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+func TestDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "state.db"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "state.db", "000001.log"), make([]byte, 100), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "state.db", "CURRENT"), make([]byte, 16), 0o600))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "blockstore.db"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "blockstore.db", "000002.ldb"), make([]byte, 1000), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "priv_validator_state.json"), make([]byte, 10), 0o600))
+
+	env = &Environment{DBDir: dir}
+	result, err := DiskUsage(&rpctypes.Context{})
+	require.NoError(t, err)
+	assert.Equal(t, &ctypes.ResultDiskUsage{
+		Total: 1126,
+		Databases: []ctypes.DatabaseSize{
+			{Name: "blockstore", Size: 1000},
+			{Name: "priv_validator_state.json", Size: 10},
+			{Name: "state", Size: 116},
+		},
+	}, result)
+
+	env = &Environment{}
+	_, err = DiskUsage(&rpctypes.Context{})
+	assert.Error(t, err)
+}
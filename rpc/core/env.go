@@ -2,6 +2,7 @@ package core
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 
@@ -24,6 +25,12 @@ const (
 	defaultPerPage = 30
 	maxPerPage     = 100
 
+	// default number of heights of the buckets of event_histogram
+	defaultBucketSize = 100
+	// maximum number of buckets of event_histogram over the heights of the
+	// chain
+	maxHistogramBuckets = 1000
+
 	// SubscribeTimeout is the maximum time we wait to subscribe for an event.
 	// must be less than the server's write timeout (see rpcserver.DefaultConfig)
 	SubscribeTimeout = 5 * time.Second
@@ -41,6 +48,16 @@ var (
 // SetEnvironment sets up the given Environment.
 // It will race if multiple Node call SetEnvironment.
 func SetEnvironment(e *Environment) {
+	// The environment of a node can be set more than once, e.g. by the local
+	// client, while its event bus still holds the subscriptions of the event
+	// logs and of the streams.
+	if env != nil && env.EventBus == e.EventBus {
+		e.eventLogs = env.eventLogs
+		e.streams = env.streams
+	} else {
+		e.eventLogs = newEventLogs()
+		e.streams = newStreamClients()
+	}
 	env = e
 }
 
@@ -90,16 +107,27 @@ type Environment struct {
 	GenDoc           *types.GenesisDoc // cache the genesis structure
 	TxIndexer        txindex.TxIndexer
 	BlockIndexer     indexer.BlockIndexer
+	IndexerService   *txindex.IndexerService
 	ConsensusReactor *consensus.Reactor
 	EventBus         *types.EventBus // thread safe
 	Mempool          mempl.Mempool
+	TxLifecycle      mempl.TxLifecycleStore
 
 	Logger log.Logger
 
 	Config cfg.RPCConfig
 
+	// The data directory of the node, reported by disk_usage.
+	DBDir string
+
 	// cache of chunked genesis data.
 	genChunks []string
+
+	// events replayed to WebSocket subscriptions.
+	eventLogs *eventLogs
+
+	// clients of the streams subscribed by other transports.
+	streams *streamClients
 }
 
 //----------------------------------------------
@@ -177,6 +205,39 @@ func validateSkipCount(page, perPage int) int {
 	return skipCount
 }
 
+// searchPage returns the page of search results selected by the page,
+// per_page, order_by and cursor parameters, in defaultOrder if order_by is
+// empty. The page number is validated by the caller, once the total count of
+// results is known.
+func searchPage(pagePtr, perPagePtr *int, orderBy, defaultOrder, cursor string) (indexer.Page, error) {
+	page := indexer.Page{Limit: validatePerPage(perPagePtr)}
+
+	if orderBy == "" {
+		orderBy = defaultOrder
+	}
+	switch orderBy {
+	case "asc":
+	case "desc":
+		page.Desc = true
+	default:
+		return page, errors.New("expected order_by to be either `asc` or `desc` or empty")
+	}
+
+	switch {
+	case cursor != "" && pagePtr != nil:
+		return page, errors.New("page and cursor can't be both set")
+	case cursor != "":
+		c, err := indexer.ParseCursor(cursor)
+		if err != nil {
+			return page, err
+		}
+		page.After = &c
+	case pagePtr != nil:
+		page.Skip = validateSkipCount(*pagePtr, page.Limit)
+	}
+	return page, nil
+}
+
 // latestHeight can be either latest committed or uncommitted (+1) height.
 func getHeight(latestHeight int64, heightPtr *int64) (int64, error) {
 	if heightPtr != nil {
@@ -0,0 +1,208 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tendermint/tendermint/crypto"
+	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+	"github.com/tendermint/tendermint/types"
+)
+
+const (
+	// eventLogSubscriber is the subscriber of the event bus recording the
+	// events of the event logs.
+	eventLogSubscriber = "rpc-event-log"
+)
+
+type eventLogEntry struct {
+	seq    int64
+	data   types.TMEventData
+	events map[string][]string
+}
+
+// eventLog records the last events matching a query, numbered by a sequence
+// starting at 1, for WebSocket subscriptions to replay them. The sequence of
+// a log starts over with a new log, e.g. once the node restarts, which has
+// an epoch of its own, for the events of one log not to be replayed from the
+// sequence numbers of another.
+type eventLog struct {
+	query cmtpubsub.Query
+	size  int
+	epoch string
+
+	mtx     cmtsync.Mutex
+	entries []eventLogEntry
+	next    int64         // sequence number of the next event
+	updated chan struct{} // closed when an event is added
+	err     error         // reason why the log was closed
+	closed  chan struct{}
+
+	subscribers int
+	idleSince   time.Time
+}
+
+func newEventLog(query cmtpubsub.Query, size int) *eventLog {
+	return &eventLog{
+		query:   query,
+		size:    size,
+		epoch:   crypto.CRandHex(16),
+		next:    1,
+		updated: make(chan struct{}),
+		closed:  make(chan struct{}),
+	}
+}
+
+func (l *eventLog) add(msg cmtpubsub.Message) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if len(l.entries) == l.size {
+		l.entries[0] = eventLogEntry{}
+		l.entries = l.entries[1:]
+	}
+	l.entries = append(l.entries, eventLogEntry{seq: l.next, data: msg.Data(), events: msg.Events()})
+	l.next++
+
+	close(l.updated)
+	l.updated = make(chan struct{})
+}
+
+func (l *eventLog) close(err error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	l.err = err
+	close(l.closed)
+}
+
+// Err returns the reason why the log was closed.
+func (l *eventLog) Err() error {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.err
+}
+
+// cursor returns the sequence number of the first event to send to a
+// subscriber resuming from fromSeq in epoch, or of the next event if fromSeq
+// is 0.
+func (l *eventLog) cursor(epoch string, fromSeq int64) (int64, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	if fromSeq == 0 {
+		return l.next, nil
+	}
+	if epoch != l.epoch {
+		return 0, fmt.Errorf("events of epoch %q are no longer available, the current epoch is %q", epoch, l.epoch)
+	}
+	if oldest := l.next - int64(len(l.entries)); fromSeq < oldest {
+		return 0, fmt.Errorf("events from seq %d are no longer available, the oldest is %d", fromSeq, oldest)
+	}
+	if fromSeq > l.next {
+		return 0, fmt.Errorf("seq %d is ahead of the next event (%d)", fromSeq, l.next)
+	}
+	return fromSeq, nil
+}
+
+// since returns the events from seq, and the sequence number of the next
+// event, along with a channel closed once it is added. If the events from seq
+// are no longer available, it returns the events from the oldest one, and
+// skipped is true.
+func (l *eventLog) since(seq int64) (entries []eventLogEntry, next int64, skipped bool, updated <-chan struct{}) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+
+	oldest := l.next - int64(len(l.entries))
+	if seq < oldest {
+		seq, skipped = oldest, true
+	}
+	entries = append(entries, l.entries[seq-oldest:]...)
+	return entries, l.next, skipped, l.updated
+}
+
+// eventLogs holds the event logs of the queries subscribed to over the
+// WebSocket.
+type eventLogs struct {
+	mtx  cmtsync.Mutex
+	logs map[string]*eventLog // query -> log
+}
+
+func newEventLogs() *eventLogs {
+	return &eventLogs{logs: make(map[string]*eventLog)}
+}
+
+// acquire returns the event log of query, creating it if needed. The caller
+// must call release once done with it.
+func (ls *eventLogs) acquire(ctx context.Context, query cmtpubsub.Query) (*eventLog, error) {
+	ls.mtx.Lock()
+	defer ls.mtx.Unlock()
+
+	if l, ok := ls.logs[query.String()]; ok {
+		l.subscribers++
+		return l, nil
+	}
+
+	sub, err := env.EventBus.Subscribe(ctx, eventLogSubscriber, query, env.Config.SubscriptionBufferSize)
+	if err != nil {
+		return nil, err
+	}
+	l := newEventLog(query, env.Config.EventReplayBufferSize)
+	l.subscribers++
+	ls.logs[query.String()] = l
+
+	go func() {
+		for {
+			select {
+			case msg := <-sub.Out():
+				l.add(msg)
+			case <-sub.Cancelled():
+				ls.remove(l)
+				l.close(sub.Err())
+				return
+			}
+		}
+	}()
+
+	return l, nil
+}
+
+// release releases an event log returned by acquire. The log is removed once
+// its query has had no subscribers for event_replay_idle_timeout.
+func (ls *eventLogs) release(l *eventLog) {
+	ls.mtx.Lock()
+	defer ls.mtx.Unlock()
+
+	l.subscribers--
+	if l.subscribers > 0 {
+		return
+	}
+	l.idleSince = time.Now()
+	time.AfterFunc(env.Config.EventReplayIdleTimeout, func() { ls.expire(l) })
+}
+
+func (ls *eventLogs) expire(l *eventLog) {
+	ls.mtx.Lock()
+	defer ls.mtx.Unlock()
+
+	if ls.logs[l.query.String()] != l || l.subscribers > 0 || time.Since(l.idleSince) < env.Config.EventReplayIdleTimeout {
+		return
+	}
+	delete(ls.logs, l.query.String())
+	// The log is closed once the subscription is cancelled.
+	err := env.EventBus.Unsubscribe(context.Background(), eventLogSubscriber, l.query)
+	if err != nil && err != cmtpubsub.ErrSubscriptionNotFound {
+		env.Logger.Error("Failed to unsubscribe event log", "query", l.query, "err", err)
+	}
+}
+
+func (ls *eventLogs) remove(l *eventLog) {
+	ls.mtx.Lock()
+	defer ls.mtx.Unlock()
+
+	if ls.logs[l.query.String()] == l {
+		delete(ls.logs, l.query.String())
+	}
+}
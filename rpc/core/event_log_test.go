@@ -0,0 +1,66 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	cmtquery "github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestEventLog(t *testing.T) {
+	l := newEventLog(cmtquery.MustParse("tm.event = 'Tx'"), 3)
+
+	seq, err := l.cursor("", 0)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, seq)
+	_, err = l.cursor(l.epoch, 2)
+	assert.Error(t, err)
+
+	entries, next, skipped, updated := l.since(seq)
+	assert.Empty(t, entries)
+	assert.EqualValues(t, 1, next)
+	assert.False(t, skipped)
+
+	for h := int64(1); h <= 5; h++ {
+		l.add(cmtpubsub.NewMessage(types.EventDataNewBlockHeader{NumTxs: h}, nil))
+	}
+	select {
+	case <-updated:
+	default:
+		t.Fatal("expected the log to be updated")
+	}
+
+	// Only the last 3 events are kept.
+	_, err = l.cursor(l.epoch, 2)
+	assert.Error(t, err)
+	seq, err = l.cursor(l.epoch, 3)
+	require.NoError(t, err)
+	// Nor from the sequence numbers of another log.
+	other := newEventLog(l.query, 3)
+	assert.NotEqual(t, l.epoch, other.epoch)
+	_, err = l.cursor(other.epoch, 3)
+	assert.Error(t, err)
+
+	entries, next, skipped, _ = l.since(seq)
+	assert.False(t, skipped)
+	assert.EqualValues(t, 6, next)
+	require.Len(t, entries, 3)
+	for i, entry := range entries {
+		assert.EqualValues(t, 3+i, entry.seq)
+		assert.EqualValues(t, 3+i, entry.data.(types.EventDataNewBlockHeader).NumTxs)
+	}
+
+	// A slow subscriber skips the events it missed.
+	entries, _, skipped, _ = l.since(1)
+	assert.True(t, skipped)
+	require.Len(t, entries, 3)
+	assert.EqualValues(t, 3, entries[0].seq)
+
+	entries, _, skipped, _ = l.since(6)
+	assert.False(t, skipped)
+	assert.Empty(t, entries)
+}
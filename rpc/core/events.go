@@ -8,8 +8,10 @@ import (
 
 	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
 	cmtquery "github.com/tendermint/tendermint/libs/pubsub/query"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
 )
 
 const (
@@ -18,20 +20,27 @@ const (
 	maxQueryLength = 512
 )
 
-// Subscribe for events via WebSocket.
+// Subscribe for events via WebSocket. Events are numbered by a sequence per
+// query, within an epoch; if fromSeq is not 0, the subscription resumes from
+// the event with this sequence number in epoch, provided the node still has
+// it.
 // More: https://docs.cometbft.com/v0.34/rpc/#/Websocket/subscribe
-func Subscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultSubscribe, error) {
+func Subscribe(ctx *rpctypes.Context, query string, fromSeq int64, epoch string) (*ctypes.ResultSubscribe, error) {
 	addr := ctx.RemoteAddr()
 
-	if env.EventBus.NumClients() >= env.Config.MaxSubscriptionClients {
+	if env.streams.numClients() >= env.Config.MaxSubscriptionClients {
 		return nil, fmt.Errorf("max_subscription_clients %d reached", env.Config.MaxSubscriptionClients)
 	} else if env.EventBus.NumClientSubscriptions(addr) >= env.Config.MaxSubscriptionsPerClient {
 		return nil, fmt.Errorf("max_subscriptions_per_client %d reached", env.Config.MaxSubscriptionsPerClient)
 	} else if len(query) > maxQueryLength {
 		return nil, errors.New("maximum query length exceeded")
+	} else if fromSeq < 0 {
+		return nil, errors.New("from_seq can't be negative")
+	} else if fromSeq > 0 && epoch == "" {
+		return nil, errors.New("from_seq requires the epoch of the events")
 	}
 
-	env.Logger.Info("Subscribe to query", "remote", addr, "query", query)
+	env.Logger.Info("Subscribe to query", "remote", addr, "query", query, "fromSeq", fromSeq)
 
 	q, err := cmtquery.New(query)
 	if err != nil {
@@ -41,41 +50,103 @@ func Subscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultSubscribe, er
 	subCtx, cancel := context.WithTimeout(ctx.Context(), SubscribeTimeout)
 	defer cancel()
 
+	// The subscription of the client accounts for the limits above, and is
+	// cancelled by Unsubscribe or when the client disconnects, while its events
+	// are read from the event log of the query.
 	sub, err := env.EventBus.Subscribe(subCtx, addr, q, env.Config.SubscriptionBufferSize)
 	if err != nil {
 		return nil, err
 	}
+	eventLog, err := env.eventLogs.acquire(subCtx, q)
+	if err != nil {
+		unsubscribe(addr, q)
+		return nil, err
+	}
+	seq, err := eventLog.cursor(epoch, fromSeq)
+	if err != nil {
+		env.eventLogs.release(eventLog)
+		unsubscribe(addr, q)
+		return nil, err
+	}
 
-	closeIfSlow := env.Config.CloseOnSlowClient
-
-	// Capture the current ID, since it can change in the future.
-	subscriptionID := ctx.JSONReq.ID
 	go func() {
 		for {
 			select {
-			case msg := <-sub.Out():
+			case <-sub.Out(): // events are read from the event log
+			case <-sub.Cancelled():
+				return
+			}
+		}
+	}()
+
+	var (
+		// Capture the current ID, since it can change in the future.
+		subscriptionID = ctx.JSONReq.ID
+		cancelSlow     = env.Config.CancelSlowClients()
+	)
+	go func() {
+		defer env.eventLogs.release(eventLog)
+
+		writeCancel := func(reason string) {
+			var (
+				err  = fmt.Errorf("subscription was cancelled (reason: %s)", reason)
+				resp = rpctypes.RPCServerError(subscriptionID, err)
+			)
+			if !ctx.WSConn.TryWriteRPCResponse(resp) {
+				env.Logger.Info("Can't write response (slow client)",
+					"to", addr, "subscriptionID", subscriptionID, "err", err)
+			}
+		}
+
+		for {
+			entries, next, skipped, updated := eventLog.since(seq)
+			if skipped {
+				env.Logger.Info("Client missed events (slow client)",
+					"to", addr, "subscriptionID", subscriptionID, "fromSeq", seq)
+				if cancelSlow {
+					writeCancel("slow client")
+					unsubscribe(addr, eventLog.query)
+					return
+				}
+			}
+
+			for _, entry := range entries {
 				var (
-					resultEvent = &ctypes.ResultEvent{Query: query, Data: msg.Data(), Events: msg.Events()}
-					resp        = rpctypes.NewRPCSuccessResponse(subscriptionID, resultEvent)
+					resultEvent = &ctypes.ResultEvent{
+						Query:  query,
+						Epoch:  eventLog.epoch,
+						Seq:    entry.seq,
+						Data:   entry.data,
+						Events: entry.events,
+					}
+					resp = rpctypes.NewRPCSuccessResponse(subscriptionID, resultEvent)
 				)
 				writeCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-				defer cancel()
-				if err := ctx.WSConn.WriteRPCResponse(writeCtx, resp); err != nil {
+				err := ctx.WSConn.WriteRPCResponse(writeCtx, resp)
+				cancel()
+				if err != nil {
 					env.Logger.Info("Can't write response (slow client)",
 						"to", addr, "subscriptionID", subscriptionID, "err", err)
 
-					if closeIfSlow {
-						var (
-							err  = errors.New("subscription was cancelled (reason: slow client)")
-							resp = rpctypes.RPCServerError(subscriptionID, err)
-						)
-						if !ctx.WSConn.TryWriteRPCResponse(resp) {
-							env.Logger.Info("Can't write response (slow client)",
-								"to", addr, "subscriptionID", subscriptionID, "err", err)
-						}
+					if cancelSlow {
+						writeCancel("slow client")
+						unsubscribe(addr, eventLog.query)
 						return
 					}
 				}
+			}
+			seq = next
+
+			select {
+			case <-updated:
+			case <-eventLog.closed:
+				reason := "CometBFT exited"
+				if err := eventLog.Err(); err != nil && err != cmtpubsub.ErrUnsubscribed {
+					reason = err.Error()
+				}
+				writeCancel(reason)
+				unsubscribe(addr, eventLog.query)
+				return
 			case <-sub.Cancelled():
 				if sub.Err() != cmtpubsub.ErrUnsubscribed {
 					var reason string
@@ -84,14 +155,7 @@ func Subscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultSubscribe, er
 					} else {
 						reason = sub.Err().Error()
 					}
-					var (
-						err  = fmt.Errorf("subscription was cancelled (reason: %s)", reason)
-						resp = rpctypes.RPCServerError(subscriptionID, err)
-					)
-					if !ctx.WSConn.TryWriteRPCResponse(resp) {
-						env.Logger.Info("Can't write response (slow client)",
-							"to", addr, "subscriptionID", subscriptionID, "err", err)
-					}
+					writeCancel(reason)
 				}
 				return
 			}
@@ -101,6 +165,98 @@ func Subscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultSubscribe, er
 	return &ctypes.ResultSubscribe{}, nil
 }
 
+// unsubscribe unsubscribes subscriber from query, if still subscribed.
+func unsubscribe(subscriber string, query cmtpubsub.Query) {
+	if err := env.EventBus.Unsubscribe(context.Background(), subscriber, query); err != nil &&
+		err != cmtpubsub.ErrSubscriptionNotFound {
+		env.Logger.Error("Failed to unsubscribe", "remote", subscriber, "query", query, "err", err)
+	}
+}
+
+// SubscribeStream subscribes to events matching query on behalf of transports
+// other than the WebSocket, e.g. gRPC streams, applying the same limits as
+// Subscribe to client. Each stream subscribes separately, for the streams of a
+// client not to collide. The caller must call the returned function once done
+// with the subscription.
+func SubscribeStream(ctx context.Context, client, query string) (types.Subscription, func(), error) {
+	if len(query) > maxQueryLength {
+		return nil, nil, errors.New("maximum query length exceeded")
+	}
+	q, err := cmtquery.New(query)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse query: %w", err)
+	}
+
+	subscriber, err := env.streams.add(client)
+	if err != nil {
+		return nil, nil, err
+	}
+	env.Logger.Info("Subscribe to query", "remote", subscriber, "query", query)
+
+	subCtx, cancel := context.WithTimeout(ctx, SubscribeTimeout)
+	defer cancel()
+
+	sub, err := env.EventBus.Subscribe(subCtx, subscriber, q, env.Config.SubscriptionBufferSize)
+	if err != nil {
+		env.streams.remove(client)
+		return nil, nil, err
+	}
+
+	return sub, func() {
+		unsubscribe(subscriber, q)
+		env.streams.remove(client)
+	}, nil
+}
+
+// streamClients counts the streams subscribed by each client with
+// SubscribeStream, for the limits to apply to the clients rather than to the
+// subscribers of their streams.
+type streamClients struct {
+	mtx     cmtsync.Mutex
+	seq     uint64
+	streams map[string]int // client -> number of streams
+	total   int
+}
+
+func newStreamClients() *streamClients {
+	return &streamClients{streams: make(map[string]int)}
+}
+
+// numClients returns the number of clients subscribed to the event bus, the
+// streams of a client counting as one.
+func (sc *streamClients) numClients() int {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	return env.EventBus.NumClients() - sc.total + len(sc.streams)
+}
+
+// add adds a stream of client, and returns its subscriber, unless the limits
+// of the subscriptions are reached. The caller must call remove once the
+// stream is done.
+func (sc *streamClients) add(client string) (string, error) {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	streams := sc.streams[client]
+	if streams == 0 && env.EventBus.NumClients()-sc.total+len(sc.streams) >= env.Config.MaxSubscriptionClients {
+		return "", fmt.Errorf("max_subscription_clients %d reached", env.Config.MaxSubscriptionClients)
+	} else if streams >= env.Config.MaxSubscriptionsPerClient {
+		return "", fmt.Errorf("max_subscriptions_per_client %d reached", env.Config.MaxSubscriptionsPerClient)
+	}
+	sc.seq++
+	sc.streams[client]++
+	sc.total++
+	return fmt.Sprintf("%s#%d", client, sc.seq), nil
+}
+
+func (sc *streamClients) remove(client string) {
+	sc.mtx.Lock()
+	defer sc.mtx.Unlock()
+	if sc.streams[client]--; sc.streams[client] <= 0 {
+		delete(sc.streams, client)
+	}
+	sc.total--
+}
+
 // Unsubscribe from events via WebSocket.
 // More: https://docs.cometbft.com/v0.34/rpc/#/Websocket/unsubscribe
 func Unsubscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultUnsubscribe, error) {
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	cfg "github.com/tendermint/tendermint/config"
+	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestSubscribeStream(t *testing.T) {
+	eventBus := types.NewEventBus()
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+	config := cfg.DefaultRPCConfig()
+	config.MaxSubscriptionClients = 1
+	config.MaxSubscriptionsPerClient = 2
+	SetEnvironment(&Environment{EventBus: eventBus, Config: *config, Logger: log.NewNopLogger()})
+
+	ctx := context.Background()
+	query := types.EventQueryNewBlock.String()
+
+	// The streams of a client subscribe to the same query separately.
+	_, unsubscribe1, err := SubscribeStream(ctx, "peer1", query)
+	require.NoError(t, err)
+	_, unsubscribe2, err := SubscribeStream(ctx, "peer1", query)
+	require.NoError(t, err)
+	require.Equal(t, 2, eventBus.NumClients())
+
+	// The limits apply to the clients.
+	_, _, err = SubscribeStream(ctx, "peer1", query)
+	require.ErrorContains(t, err, "max_subscriptions_per_client")
+	_, _, err = SubscribeStream(ctx, "peer2", query)
+	require.ErrorContains(t, err, "max_subscription_clients")
+
+	unsubscribe1()
+	unsubscribe2()
+	_, unsubscribe, err := SubscribeStream(ctx, "peer2", query)
+	require.NoError(t, err)
+	unsubscribe()
+	require.Zero(t, eventBus.NumClients())
+}
@@ -10,6 +10,7 @@ import (
 	mempl "github.com/tendermint/tendermint/mempool"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/state/txindex/null"
 	"github.com/tendermint/tendermint/types"
 )
 
@@ -59,6 +60,67 @@ func BroadcastTxSync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcas
 	}
 }
 
+// BroadcastTxsAsync submits each of the given transactions to CheckTx and
+// returns right away with their hashes. Does not wait for CheckTx nor
+// DeliverTx results; only the transactions that could not be submitted have
+// an error set.
+func BroadcastTxsAsync(ctx *rpctypes.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("no transactions given")
+	}
+
+	result := &ctypes.ResultBroadcastTxs{Txs: make([]*ctypes.ResultBroadcastTxsItem, len(txs))}
+	for i, tx := range txs {
+		result.Txs[i] = &ctypes.ResultBroadcastTxsItem{Hash: tx.Hash()}
+		if err := env.Mempool.CheckTx(tx, nil, mempl.TxInfo{}); err != nil {
+			result.Txs[i].Error = err.Error()
+		}
+	}
+	return result, nil
+}
+
+// BroadcastTxsSync submits each of the given transactions to CheckTx and
+// returns with their responses, in the same order. Does not wait for
+// DeliverTx results.
+func BroadcastTxsSync(ctx *rpctypes.Context, txs types.Txs) (*ctypes.ResultBroadcastTxs, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("no transactions given")
+	}
+
+	result := &ctypes.ResultBroadcastTxs{Txs: make([]*ctypes.ResultBroadcastTxsItem, len(txs))}
+	resChs := make([]chan *abci.Response, len(txs))
+	for i, tx := range txs {
+		result.Txs[i] = &ctypes.ResultBroadcastTxsItem{Hash: tx.Hash()}
+		// Buffered so the callback never blocks, even if we stop waiting.
+		resCh := make(chan *abci.Response, 1)
+		err := env.Mempool.CheckTx(tx, func(res *abci.Response) {
+			resCh <- res
+		}, mempl.TxInfo{})
+		if err != nil {
+			result.Txs[i].Error = err.Error()
+			continue
+		}
+		resChs[i] = resCh
+	}
+
+	for i, resCh := range resChs {
+		if resCh == nil {
+			continue
+		}
+		select {
+		case <-ctx.Context().Done():
+			return nil, fmt.Errorf("broadcast confirmation not received: %w", ctx.Context().Err())
+		case res := <-resCh:
+			r := res.GetCheckTx()
+			result.Txs[i].Code = r.Code
+			result.Txs[i].Data = r.Data
+			result.Txs[i].Log = r.Log
+			result.Txs[i].Codespace = r.Codespace
+		}
+	}
+	return result, nil
+}
+
 // BroadcastTxCommit returns with the responses from CheckTx and DeliverTx.
 // More: https://docs.cometbft.com/v0.34/rpc/#/Tx/broadcast_tx_commit
 func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
@@ -171,6 +233,61 @@ func NumUnconfirmedTxs(ctx *rpctypes.Context) (*ctypes.ResultUnconfirmedTxs, err
 		TotalBytes: env.Mempool.SizeBytes()}, nil
 }
 
+// TxStatus returns what happened to the transaction with the given hash:
+// whether it is pending in the mempool, was rejected, evicted or committed,
+// along with the lifecycle events recorded by the mempool. Only a bounded
+// number of recent transactions are tracked (see
+// mempool.tx-lifecycle-size); older committed transactions are looked up in
+// the tx index.
+func TxStatus(ctx *rpctypes.Context, hash []byte) (*ctypes.ResultTxStatus, error) {
+	var key types.TxKey
+	if len(hash) != len(key) {
+		return nil, fmt.Errorf("expected a %d bytes tx hash, got %d bytes", len(key), len(hash))
+	}
+	copy(key[:], hash)
+
+	res := &ctypes.ResultTxStatus{Hash: hash, Status: ctypes.TxStatusUnknown}
+	var (
+		lifecycle mempl.TxLifecycle
+		ok        bool
+	)
+	if env.TxLifecycle != nil {
+		lifecycle, ok = env.TxLifecycle.Get(key)
+	}
+	if !ok {
+		if _, isNull := env.TxIndexer.(*null.TxIndex); isNull {
+			return res, nil
+		}
+		r, err := env.TxIndexer.Get(hash)
+		if err != nil {
+			return nil, err
+		}
+		if r != nil {
+			res.Status = ctypes.TxStatusCommitted
+			res.Height = r.Height
+			res.Code = r.Result.Code
+		}
+		return res, nil
+	}
+
+	last := lifecycle.Last()
+	res.Events = lifecycle.Events
+	res.Code = last.Code
+	res.Reason = last.Reason
+	switch last.Type {
+	case mempl.TxEventReceived, mempl.TxEventAdded:
+		res.Status = ctypes.TxStatusPending
+	case mempl.TxEventRejected:
+		res.Status = ctypes.TxStatusRejected
+	case mempl.TxEventEvicted, mempl.TxEventExpired, mempl.TxEventRemoved:
+		res.Status = ctypes.TxStatusEvicted
+	case mempl.TxEventCommitted:
+		res.Status = ctypes.TxStatusCommitted
+		res.Height = last.Height
+	}
+	return res, nil
+}
+
 // CheckTx checks the transaction without executing it. The transaction won't
 // be added to the mempool either.
 // More: https://docs.cometbft.com/v0.34/rpc/#/Tx/check_tx
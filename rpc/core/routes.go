@@ -1,6 +1,9 @@
 package core
 
 import (
+	"fmt"
+	"sort"
+
 	rpc "github.com/tendermint/tendermint/rpc/jsonrpc/server"
 )
 
@@ -14,7 +17,7 @@ var Routes = map[string]*rpc.RPCFunc{
 	"tracks_pod_count": rpc.NewRPCFunc(TracksGetPodCount, ""),
 
 	// subscribe/unsubscribe are reserved for websocket events.
-	"subscribe":       rpc.NewWSRPCFunc(Subscribe, "query"),
+	"subscribe":       rpc.NewWSRPCFunc(Subscribe, "query,from_seq,epoch"),
 	"unsubscribe":     rpc.NewWSRPCFunc(Unsubscribe, "query"),
 	"unsubscribe_all": rpc.NewWSRPCFunc(UnsubscribeAll, ""),
 
@@ -22,41 +25,116 @@ var Routes = map[string]*rpc.RPCFunc{
 	"health":               rpc.NewRPCFunc(Health, ""),
 	"status":               rpc.NewRPCFunc(Status, ""),
 	"net_info":             rpc.NewRPCFunc(NetInfo, ""),
+	"disk_usage":           rpc.NewRPCFunc(DiskUsage, ""),
 	"blockchain":           rpc.NewRPCFunc(BlockchainInfo, "minHeight,maxHeight", rpc.Cacheable()),
-	"genesis":              rpc.NewRPCFunc(Genesis, "", rpc.Cacheable()),
-	"genesis_chunked":      rpc.NewRPCFunc(GenesisChunked, "chunk", rpc.Cacheable()),
+	"genesis":              rpc.NewRPCFunc(Genesis, "", rpc.Cacheable(), rpc.Immutable()),
+	"genesis_chunked":      rpc.NewRPCFunc(GenesisChunked, "chunk", rpc.Cacheable(), rpc.Immutable()),
 	"block":                rpc.NewRPCFunc(Block, "height", rpc.Cacheable("height")),
 	"block_by_hash":        rpc.NewRPCFunc(BlockByHash, "hash", rpc.Cacheable()),
 	"block_results":        rpc.NewRPCFunc(BlockResults, "height", rpc.Cacheable("height")),
 	"commit":               rpc.NewRPCFunc(Commit, "height", rpc.Cacheable("height")),
-	"check_tx":             rpc.NewRPCFunc(CheckTx, "tx"),
+	"check_tx":             rpc.NewRPCFunc(CheckTx, "tx", rpc.Ordered()),
 	"tx":                   rpc.NewRPCFunc(Tx, "hash,prove", rpc.Cacheable()),
-	"tx_search":            rpc.NewRPCFunc(TxSearchMatchEvents, "query,prove,page,per_page,order_by,match_events"),
-	"block_search":         rpc.NewRPCFunc(BlockSearchMatchEvents, "query,page,per_page,order_by,match_events"),
+	"tx_search":            rpc.NewRPCFunc(TxSearchMatchEvents, "query,prove,page,per_page,order_by,match_events,cursor,count"),
+	"block_search":         rpc.NewRPCFunc(BlockSearchMatchEvents, "query,page,per_page,order_by,match_events,cursor"),
+	"tx_count":             rpc.NewRPCFunc(TxCount, "query,match_events"),
+	"event_histogram":      rpc.NewRPCFunc(EventHistogram, "query,attribute,bucket_size,match_events"),
+	"block_count":          rpc.NewRPCFunc(BlockCount, "query,match_events"),
+	"account_txs":          rpc.NewRPCFunc(AccountTxs, "address,prove,cursor,per_page,order_by"),
 	"validators":           rpc.NewRPCFunc(Validators, "height,page,per_page", rpc.Cacheable("height")),
 	"dump_consensus_state": rpc.NewRPCFunc(DumpConsensusState, ""),
 	"consensus_state":      rpc.NewRPCFunc(ConsensusState, ""),
 	"consensus_params":     rpc.NewRPCFunc(ConsensusParams, "height", rpc.Cacheable("height")),
 	"unconfirmed_txs":      rpc.NewRPCFunc(UnconfirmedTxs, "limit"),
 	"num_unconfirmed_txs":  rpc.NewRPCFunc(NumUnconfirmedTxs, ""),
+	"tx_status":            rpc.NewRPCFunc(TxStatus, "hash"),
 
 	// tx broadcast API
-	"broadcast_tx_commit": rpc.NewRPCFunc(BroadcastTxCommit, "tx"),
-	"broadcast_tx_sync":   rpc.NewRPCFunc(BroadcastTxSync, "tx"),
-	"broadcast_tx_async":  rpc.NewRPCFunc(BroadcastTxAsync, "tx"),
+	"broadcast_tx_commit": rpc.NewRPCFunc(BroadcastTxCommit, "tx", rpc.Ordered()),
+	"broadcast_tx_sync":   rpc.NewRPCFunc(BroadcastTxSync, "tx", rpc.Ordered()),
+	"broadcast_tx_async":  rpc.NewRPCFunc(BroadcastTxAsync, "tx", rpc.Ordered()),
+	"broadcast_txs_sync":  rpc.NewRPCFunc(BroadcastTxsSync, "txs", rpc.Ordered()),
+	"broadcast_txs_async": rpc.NewRPCFunc(BroadcastTxsAsync, "txs", rpc.Ordered()),
 
 	// abci API
 	"abci_query": rpc.NewRPCFunc(ABCIQuery, "path,data,height,prove"),
 	"abci_info":  rpc.NewRPCFunc(ABCIInfo, "", rpc.Cacheable()),
 
 	// evidence API
-	"broadcast_evidence": rpc.NewRPCFunc(BroadcastEvidence, "evidence"),
+	"broadcast_evidence": rpc.NewRPCFunc(BroadcastEvidence, "evidence", rpc.Ordered()),
 }
 
 // AddUnsafeRoutes adds unsafe routes.
 func AddUnsafeRoutes() {
 	// control API
-	Routes["dial_seeds"] = rpc.NewRPCFunc(UnsafeDialSeeds, "seeds")
-	Routes["dial_peers"] = rpc.NewRPCFunc(UnsafeDialPeers, "peers,persistent,unconditional,private")
-	Routes["unsafe_flush_mempool"] = rpc.NewRPCFunc(UnsafeFlushMempool, "")
+	Routes["dial_seeds"] = rpc.NewRPCFunc(UnsafeDialSeeds, "seeds", rpc.Ordered())
+	Routes["dial_peers"] = rpc.NewRPCFunc(UnsafeDialPeers, "peers,persistent,unconditional,private", rpc.Ordered())
+	Routes["unsafe_flush_mempool"] = rpc.NewRPCFunc(UnsafeFlushMempool, "", rpc.Ordered())
+}
+
+// Route groups that can be granted to API keys, besides single routes. See
+// the auth_keys option of the [rpc] config.
+const (
+	RouteGroupAll       = "*"
+	RouteGroupRead      = "read"
+	RouteGroupBroadcast = "broadcast"
+	RouteGroupUnsafe    = "unsafe"
+	RouteGroupTracks    = "tracks"
+)
+
+// routeGroups maps the routes which are not in RouteGroupRead to their group.
+var routeGroups = map[string]string{
+	"tracks_get_pod":   RouteGroupTracks,
+	"tracks_pod_count": RouteGroupTracks,
+
+	"check_tx":            RouteGroupBroadcast,
+	"broadcast_tx_commit": RouteGroupBroadcast,
+	"broadcast_tx_sync":   RouteGroupBroadcast,
+	"broadcast_tx_async":  RouteGroupBroadcast,
+	"broadcast_txs_sync":  RouteGroupBroadcast,
+	"broadcast_txs_async": RouteGroupBroadcast,
+	"broadcast_evidence":  RouteGroupBroadcast,
+
+	"dial_seeds":           RouteGroupUnsafe,
+	"dial_peers":           RouteGroupUnsafe,
+	"unsafe_flush_mempool": RouteGroupUnsafe,
+}
+
+// RouteGroup returns the group of the given route.
+func RouteGroup(route string) string {
+	if group, ok := routeGroups[route]; ok {
+		return group
+	}
+	return RouteGroupRead
+}
+
+// ExpandACL returns the sorted names of the routes granted by acl, a list of
+// route names and route groups.
+func ExpandACL(acl []string) ([]string, error) {
+	granted := make(map[string]struct{})
+	for _, name := range acl {
+		switch name {
+		case RouteGroupAll, RouteGroupRead, RouteGroupBroadcast, RouteGroupUnsafe, RouteGroupTracks:
+			for route := range Routes {
+				if name == RouteGroupAll || RouteGroup(route) == name {
+					granted[route] = struct{}{}
+				}
+			}
+		default:
+			// Unsafe routes are only in Routes if enabled.
+			_, isRoute := Routes[name]
+			_, isKnownRoute := routeGroups[name]
+			if !isRoute && !isKnownRoute {
+				return nil, fmt.Errorf("unknown route or route group %q", name)
+			}
+			granted[name] = struct{}{}
+		}
+	}
+
+	routes := make([]string, 0, len(granted))
+	for route := range granted {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	return routes, nil
 }
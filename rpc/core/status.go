@@ -51,6 +51,21 @@ func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 		votingPower = val.VotingPower
 	}
 
+	var (
+		indexedHeight     int64
+		indexerLag        int64
+		indexerCatchingUp bool
+	)
+	if env.IndexerService != nil {
+		if height, ok := env.IndexerService.IndexedHeight(); ok {
+			indexedHeight = height
+			if latestHeight > height {
+				indexerLag = latestHeight - height
+			}
+			indexerCatchingUp = env.IndexerService.CatchingUp()
+		}
+	}
+
 	result := &ctypes.ResultStatus{
 		NodeInfo: env.P2PTransport.NodeInfo().(p2p.DefaultNodeInfo),
 		SyncInfo: ctypes.SyncInfo{
@@ -63,6 +78,9 @@ func Status(ctx *rpctypes.Context) (*ctypes.ResultStatus, error) {
 			EarliestBlockHeight: earliestBlockHeight,
 			EarliestBlockTime:   time.Unix(0, earliestBlockTimeNano),
 			CatchingUp:          env.ConsensusReactor.WaitSync(),
+			IndexedHeight:       indexedHeight,
+			IndexerLag:          indexerLag,
+			IndexerCatchingUp:   indexerCatchingUp,
 		},
 		ValidatorInfo: ctypes.ValidatorInfo{
 			Address:     env.PubKey.Address(),
@@ -3,12 +3,12 @@ package core
 import (
 	"errors"
 	"fmt"
-	"sort"
 
-	cmtmath "github.com/tendermint/tendermint/libs/math"
+	abci "github.com/tendermint/tendermint/abci/types"
 	cmtquery "github.com/tendermint/tendermint/libs/pubsub/query"
 	ctypes "github.com/tendermint/tendermint/rpc/core/types"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/state/txindex"
 	"github.com/tendermint/tendermint/state/txindex/null"
 	"github.com/tendermint/tendermint/types"
 )
@@ -37,8 +37,9 @@ func Tx(ctx *rpctypes.Context, hash []byte, prove bool) (*ctypes.ResultTx, error
 
 	var proof types.TxProof
 	if prove {
-		block := env.BlockStore.LoadBlock(height)
-		proof = block.Data.Txs.Proof(int(index)) // XXX: overflow on 32-bit machines
+		if proof, err = txProof(height, index); err != nil {
+			return nil, err
+		}
 	}
 
 	return &ctypes.ResultTx{
@@ -61,6 +62,36 @@ func TxSearch(
 	pagePtr, perPagePtr *int,
 	orderBy string,
 ) (*ctypes.ResultTxSearch, error) {
+	return txSearch(ctx, query, prove, pagePtr, perPagePtr, orderBy, "", true)
+}
+
+// TxSearchAfter is like TxSearch, but returns the transactions following
+// cursor, the next_cursor of a previous result, or the first ones if cursor
+// is empty. Unlike pages, cursors are not shifted by new blocks. The total
+// count is not returned, for the cost of a call to only depend on perPage:
+// use TxCount to get it.
+func TxSearchAfter(
+	ctx *rpctypes.Context,
+	query string,
+	prove bool,
+	cursor string,
+	perPagePtr *int,
+	orderBy string,
+) (*ctypes.ResultTxSearch, error) {
+	return txSearch(ctx, query, prove, nil, perPagePtr, orderBy, cursor, false)
+}
+
+// txSearch searches for the transactions matching query, and counts them all
+// for the total count if count is true.
+func txSearch(
+	ctx *rpctypes.Context,
+	query string,
+	prove bool,
+	pagePtr, perPagePtr *int,
+	orderBy string,
+	cursor string,
+	count bool,
+) (*ctypes.ResultTxSearch, error) {
 
 	// if index is disabled, return error
 	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
@@ -74,51 +105,99 @@ func TxSearch(
 		return nil, err
 	}
 
-	results, err := env.TxIndexer.Search(ctx.Context(), q)
+	page, err := searchPage(pagePtr, perPagePtr, orderBy, "asc", cursor)
+	if err != nil {
+		return nil, err
+	}
+	results, next, err := txindex.SearchPage(ctx.Context(), env.TxIndexer, q, page)
 	if err != nil {
 		return nil, err
 	}
+	// Counting loads all the matching txs, so the cost of a call only depends
+	// on per_page if they are not counted.
+	var totalCount int
+	if count {
+		if totalCount, err = txindex.Count(ctx.Context(), env.TxIndexer, q); err != nil {
+			return nil, err
+		}
+	}
+	// The search stops early once the context is done, with partial results.
+	if err := ctx.Context().Err(); err != nil {
+		return nil, fmt.Errorf("search aborted: %w", err)
+	}
+	if pagePtr != nil && count {
+		// Validated once the total count is known.
+		if _, err := validatePage(pagePtr, page.Limit, totalCount); err != nil {
+			return nil, err
+		}
+	}
 
-	// sort results (must be done before pagination)
-	switch orderBy {
-	case "desc":
-		sort.Slice(results, func(i, j int) bool {
-			if results[i].Height == results[j].Height {
-				return results[i].Index > results[j].Index
-			}
-			return results[i].Height > results[j].Height
-		})
-	case "asc", "":
-		sort.Slice(results, func(i, j int) bool {
-			if results[i].Height == results[j].Height {
-				return results[i].Index < results[j].Index
-			}
-			return results[i].Height < results[j].Height
-		})
-	default:
-		return nil, errors.New("expected order_by to be either `asc` or `desc` or empty")
+	txs, err := resultTxs(results, prove)
+	if err != nil {
+		return nil, err
 	}
+	result := &ctypes.ResultTxSearch{Txs: txs, TotalCount: totalCount}
+	if next != nil {
+		result.NextCursor = next.String()
+	}
+	return result, nil
+}
 
-	// paginate results
-	totalCount := len(results)
-	perPage := validatePerPage(perPagePtr)
+// AccountTxs returns the transactions involving address, per the address
+// index of the tx indexer (see address_events in the [tx_index] config), the
+// latest first unless order_by is "asc". Transactions follow cursor, the
+// next_cursor of a previous result, or start from the first ones if cursor is
+// empty. Unlike tx_search, the cost of a call only depends on per_page.
+func AccountTxs(
+	ctx *rpctypes.Context,
+	address string,
+	prove bool,
+	cursor string,
+	perPagePtr *int,
+	orderBy string,
+) (*ctypes.ResultAccountTxs, error) {
+	if address == "" {
+		return nil, errors.New("address cannot be empty")
+	}
+	ai, ok := env.TxIndexer.(txindex.AddressIndexer)
+	if !ok {
+		return nil, txindex.ErrAddressIndexDisabled
+	}
 
-	page, err := validatePage(pagePtr, perPage, totalCount)
+	page, err := searchPage(nil, perPagePtr, orderBy, "desc", cursor)
 	if err != nil {
 		return nil, err
 	}
+	results, next, err := ai.AddressTxs(ctx.Context(), address, page)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Context().Err(); err != nil {
+		return nil, fmt.Errorf("search aborted: %w", err)
+	}
 
-	skipCount := validateSkipCount(page, perPage)
-	pageSize := cmtmath.MinInt(perPage, totalCount-skipCount)
-
-	apiResults := make([]*ctypes.ResultTx, 0, pageSize)
-	for i := skipCount; i < skipCount+pageSize; i++ {
-		r := results[i]
+	txs, err := resultTxs(results, prove)
+	if err != nil {
+		return nil, err
+	}
+	result := &ctypes.ResultAccountTxs{Txs: txs}
+	if next != nil {
+		result.NextCursor = next.String()
+	}
+	return result, nil
+}
 
+// resultTxs converts the results of the tx indexer, with their proofs if
+// prove is true.
+func resultTxs(results []*abci.TxResult, prove bool) ([]*ctypes.ResultTx, error) {
+	apiResults := make([]*ctypes.ResultTx, 0, len(results))
+	for _, r := range results {
 		var proof types.TxProof
 		if prove {
-			block := env.BlockStore.LoadBlock(r.Height)
-			proof = block.Data.Txs.Proof(int(r.Index)) // XXX: overflow on 32-bit machines
+			var err error
+			if proof, err = txProof(r.Height, r.Index); err != nil {
+				return nil, err
+			}
 		}
 
 		apiResults = append(apiResults, &ctypes.ResultTx{
@@ -130,13 +209,25 @@ func TxSearch(
 			Proof:    proof,
 		})
 	}
+	return apiResults, nil
+}
 
-	return &ctypes.ResultTxSearch{Txs: apiResults, TotalCount: totalCount}, nil
+// txProof returns the proof of the tx at index within the block at height, or
+// an error if the block is not stored, e.g. if it was pruned while the tx is
+// still indexed.
+func txProof(height int64, index uint32) (types.TxProof, error) {
+	block := env.BlockStore.LoadBlock(height)
+	if block == nil {
+		return types.TxProof{}, fmt.Errorf("cannot prove tx: block at height %d is not available, lowest height is %d",
+			height, env.BlockStore.Base())
+	}
+	return block.Data.Txs.Proof(int(index)), nil // XXX: overflow on 32-bit machines
 }
 
 // TxSearchMatchEvents allows you to query for multiple transactions results and match the
 // query attributes to a common event. It returns a
-// list of transactions (maximum ?per_page entries) and the total count.
+// list of transactions (maximum ?per_page entries) and the total count, if
+// count is true, which it defaults to without a cursor.
 // More: https://docs.cometbft.com/v0.34/rpc/#/Info/tx_search
 func TxSearchMatchEvents(
 	ctx *rpctypes.Context,
@@ -145,13 +236,107 @@ func TxSearchMatchEvents(
 	pagePtr, perPagePtr *int,
 	orderBy string,
 	matchEvents bool,
+	cursor string,
+	countPtr *bool,
 ) (*ctypes.ResultTxSearch, error) {
 
 	if matchEvents {
-		query = "match.events = 1 AND " + query
+		query = "match.events = 1 AND (" + query + ")"
 	} else {
-		query = "match.events = 0 AND " + query
+		query = "match.events = 0 AND (" + query + ")"
+	}
+	count := cursor == ""
+	if countPtr != nil {
+		count = *countPtr
+	}
+	return txSearch(ctx, query, prove, pagePtr, perPagePtr, orderBy, cursor, count)
+
+}
+
+// TxCount returns the number of transactions matching query, without
+// returning them.
+func TxCount(ctx *rpctypes.Context, query string, matchEvents bool) (*ctypes.ResultTxCount, error) {
+	q, err := txQuery(query, matchEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	count, err := txindex.Count(ctx.Context(), env.TxIndexer, q)
+	if err != nil {
+		return nil, err
+	}
+	if err := ctx.Context().Err(); err != nil {
+		return nil, fmt.Errorf("search aborted: %w", err)
+	}
+	return &ctypes.ResultTxCount{Count: count}, nil
+}
+
+// EventHistogram returns the number of transactions matching query by
+// buckets of bucket_size heights (100 by default), and the sum, minimum and
+// maximum of the integer values of their attribute (e.g. "transfer.amount"),
+// if not empty. Only buckets with transactions are returned, and there can be
+// at most 1000 buckets over the heights of the chain. Blocks are not
+// aggregated: use BlockCount to count them.
+func EventHistogram(
+	ctx *rpctypes.Context,
+	query string,
+	attribute string,
+	bucketSizePtr *int64,
+	matchEvents bool,
+) (*ctypes.ResultEventHistogram, error) {
+	bucketSize := int64(defaultBucketSize)
+	if bucketSizePtr != nil {
+		if bucketSize = *bucketSizePtr; bucketSize < 1 {
+			return nil, fmt.Errorf("bucket_size must be positive, got %d", bucketSize)
+		}
+	}
+	if height := env.BlockStore.Height(); (height+bucketSize-1)/bucketSize > maxHistogramBuckets {
+		return nil, fmt.Errorf("bucket_size must be at least %d for the %d heights of the chain",
+			(height+maxHistogramBuckets-1)/maxHistogramBuckets, height)
+	}
+	q, err := txQuery(query, matchEvents)
+	if err != nil {
+		return nil, err
+	}
+
+	h := txindex.NewHistogram(bucketSize)
+	if err := txindex.Aggregate(ctx.Context(), env.TxIndexer, q, attribute, h); err != nil {
+		return nil, err
+	}
+	if err := ctx.Context().Err(); err != nil {
+		return nil, fmt.Errorf("search aborted: %w", err)
 	}
-	return TxSearch(ctx, query, prove, pagePtr, perPagePtr, orderBy)
 
+	result := &ctypes.ResultEventHistogram{Buckets: make([]ctypes.HistogramBucket, 0)}
+	for _, b := range h.Buckets() {
+		bucket := ctypes.HistogramBucket{
+			FromHeight: b.FromHeight,
+			ToHeight:   b.ToHeight,
+			Count:      b.Count,
+			Values:     b.Values,
+		}
+		if b.Values > 0 {
+			bucket.Sum, bucket.Min, bucket.Max = b.Sum.String(), b.Min.String(), b.Max.String()
+		}
+		result.Buckets = append(result.Buckets, bucket)
+	}
+	return result, nil
+}
+
+// txQuery parses a query of the tx index, matching attributes within events
+// if matchEvents is true.
+func txQuery(query string, matchEvents bool) (*cmtquery.Query, error) {
+	// if index is disabled, return error
+	if _, ok := env.TxIndexer.(*null.TxIndex); ok {
+		return nil, errors.New("transaction indexing is disabled")
+	} else if len(query) > maxQueryLength {
+		return nil, errors.New("maximum query length exceeded")
+	}
+
+	if matchEvents {
+		query = "match.events = 1 AND (" + query + ")"
+	} else {
+		query = "match.events = 0 AND (" + query + ")"
+	}
+	return cmtquery.New(query)
 }
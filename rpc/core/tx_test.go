@@ -0,0 +1,76 @@
+package core
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/state/txindex"
+	"github.com/tendermint/tendermint/state/txindex/kv"
+	"github.com/tendermint/tendermint/types"
+)
+
+func TestTxProveMissingBlock(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	tx := types.Tx("tx")
+	batch := txindex.NewBatch(1)
+	require.NoError(t, batch.Add(&abci.TxResult{Height: 5, Tx: tx}))
+	require.NoError(t, txIndexer.AddBatch(batch))
+
+	// The block of the indexed tx was pruned.
+	env = &Environment{TxIndexer: txIndexer, BlockStore: mockBlockStore{height: 100}}
+	ctx := &rpctypes.Context{}
+
+	res, err := Tx(ctx, tx.Hash(), false)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, res.Height)
+	_, err = Tx(ctx, tx.Hash(), true)
+	require.ErrorContains(t, err, "block at height 5 is not available")
+
+	search, err := TxSearch(ctx, "tx.height = 5", false, nil, nil, "asc")
+	require.NoError(t, err)
+	require.Len(t, search.Txs, 1)
+	_, err = TxSearch(ctx, "tx.height = 5", true, nil, nil, "asc")
+	require.ErrorContains(t, err, "block at height 5 is not available")
+}
+
+func TestTxSearchCount(t *testing.T) {
+	txIndexer := kv.NewTxIndex(dbm.NewMemDB())
+	for height := int64(1); height <= 3; height++ {
+		batch := txindex.NewBatch(1)
+		require.NoError(t, batch.Add(&abci.TxResult{Height: height, Tx: types.Tx(fmt.Sprintf("tx%d", height))}))
+		require.NoError(t, txIndexer.AddBatch(batch))
+	}
+	env = &Environment{TxIndexer: txIndexer}
+	ctx := &rpctypes.Context{}
+	perPage := 2
+	yes, no := true, false
+
+	// Pages are counted by default, cursors are not.
+	res, err := TxSearchMatchEvents(ctx, "tx.height >= 1", false, nil, &perPage, "asc", false, "", nil)
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+	require.Equal(t, 3, res.TotalCount)
+	next, err := TxSearchMatchEvents(ctx, "tx.height >= 1", false, nil, &perPage, "asc", false, res.NextCursor, nil)
+	require.NoError(t, err)
+	require.Len(t, next.Txs, 1)
+	require.Zero(t, next.TotalCount)
+
+	res, err = TxSearchMatchEvents(ctx, "tx.height >= 1", false, nil, &perPage, "asc", false, "", &no)
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+	require.Zero(t, res.TotalCount)
+	next, err = TxSearchMatchEvents(ctx, "tx.height >= 1", false, nil, &perPage, "asc", false, res.NextCursor, &yes)
+	require.NoError(t, err)
+	require.Equal(t, 3, next.TotalCount)
+
+	res, err = TxSearchAfter(ctx, "tx.height >= 1", false, "", &perPage, "asc")
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+	require.Zero(t, res.TotalCount)
+}
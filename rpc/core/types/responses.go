@@ -7,6 +7,7 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/crypto"
 	"github.com/tendermint/tendermint/libs/bytes"
+	"github.com/tendermint/tendermint/mempool"
 	"github.com/tendermint/tendermint/p2p"
 	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
 	"github.com/tendermint/tendermint/types"
@@ -45,6 +46,13 @@ type ResultCommit struct {
 	CanonicalCommit    bool `json:"canonical"`
 }
 
+// Final returns true if the commit is canonical, the commit seen for the
+// latest block being replaced by the one of the next block. Without a
+// block, there is no commit yet.
+func (r *ResultCommit) Final() bool {
+	return r != nil && r.CanonicalCommit
+}
+
 // ABCI results from a block
 type ResultBlockResults struct {
 	Height                int64                     `json:"height"`
@@ -82,6 +90,12 @@ type SyncInfo struct {
 	EarliestBlockTime   time.Time      `json:"earliest_block_time"`
 
 	CatchingUp bool `json:"catching_up"`
+
+	// The height up to which all the blocks were indexed, the number of
+	// stored blocks above it, and whether the missing ones are being indexed.
+	IndexedHeight     int64 `json:"indexed_height"`
+	IndexerLag        int64 `json:"indexer_lag"`
+	IndexerCatchingUp bool  `json:"indexer_catching_up"`
 }
 
 // Info about the node's validator
@@ -114,6 +128,19 @@ type ResultNetInfo struct {
 	Peers     []Peer   `json:"peers"`
 }
 
+// Disk usage of the data directory
+type ResultDiskUsage struct {
+	Total     int64          `json:"total"`
+	Databases []DatabaseSize `json:"databases"`
+}
+
+// Size on disk of a database, or of another entry of the data directory, in
+// bytes
+type DatabaseSize struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
 // Log from dialing seeds
 type ResultDialSeeds struct {
 	Log string `json:"log"`
@@ -176,6 +203,25 @@ type ResultBroadcastTx struct {
 	Hash bytes.HexBytes `json:"hash"`
 }
 
+// CheckTx results of a batch of transactions, in the order they were given
+type ResultBroadcastTxs struct {
+	Txs []*ResultBroadcastTxsItem `json:"txs"`
+}
+
+// CheckTx result of one transaction of a batch
+type ResultBroadcastTxsItem struct {
+	Code      uint32         `json:"code"`
+	Data      bytes.HexBytes `json:"data"`
+	Log       string         `json:"log"`
+	Codespace string         `json:"codespace"`
+
+	Hash bytes.HexBytes `json:"hash"`
+
+	// Error is set if the transaction could not be submitted to CheckTx, e.g.
+	// because it is already in the cache or the mempool is full.
+	Error string `json:"error,omitempty"`
+}
+
 // CheckTx and DeliverTx results
 type ResultBroadcastTxCommit struct {
 	CheckTx   abci.ResponseCheckTx   `json:"check_tx"`
@@ -203,12 +249,75 @@ type ResultTx struct {
 type ResultTxSearch struct {
 	Txs        []*ResultTx `json:"txs"`
 	TotalCount int         `json:"total_count"`
+	// Cursor to pass to get the next results, if any.
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // ResultBlockSearch defines the RPC response type for a block search by events.
 type ResultBlockSearch struct {
 	Blocks     []*ResultBlock `json:"blocks"`
 	TotalCount int            `json:"total_count"`
+	// Cursor to pass to get the next results, if any.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ResultAccountTxs is a page of the txs involving an address.
+type ResultAccountTxs struct {
+	Txs []*ResultTx `json:"txs"`
+	// Cursor to pass to get the next txs, if any.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ResultTxCount is the number of txs matching a query.
+type ResultTxCount struct {
+	Count int `json:"count"`
+}
+
+// ResultBlockCount is the number of blocks matching a query.
+type ResultBlockCount struct {
+	Count int `json:"count"`
+}
+
+// HistogramBucket aggregates the txs matching a query within a range of
+// heights.
+type HistogramBucket struct {
+	FromHeight int64 `json:"from_height"`
+	ToHeight   int64 `json:"to_height"`
+	// Number of txs.
+	Count int `json:"count"`
+	// Number of integer values of the aggregated attribute, and their sum,
+	// minimum and maximum, as decimal strings.
+	Values int    `json:"values"`
+	Sum    string `json:"sum,omitempty"`
+	Min    string `json:"min,omitempty"`
+	Max    string `json:"max,omitempty"`
+}
+
+// ResultEventHistogram holds the buckets of heights with txs matching a query.
+type ResultEventHistogram struct {
+	Buckets []HistogramBucket `json:"buckets"`
+}
+
+// Statuses of a transaction reported by tx_status.
+const (
+	TxStatusPending   = "pending"
+	TxStatusRejected  = "rejected"
+	TxStatusEvicted   = "evicted"
+	TxStatusCommitted = "committed"
+	TxStatusUnknown   = "unknown"
+)
+
+// ResultTxStatus describes what happened to a transaction seen by the node.
+type ResultTxStatus struct {
+	Hash   bytes.HexBytes `json:"hash"`
+	Status string         `json:"status"`
+	// Height is the height of the block that included the transaction, if
+	// committed.
+	Height int64 `json:"height"`
+	// Code is the last CheckTx or DeliverTx response code.
+	Code   uint32            `json:"code"`
+	Reason string            `json:"reason,omitempty"`
+	Events []mempool.TxEvent `json:"events"`
 }
 
 // List of mempool txs
@@ -245,7 +354,13 @@ type (
 
 // Event data from a subscription
 type ResultEvent struct {
-	Query  string              `json:"query"`
+	Query string `json:"query"`
+	// Epoch of the sequence numbers of the events matching the query, and
+	// sequence number of the event among them, set for WebSocket
+	// subscriptions. Pass the epoch, and the next sequence number as
+	// "from_seq", to /subscribe to resume a subscription.
+	Epoch  string              `json:"epoch,omitempty"`
+	Seq    int64               `json:"seq,omitempty"`
 	Data   types.TMEventData   `json:"data"`
 	Events map[string][]string `json:"events"`
 }
@@ -6,6 +6,7 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 	core "github.com/tendermint/tendermint/rpc/core"
 	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
 )
 
 type broadcastAPI struct {
@@ -37,3 +38,30 @@ func (bapi *broadcastAPI) BroadcastTx(ctx context.Context, req *RequestBroadcast
 		},
 	}, nil
 }
+
+func (bapi *broadcastAPI) BroadcastTxs(ctx context.Context, req *RequestBroadcastTxs) (*ResponseBroadcastTxs, error) {
+	txs := make(types.Txs, len(req.Txs))
+	for i, tx := range req.Txs {
+		txs[i] = tx
+	}
+
+	res, err := core.BroadcastTxsSync(&rpctypes.Context{}, txs)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*ResponseBroadcastTxsItem, len(res.Txs))
+	for i, r := range res.Txs {
+		items[i] = &ResponseBroadcastTxsItem{
+			Hash: r.Hash,
+			CheckTx: &abci.ResponseCheckTx{
+				Code:      r.Code,
+				Data:      r.Data,
+				Log:       r.Log,
+				Codespace: r.Codespace,
+			},
+			Error: r.Error,
+		}
+	}
+	return &ResponseBroadcastTxs{Txs: items}, nil
+}
@@ -0,0 +1,137 @@
+package coregrpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	rpcserver "github.com/tendermint/tendermint/rpc/jsonrpc/server"
+)
+
+// methodRoutes maps the gRPC methods to the RPC routes they are equivalent
+// to, for the API keys and rate limits configured for those routes to apply.
+var methodRoutes = map[string]string{
+	"/tendermint.rpc.grpc.BroadcastAPI/Ping":         "health",
+	"/tendermint.rpc.grpc.BroadcastAPI/BroadcastTx":  "broadcast_tx_commit",
+	"/tendermint.rpc.grpc.BroadcastAPI/BroadcastTxs": "broadcast_txs_sync",
+
+	"/tendermint.rpc.grpc.QueryAPI/Status":             "status",
+	"/tendermint.rpc.grpc.QueryAPI/Block":              "block",
+	"/tendermint.rpc.grpc.QueryAPI/BlockResults":       "block_results",
+	"/tendermint.rpc.grpc.QueryAPI/Commit":             "commit",
+	"/tendermint.rpc.grpc.QueryAPI/Validators":         "validators",
+	"/tendermint.rpc.grpc.QueryAPI/Tx":                 "tx",
+	"/tendermint.rpc.grpc.QueryAPI/TxSearch":           "tx_search",
+	"/tendermint.rpc.grpc.QueryAPI/BlockSearch":        "block_search",
+	"/tendermint.rpc.grpc.QueryAPI/ABCIQuery":          "abci_query",
+	"/tendermint.rpc.grpc.QueryAPI/SubscribeNewBlocks": "subscribe",
+	"/tendermint.rpc.grpc.QueryAPI/SubscribeEvents":    "subscribe",
+	"/tendermint.rpc.grpc.QueryAPI/SubscribeBlocks":    "subscribe",
+}
+
+// ServerOption sets an optional parameter of the gRPC server.
+type ServerOption func(*serverOptions)
+
+type serverOptions struct {
+	auth        *rpcserver.APIKeyAuth
+	rateLimiter *rpcserver.RateLimiter
+}
+
+// RequireAPIKey makes the server reject calls without a valid API key, passed
+// in the "authorization: Bearer <key>" or "x-api-key: <key>" metadata, and
+// calls to methods whose route is not allowed for the key.
+func RequireAPIKey(auth *rpcserver.APIKeyAuth) ServerOption {
+	return func(opts *serverOptions) {
+		opts.auth = auth
+	}
+}
+
+// RateLimitCalls makes the server reject calls exceeding the limits of
+// limiter, counted as calls to the routes of the methods. Subscriptions are
+// counted once, when they are made.
+func RateLimitCalls(limiter *rpcserver.RateLimiter) ServerOption {
+	return func(opts *serverOptions) {
+		opts.rateLimiter = limiter
+	}
+}
+
+func (opts *serverOptions) unaryInterceptor(
+	ctx context.Context,
+	req interface{},
+	info *grpc.UnaryServerInfo,
+	handler grpc.UnaryHandler,
+) (interface{}, error) {
+	if err := opts.authorize(ctx, info.FullMethod, func(md metadata.MD) error {
+		return grpc.SetHeader(ctx, md)
+	}); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (opts *serverOptions) streamInterceptor(
+	srv interface{},
+	ss grpc.ServerStream,
+	info *grpc.StreamServerInfo,
+	handler grpc.StreamHandler,
+) error {
+	if err := opts.authorize(ss.Context(), info.FullMethod, ss.SetHeader); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// authorize applies the API keys and rate limits to the call of method made
+// with ctx, and returns the gRPC status error of a rejected call. Rate limited
+// calls get a "retry-after" header, in seconds, set with setHeader.
+func (opts *serverOptions) authorize(
+	ctx context.Context,
+	method string,
+	setHeader func(metadata.MD) error,
+) error {
+	if opts.auth == nil && opts.rateLimiter == nil {
+		return nil
+	}
+	route, ok := methodRoutes[method]
+	if !ok {
+		route = method
+	}
+
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		header := make(http.Header, len(md))
+		for name, values := range md {
+			for _, value := range values {
+				header.Add(name, value)
+			}
+		}
+		key = rpcserver.APIKeyFromHeader(header)
+	}
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+
+	wait, err := rpcserver.AuthorizeCall(opts.auth, opts.rateLimiter, key, remoteAddr, route)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, rpcserver.ErrUnauthenticated):
+		return status.Error(codes.Unauthenticated, err.Error())
+	case errors.Is(err, rpcserver.ErrRouteNotAllowed):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, rpcserver.ErrRateLimited):
+		_ = setHeader(metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(wait.Seconds())))))
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -14,12 +14,21 @@ type Config struct {
 	MaxOpenConnections int
 }
 
-// StartGRPCServer starts a new gRPC BroadcastAPIServer using the given
-// net.Listener.
+// StartGRPCServer starts a new gRPC server exposing the BroadcastAPI and the
+// QueryAPI using the given net.Listener. The options apply the API keys and
+// rate limits of the RPC server to its calls.
 // NOTE: This function blocks - you may want to call it in a go-routine.
-func StartGRPCServer(ln net.Listener) error {
-	grpcServer := grpc.NewServer()
+func StartGRPCServer(ln net.Listener, options ...ServerOption) error {
+	opts := &serverOptions{}
+	for _, option := range options {
+		option(opts)
+	}
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(opts.unaryInterceptor),
+		grpc.StreamInterceptor(opts.streamInterceptor),
+	)
 	RegisterBroadcastAPIServer(grpcServer, &broadcastAPI{})
+	RegisterQueryAPIServer(grpcServer, &queryAPI{})
 	return grpcServer.Serve(ln)
 }
 
@@ -34,6 +43,17 @@ func StartGRPCClient(protoAddr string) BroadcastAPIClient {
 	return NewBroadcastAPIClient(conn)
 }
 
+// StartGRPCQueryClient dials the gRPC server using protoAddr and returns a new
+// QueryAPIClient.
+func StartGRPCQueryClient(protoAddr string) QueryAPIClient {
+	//nolint:staticcheck // SA1019 Existing use of deprecated but supported dial option.
+	conn, err := grpc.Dial(protoAddr, grpc.WithInsecure(), grpc.WithContextDialer(dialerFunc))
+	if err != nil {
+		panic(err)
+	}
+	return NewQueryAPIClient(conn)
+}
+
 func dialerFunc(ctx context.Context, addr string) (net.Conn, error) {
 	return cmtnet.Connect(addr)
 }
@@ -2,13 +2,19 @@ package coregrpc_test
 
 import (
 	"context"
+	"net"
 	"os"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	"github.com/tendermint/tendermint/abci/example/kvstore"
 	core_grpc "github.com/tendermint/tendermint/rpc/grpc"
+	rpcserver "github.com/tendermint/tendermint/rpc/jsonrpc/server"
 	rpctest "github.com/tendermint/tendermint/rpc/test"
 )
 
@@ -33,3 +39,140 @@ func TestBroadcastTx(t *testing.T) {
 	require.EqualValues(t, 0, res.CheckTx.Code)
 	require.EqualValues(t, 0, res.DeliverTx.Code)
 }
+
+func TestBroadcastTxs(t *testing.T) {
+	tx := []byte("this is another tx")
+	res, err := rpctest.GetGRPCClient().BroadcastTxs(
+		context.Background(),
+		&core_grpc.RequestBroadcastTxs{Txs: [][]byte{tx, tx}},
+	)
+	require.NoError(t, err)
+	require.Len(t, res.Txs, 2)
+	require.EqualValues(t, 0, res.Txs[0].CheckTx.Code)
+	require.Empty(t, res.Txs[0].Error)
+	// The second copy is rejected by the mempool cache.
+	require.NotEmpty(t, res.Txs[1].Error)
+}
+
+func TestQueryAPI(t *testing.T) {
+	client := rpctest.GetGRPCQueryClient()
+	ctx := context.Background()
+
+	status, err := client.Status(ctx, &core_grpc.RequestStatus{})
+	require.NoError(t, err)
+	require.NotEmpty(t, status.NodeInfo.Network)
+	height := status.SyncInfo.LatestBlockHeight
+	require.Positive(t, height)
+
+	block, err := client.Block(ctx, &core_grpc.RequestBlock{Height: height})
+	require.NoError(t, err)
+	require.EqualValues(t, height, block.Block.Header.Height)
+	require.NotEmpty(t, block.BlockID.Hash)
+
+	commit, err := client.Commit(ctx, &core_grpc.RequestCommit{Height: height})
+	require.NoError(t, err)
+	require.Equal(t, block.BlockID.Hash, commit.SignedHeader.Commit.BlockID.Hash)
+
+	vals, err := client.Validators(ctx, &core_grpc.RequestValidators{})
+	require.NoError(t, err)
+	require.EqualValues(t, 1, vals.Total)
+	require.Len(t, vals.Validators, 1)
+
+	_, err = client.BlockResults(ctx, &core_grpc.RequestBlockResults{Height: height})
+	require.NoError(t, err)
+
+	query, err := client.ABCIQuery(ctx, &core_grpc.RequestABCIQuery{Data: []byte("this is a tx")})
+	require.NoError(t, err)
+	require.True(t, query.Response.IsOK())
+
+	_, err = client.Block(ctx, &core_grpc.RequestBlock{Height: height + 1000})
+	require.Error(t, err)
+}
+
+func TestSubscribeNewBlocks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := rpctest.GetGRPCQueryClient().SubscribeNewBlocks(ctx, &core_grpc.RequestSubscribeNewBlocks{})
+	require.NoError(t, err)
+
+	first, err := stream.Recv()
+	require.NoError(t, err)
+	second, err := stream.Recv()
+	require.NoError(t, err)
+	require.Equal(t, first.Block.Header.Height+1, second.Block.Header.Height)
+	require.Equal(t, first.BlockID.Hash, second.Block.Header.LastBlockId.Hash)
+}
+
+func TestSubscribeBlocks(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := rpctest.GetGRPCQueryClient()
+	status, err := client.Status(ctx, &core_grpc.RequestStatus{})
+	require.NoError(t, err)
+	latest := status.SyncInfo.LatestBlockHeight
+
+	stream, err := client.SubscribeBlocks(ctx, &core_grpc.RequestSubscribeBlocks{FromHeight: 1})
+	require.NoError(t, err)
+
+	// Stored blocks are followed by new ones without gaps.
+	for height := int64(1); height <= latest+2; height++ {
+		res, err := stream.Recv()
+		require.NoError(t, err)
+		require.Equal(t, height, res.Block.Block.Header.Height)
+		require.Equal(t, height, res.BlockResults.Height)
+		require.Equal(t, height+1, res.Cursor)
+	}
+}
+
+func TestSubscribeConcurrentStreams(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// The streams of a connection subscribe to new blocks separately.
+	client := rpctest.GetGRPCQueryClient()
+	newBlocks1, err := client.SubscribeNewBlocks(ctx, &core_grpc.RequestSubscribeNewBlocks{})
+	require.NoError(t, err)
+	newBlocks2, err := client.SubscribeNewBlocks(ctx, &core_grpc.RequestSubscribeNewBlocks{})
+	require.NoError(t, err)
+	blocks, err := client.SubscribeBlocks(ctx, &core_grpc.RequestSubscribeBlocks{})
+	require.NoError(t, err)
+
+	_, err = newBlocks1.Recv()
+	require.NoError(t, err)
+	_, err = newBlocks2.Recv()
+	require.NoError(t, err)
+	_, err = blocks.Recv()
+	require.NoError(t, err)
+}
+
+func TestAuthAndRateLimit(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+	auth := rpcserver.NewAPIKeyAuth(map[string][]string{
+		"reader": {"status"},
+		"admin":  {"health", "status"},
+	})
+	limiter := rpcserver.NewRateLimiter(rpcserver.RateLimit{Rate: 1}, rpcserver.RateLimit{}, nil, nil)
+	go core_grpc.StartGRPCServer(ln, core_grpc.RequireAPIKey(auth), core_grpc.RateLimitCalls(limiter)) //nolint:errcheck
+
+	client := core_grpc.StartGRPCClient("tcp://" + ln.Addr().String())
+	ping := func(md ...string) (metadata.MD, error) {
+		ctx := metadata.AppendToOutgoingContext(context.Background(), md...)
+		var header metadata.MD
+		_, err := client.Ping(ctx, &core_grpc.RequestPing{}, grpc.Header(&header))
+		return header, err
+	}
+
+	_, err = ping()
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+	_, err = ping("authorization", "Bearer reader")
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	_, err = ping("x-api-key", "admin")
+	require.NoError(t, err)
+	header, err := ping("authorization", "Bearer admin")
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, []string{"1"}, header.Get("retry-after"))
+}
@@ -0,0 +1,290 @@
+package coregrpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/peer"
+
+	cryptoenc "github.com/tendermint/tendermint/crypto/encoding"
+	cmtjson "github.com/tendermint/tendermint/libs/json"
+	cmtpubsub "github.com/tendermint/tendermint/libs/pubsub"
+	cmtproto "github.com/tendermint/tendermint/proto/tendermint/types"
+	core "github.com/tendermint/tendermint/rpc/core"
+	ctypes "github.com/tendermint/tendermint/rpc/core/types"
+	rpctypes "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// queryAPI exposes the read-only routes of the JSON-RPC server over gRPC. It
+// reuses the rpc/core handlers, so it behaves like the equivalent routes.
+type queryAPI struct {
+}
+
+func (qapi *queryAPI) Status(ctx context.Context, req *RequestStatus) (*ResponseStatus, error) {
+	res, err := core.Status(&rpctypes.Context{})
+	if err != nil {
+		return nil, err
+	}
+
+	valInfo := &ValidatorInfo{
+		Address:     res.ValidatorInfo.Address,
+		VotingPower: res.ValidatorInfo.VotingPower,
+	}
+	if res.ValidatorInfo.PubKey != nil {
+		pk, err := cryptoenc.PubKeyToProto(res.ValidatorInfo.PubKey)
+		if err != nil {
+			return nil, err
+		}
+		valInfo.PubKey = &pk
+	}
+
+	return &ResponseStatus{
+		NodeInfo: res.NodeInfo.ToProto(),
+		SyncInfo: &SyncInfo{
+			LatestBlockHash:     res.SyncInfo.LatestBlockHash,
+			LatestAppHash:       res.SyncInfo.LatestAppHash,
+			LatestBlockHeight:   res.SyncInfo.LatestBlockHeight,
+			LatestBlockTime:     res.SyncInfo.LatestBlockTime,
+			EarliestBlockHash:   res.SyncInfo.EarliestBlockHash,
+			EarliestAppHash:     res.SyncInfo.EarliestAppHash,
+			EarliestBlockHeight: res.SyncInfo.EarliestBlockHeight,
+			EarliestBlockTime:   res.SyncInfo.EarliestBlockTime,
+			CatchingUp:          res.SyncInfo.CatchingUp,
+		},
+		ValidatorInfo: valInfo,
+	}, nil
+}
+
+func (qapi *queryAPI) Block(ctx context.Context, req *RequestBlock) (*ResponseBlock, error) {
+	res, err := core.Block(&rpctypes.Context{}, heightPtr(req.Height))
+	if err != nil {
+		return nil, err
+	}
+	return blockToProto(res)
+}
+
+func (qapi *queryAPI) BlockResults(ctx context.Context, req *RequestBlockResults) (*ResponseBlockResults, error) {
+	res, err := core.BlockResults(&rpctypes.Context{}, heightPtr(req.Height))
+	if err != nil {
+		return nil, err
+	}
+	return blockResultsToProto(res), nil
+}
+
+func (qapi *queryAPI) Commit(ctx context.Context, req *RequestCommit) (*ResponseCommit, error) {
+	res, err := core.Commit(&rpctypes.Context{}, heightPtr(req.Height))
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseCommit{
+		SignedHeader: res.SignedHeader.ToProto(),
+		Canonical:    res.CanonicalCommit,
+	}, nil
+}
+
+func (qapi *queryAPI) Validators(ctx context.Context, req *RequestValidators) (*ResponseValidators, error) {
+	res, err := core.Validators(&rpctypes.Context{}, heightPtr(req.Height), intPtr(req.Page), intPtr(req.PerPage))
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]*cmtproto.Validator, len(res.Validators))
+	for i, v := range res.Validators {
+		if vals[i], err = v.ToProto(); err != nil {
+			return nil, err
+		}
+	}
+	return &ResponseValidators{
+		BlockHeight: res.BlockHeight,
+		Validators:  vals,
+		Count:       int32(res.Count),
+		Total:       int32(res.Total),
+	}, nil
+}
+
+func (qapi *queryAPI) Tx(ctx context.Context, req *RequestTx) (*ResponseTx, error) {
+	res, err := core.Tx(&rpctypes.Context{}, req.Hash, req.Prove)
+	if err != nil {
+		return nil, err
+	}
+	return txToProto(res, req.Prove), nil
+}
+
+func (qapi *queryAPI) TxSearch(ctx context.Context, req *RequestTxSearch) (*ResponseTxSearch, error) {
+	res, err := core.TxSearch((&rpctypes.Context{}).WithContext(ctx), req.Query, req.Prove,
+		intPtr(req.Page), intPtr(req.PerPage), req.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	txs := make([]*ResponseTx, len(res.Txs))
+	for i, r := range res.Txs {
+		txs[i] = txToProto(r, req.Prove)
+	}
+	return &ResponseTxSearch{Txs: txs, TotalCount: int32(res.TotalCount)}, nil
+}
+
+func (qapi *queryAPI) BlockSearch(ctx context.Context, req *RequestBlockSearch) (*ResponseBlockSearch, error) {
+	res, err := core.BlockSearch((&rpctypes.Context{}).WithContext(ctx), req.Query,
+		intPtr(req.Page), intPtr(req.PerPage), req.OrderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	blocks := make([]*ResponseBlock, len(res.Blocks))
+	for i, r := range res.Blocks {
+		if blocks[i], err = blockToProto(r); err != nil {
+			return nil, err
+		}
+	}
+	return &ResponseBlockSearch{Blocks: blocks, TotalCount: int32(res.TotalCount)}, nil
+}
+
+func (qapi *queryAPI) ABCIQuery(ctx context.Context, req *RequestABCIQuery) (*ResponseABCIQuery, error) {
+	res, err := core.ABCIQuery((&rpctypes.Context{}).WithContext(ctx), req.Path, req.Data, req.Height, req.Prove)
+	if err != nil {
+		return nil, err
+	}
+	return &ResponseABCIQuery{Response: &res.Response}, nil
+}
+
+func (qapi *queryAPI) SubscribeNewBlocks(req *RequestSubscribeNewBlocks, stream QueryAPI_SubscribeNewBlocksServer) error {
+	return subscribe(stream.Context(), types.EventQueryNewBlock.String(), func(msg cmtpubsub.Message) error {
+		data, ok := msg.Data().(types.EventDataNewBlock)
+		if !ok {
+			return fmt.Errorf("unexpected event data %T", msg.Data())
+		}
+		blockID := types.BlockID{
+			Hash:          data.Block.Hash(),
+			PartSetHeader: data.Block.MakePartSet(types.BlockPartSizeBytes).Header(),
+		}
+		res, err := blockToProto(&ctypes.ResultBlock{BlockID: blockID, Block: data.Block})
+		if err != nil {
+			return err
+		}
+		return stream.Send(res)
+	})
+}
+
+func (qapi *queryAPI) SubscribeEvents(req *RequestSubscribeEvents, stream QueryAPI_SubscribeEventsServer) error {
+	return subscribe(stream.Context(), req.Query, func(msg cmtpubsub.Message) error {
+		data, err := cmtjson.Marshal(msg.Data())
+		if err != nil {
+			return err
+		}
+		events := make([]*EventAttributes, 0, len(msg.Events()))
+		for key, values := range msg.Events() {
+			events = append(events, &EventAttributes{Key: key, Values: values})
+		}
+		return stream.Send(&ResponseEvent{Query: req.Query, Data: data, Events: events})
+	})
+}
+
+func (qapi *queryAPI) SubscribeBlocks(req *RequestSubscribeBlocks, stream QueryAPI_SubscribeBlocksServer) error {
+	ctx := stream.Context()
+	return core.StreamBlocks(ctx, clientOf(ctx), req.FromHeight,
+		func(block *ctypes.ResultBlock, results *ctypes.ResultBlockResults) error {
+			b, err := blockToProto(block)
+			if err != nil {
+				return err
+			}
+			return stream.Send(&ResponseBlockWithResults{
+				Block:        b,
+				BlockResults: blockResultsToProto(results),
+				Cursor:       results.Height + 1,
+			})
+		})
+}
+
+// clientOf returns the client the subscriptions of the gRPC peer of ctx are
+// limited as.
+func clientOf(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok {
+		return p.Addr.String()
+	}
+	return "grpc"
+}
+
+// subscribe subscribes the gRPC peer of ctx to query and calls send for every
+// matching event until ctx is done or the subscription is cancelled.
+func subscribe(ctx context.Context, query string, send func(cmtpubsub.Message) error) error {
+	sub, unsubscribe, err := core.SubscribeStream(ctx, clientOf(ctx), query)
+	if err != nil {
+		return err
+	}
+	defer unsubscribe()
+
+	for {
+		select {
+		case msg := <-sub.Out():
+			if err := send(msg); err != nil {
+				return err
+			}
+		case <-sub.Cancelled():
+			if err := sub.Err(); err != nil && err != cmtpubsub.ErrUnsubscribed {
+				return fmt.Errorf("subscription was cancelled (reason: %w)", err)
+			}
+			return errors.New("subscription was cancelled (reason: CometBFT exited)")
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func blockToProto(res *ctypes.ResultBlock) (*ResponseBlock, error) {
+	blockID := res.BlockID.ToProto()
+	resp := &ResponseBlock{BlockID: &blockID}
+	if res.Block != nil {
+		block, err := res.Block.ToProto()
+		if err != nil {
+			return nil, err
+		}
+		resp.Block = block
+	}
+	return resp, nil
+}
+
+func blockResultsToProto(res *ctypes.ResultBlockResults) *ResponseBlockResults {
+	return &ResponseBlockResults{
+		Height:                res.Height,
+		TxsResults:            res.TxsResults,
+		BeginBlockEvents:      res.BeginBlockEvents,
+		EndBlockEvents:        res.EndBlockEvents,
+		ValidatorUpdates:      res.ValidatorUpdates,
+		ConsensusParamUpdates: res.ConsensusParamUpdates,
+	}
+}
+
+func txToProto(res *ctypes.ResultTx, prove bool) *ResponseTx {
+	resp := &ResponseTx{
+		Hash:     res.Hash,
+		Height:   res.Height,
+		Index:    res.Index,
+		TxResult: &res.TxResult,
+		Tx:       res.Tx,
+	}
+	if prove {
+		proof := res.Proof.ToProto()
+		resp.Proof = &proof
+	}
+	return resp
+}
+
+// heightPtr maps the height of a request to the argument of the rpc/core
+// handlers, where nil means the latest height.
+func heightPtr(height int64) *int64 {
+	if height == 0 {
+		return nil
+	}
+	return &height
+}
+
+func intPtr(i int32) *int {
+	if i == 0 {
+		return nil
+	}
+	v := int(i)
+	return &v
+}
@@ -532,6 +532,14 @@ func (c *WSClient) Subscribe(ctx context.Context, query string) error {
 	return c.Call(ctx, "subscribe", params)
 }
 
+// SubscribeFrom subscribes to a query, resuming from the event with sequence
+// number fromSeq in epoch, e.g. after reconnecting. Note the server must have
+// a "subscribe" route defined, which takes "from_seq" and "epoch" parameters.
+func (c *WSClient) SubscribeFrom(ctx context.Context, query, epoch string, fromSeq int64) error {
+	params := map[string]interface{}{"query": query, "from_seq": fromSeq, "epoch": epoch}
+	return c.Call(ctx, "subscribe", params)
+}
+
 // Unsubscribe from a query. Note the server must have a "unsubscribe" route
 // defined.
 func (c *WSClient) Unsubscribe(ctx context.Context, query string) error {
@@ -0,0 +1,100 @@
+package server
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrUnauthenticated is returned for requests without a valid API key.
+	ErrUnauthenticated = errors.New("missing or invalid API key")
+
+	// ErrRouteNotAllowed is returned for calls to routes not allowed for the
+	// API key of the request.
+	ErrRouteNotAllowed = errors.New("API key is not allowed to call")
+)
+
+// APIKeyAuth authenticates requests by API key and authorizes each call
+// according to the routes allowed for that key.
+type APIKeyAuth struct {
+	// keys are indexed by their hash, so that looking one up does not leak
+	// its content through timing.
+	acl map[[sha256.Size]byte]map[string]struct{}
+}
+
+// NewAPIKeyAuth returns an APIKeyAuth for the given map from API keys to the
+// names of the routes they are allowed to call.
+func NewAPIKeyAuth(acl map[string][]string) *APIKeyAuth {
+	a := &APIKeyAuth{acl: make(map[[sha256.Size]byte]map[string]struct{}, len(acl))}
+	for key, routes := range acl {
+		allowed := make(map[string]struct{}, len(routes))
+		for _, route := range routes {
+			allowed[route] = struct{}{}
+		}
+		a.acl[sha256.Sum256([]byte(key))] = allowed
+	}
+	return a
+}
+
+// APIKey returns the API key of r, passed either in the "Authorization: Bearer
+// <key>" or the "X-API-Key: <key>" header, or "" if none.
+func APIKey(r *http.Request) string {
+	return APIKeyFromHeader(r.Header)
+}
+
+// APIKeyFromHeader returns the API key passed in h, as APIKey does.
+func APIKeyFromHeader(h http.Header) string {
+	if auth := h.Get("Authorization"); auth != "" {
+		const prefix = "bearer "
+		if len(auth) > len(prefix) && strings.EqualFold(auth[:len(prefix)], prefix) {
+			return strings.TrimSpace(auth[len(prefix):])
+		}
+		return ""
+	}
+	return h.Get("X-API-Key")
+}
+
+// authenticate returns the routes allowed for the API key of r, or
+// ErrUnauthenticated. A nil APIKeyAuth allows all routes.
+func (a *APIKeyAuth) authenticate(r *http.Request) (apiKeyRoutes, error) {
+	return a.routesOf(APIKey(r))
+}
+
+// routesOf returns the routes allowed for key, or ErrUnauthenticated. A nil
+// APIKeyAuth allows all routes.
+func (a *APIKeyAuth) routesOf(key string) (apiKeyRoutes, error) {
+	if a == nil {
+		return nil, nil
+	}
+	allowed, ok := a.acl[sha256.Sum256([]byte(key))]
+	if !ok {
+		return nil, ErrUnauthenticated
+	}
+	return allowed, nil
+}
+
+// apiKeyRoutes is the set of routes an API key is allowed to call. A nil set
+// allows all routes.
+type apiKeyRoutes map[string]struct{}
+
+// authorize returns an error if method is not allowed.
+func (routes apiKeyRoutes) authorize(method string) error {
+	if routes == nil {
+		return nil
+	}
+	if _, ok := routes[method]; !ok {
+		return fmt.Errorf("%w %s", ErrRouteNotAllowed, method)
+	}
+	return nil
+}
+
+// RequireAPIKey makes the handlers reject requests without a valid API key,
+// and calls to routes not allowed for the key.
+func RequireAPIKey(auth *APIKeyAuth) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.auth = auth
+	}
+}
@@ -0,0 +1,151 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+func testAuth() *APIKeyAuth {
+	return NewAPIKeyAuth(map[string][]string{
+		"reader": {"block"},
+		"admin":  {"block", "c"},
+	})
+}
+
+func TestAPIKeyAuthHTTP(t *testing.T) {
+	funcMap := map[string]*RPCFunc{
+		"c":     NewRPCFunc(func(ctx *types.Context, s string, i int) (string, error) { return "foo", nil }, "s,i"),
+		"block": NewRPCFunc(func(ctx *types.Context, h int) (string, error) { return "block", nil }, "height"),
+	}
+	mux := http.NewServeMux()
+	RegisterRPCFuncs(mux, funcMap, log.TestingLogger(), RequireAPIKey(testAuth()))
+
+	tests := []struct {
+		path       string
+		header     string
+		value      string
+		wantStatus int
+	}{
+		{"/block?height=1", "", "", http.StatusUnauthorized},
+		{"/block?height=1", "X-API-Key", "wrong", http.StatusUnauthorized},
+		{"/block?height=1", "Authorization", "reader", http.StatusUnauthorized},
+		{"/block?height=1", "X-API-Key", "reader", http.StatusOK},
+		{"/block?height=1", "Authorization", "Bearer reader", http.StatusOK},
+		{"/c?s=\"a\"&i=1", "Authorization", "Bearer reader", http.StatusForbidden},
+		{"/c?s=\"a\"&i=1", "Authorization", "bearer admin", http.StatusOK},
+	}
+	for i, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		assert.Equal(t, tt.wantStatus, rec.Code, "#%d", i)
+	}
+
+	// Within a batch, calls not allowed for the key fail individually.
+	body := `[{"jsonrpc":"2.0","id":1,"method":"block","params":{"height":"1"}},` +
+		`{"jsonrpc":"2.0","id":2,"method":"c","params":{"s":"a","i":"1"}}]`
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(body))
+	req.Header.Set("X-API-Key", "reader")
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	blob, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	var responses []types.RPCResponse
+	require.NoError(t, json.Unmarshal(blob, &responses))
+	require.Len(t, responses, 2)
+	assert.Nil(t, responses[0].Error)
+	require.NotNil(t, responses[1].Error)
+	assert.Equal(t, -32001, responses[1].Error.Code)
+
+	req = httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestAPIKeyAuthWebsocket(t *testing.T) {
+	funcMap := map[string]*RPCFunc{
+		"c":     NewWSRPCFunc(func(ctx *types.Context, s string, i int) (string, error) { return "foo", nil }, "s,i"),
+		"block": NewWSRPCFunc(func(ctx *types.Context, h int) (string, error) { return "block", nil }, "height"),
+	}
+	wm := NewWebsocketManager(funcMap)
+	wm.SetLogger(log.TestingLogger())
+	wm.SetAPIKeyAuth(testAuth())
+	mux := http.NewServeMux()
+	mux.HandleFunc("/websocket", wm.WebsocketHandler)
+	s := httptest.NewServer(mux)
+	defer s.Close()
+
+	url := "ws://" + s.Listener.Addr().String() + "/websocket"
+	d := websocket.Dialer{}
+
+	_, dialResp, err := d.Dial(url, nil)
+	require.Error(t, err)
+	require.Equal(t, http.StatusUnauthorized, dialResp.StatusCode)
+	dialResp.Body.Close()
+
+	c, dialResp, err := d.Dial(url, http.Header{"X-Api-Key": []string{"reader"}})
+	require.NoError(t, err)
+	defer dialResp.Body.Close()
+
+	call := func(method string, params map[string]interface{}) types.RPCResponse {
+		req, err := types.MapToRequest(types.JSONRPCStringID("TestAPIKeyAuthWebsocket"), method, params)
+		require.NoError(t, err)
+		require.NoError(t, c.WriteJSON(req))
+		var resp types.RPCResponse
+		require.NoError(t, c.ReadJSON(&resp))
+		return resp
+	}
+
+	resp := call("block", map[string]interface{}{"height": 1})
+	require.Nil(t, resp.Error)
+	resp = call("c", map[string]interface{}{"s": "a", "i": 10})
+	require.NotNil(t, resp.Error)
+	require.Equal(t, -32001, resp.Error.Code)
+}
+
+func TestAuthorizeCall(t *testing.T) {
+	rl := NewRateLimiter(RateLimit{Rate: 1}, RateLimit{}, nil, nil)
+	auth := testAuth()
+
+	_, err := AuthorizeCall(auth, rl, "", "10.0.0.1:1234", "block")
+	assert.ErrorIs(t, err, ErrUnauthenticated)
+	// Failed authentications are rate limited by IP.
+	_, err = AuthorizeCall(auth, rl, "wrong", "10.0.0.1:1234", "block")
+	assert.ErrorIs(t, err, ErrRateLimited)
+
+	_, err = AuthorizeCall(auth, rl, "reader", "10.0.0.1:1234", "c")
+	assert.ErrorIs(t, err, ErrRouteNotAllowed)
+	_, err = AuthorizeCall(auth, rl, "reader", "10.0.0.1:1234", "block")
+	require.NoError(t, err)
+	// Authenticated calls are rate limited by API key.
+	wait, err := AuthorizeCall(auth, rl, "reader", "10.0.0.2:1234", "block")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.Positive(t, wait)
+	_, err = AuthorizeCall(auth, rl, "admin", "10.0.0.1:1234", "block")
+	require.NoError(t, err)
+
+	// Without auth, calls are rate limited by IP.
+	_, err = AuthorizeCall(nil, rl, "", "10.0.0.3:1234", "c")
+	require.NoError(t, err)
+	_, err = AuthorizeCall(nil, rl, "", "10.0.0.3:5678", "c")
+	assert.ErrorIs(t, err, ErrRateLimited)
+	_, err = AuthorizeCall(nil, nil, "", "10.0.0.3:5678", "c")
+	require.NoError(t, err)
+}
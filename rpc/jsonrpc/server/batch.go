@@ -0,0 +1,158 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+// ErrCallTimeout is returned for the calls of a batch request that did not
+// complete within the call timeout.
+var ErrCallTimeout = errors.New("call timed out")
+
+// BatchLimits limits the execution of JSON-RPC batch requests.
+type BatchLimits struct {
+	// Maximum number of requests in a batch. 0 means no limit.
+	MaxSize int
+	// Maximum number of calls of a batch executed concurrently. Below 2,
+	// calls are executed sequentially.
+	Parallelism int
+	// Maximum duration of each call of a batch, after which its context is
+	// cancelled and it fails with ErrCallTimeout. The call still counts
+	// towards Parallelism until it returns. 0 means no timeout.
+	CallTimeout time.Duration
+}
+
+// LimitBatches limits the size of JSON-RPC batch requests and the execution
+// of their calls.
+func LimitBatches(limits BatchLimits) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.batch = limits
+	}
+}
+
+func (l BatchLimits) checkSize(n int) error {
+	if l.MaxSize > 0 && n > l.MaxSize {
+		return fmt.Errorf("batch of %d requests exceeds the maximum of %d", n, l.MaxSize)
+	}
+	return nil
+}
+
+// batchCall is a call of a batch request, ready to be executed.
+type batchCall struct {
+	index   int // of the response
+	request types.RPCRequest
+	rpcFunc *RPCFunc
+	args    []reflect.Value // without the context
+}
+
+// execute executes calls, and sets their responses in responses, at the
+// index of each call. Calls of a batch run concurrently, up to Parallelism
+// at a time, except for Ordered ones, which run once the previous calls
+// completed and before the next ones start. Each call is limited to
+// CallTimeout.
+func (l BatchLimits) execute(
+	r *http.Request,
+	calls []*batchCall,
+	batch bool,
+	opts *handlerOptions,
+	responses []types.RPCResponse,
+) {
+	if !batch {
+		for _, call := range calls {
+			responses[call.index] = executeCall(r, call, 0, opts, nil)
+		}
+		return
+	}
+
+	parallelism := l.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	var (
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, parallelism)
+	)
+	for _, call := range calls {
+		call := call
+		if call.rpcFunc.ordered {
+			wg.Wait()
+			responses[call.index] = executeCall(r, call, l.CallTimeout, opts, nil)
+			continue
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// The slot is only freed once the function returns, even after it
+			// timed out, for the functions ignoring the context to count too.
+			responses[call.index] = executeCall(r, call, l.CallTimeout, opts, func() { <-sem })
+		}()
+	}
+	wg.Wait()
+}
+
+// executeCall executes call with the context of r, cancelled after timeout if
+// not 0, or after the timeout of the route. If not nil, done is called once
+// the function of call returns, which may be after executeCall on timeout.
+func executeCall(
+	r *http.Request,
+	call *batchCall,
+	timeout time.Duration,
+	opts *handlerOptions,
+	done func(),
+) types.RPCResponse {
+	if done == nil {
+		done = func() {}
+	}
+	request := call.request
+	ctx, cancelCall := withCallTimeout(&types.Context{JSONReq: &request, HTTPReq: r}, opts.timeouts, request.Method)
+	defer cancelCall()
+
+	if timeout == 0 {
+		defer done()
+		args := append([]reflect.Value{reflect.ValueOf(ctx)}, call.args...)
+		result, err := opts.cache.call(request.Method, call.rpcFunc, args)
+		if err != nil {
+			return types.RPCInternalError(request.ID, err)
+		}
+		return types.NewRPCSuccessResponse(request.ID, result)
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx.Context(), timeout)
+	defer cancel()
+
+	args := append([]reflect.Value{reflect.ValueOf(ctx.WithContext(timeoutCtx))}, call.args...)
+
+	type callResult struct {
+		result interface{}
+		err    error
+	}
+	results := make(chan callResult, 1)
+	go func() {
+		defer done()
+		result, err := opts.cache.call(request.Method, call.rpcFunc, args)
+		results <- callResult{result, err}
+	}()
+
+	// Functions ignoring the context run to completion, but their response is
+	// returned on timeout.
+	select {
+	case res := <-results:
+		if res.err != nil {
+			return types.RPCInternalError(request.ID, res.err)
+		}
+		return types.NewRPCSuccessResponse(request.ID, res.result)
+	case <-timeoutCtx.Done():
+		if timeoutCtx.Err() == context.DeadlineExceeded {
+			return types.RPCInternalError(request.ID, ErrCallTimeout)
+		}
+		return types.RPCInternalError(request.ID, timeoutCtx.Err())
+	}
+}
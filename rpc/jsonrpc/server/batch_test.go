@@ -0,0 +1,153 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+func TestBatchLimits(t *testing.T) {
+	var (
+		running    int32
+		maxRunning int32
+		order      []string
+	)
+	run := func() func() {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&maxRunning)
+			if n <= m || atomic.CompareAndSwapInt32(&maxRunning, m, n) {
+				break
+			}
+		}
+		return func() { atomic.AddInt32(&running, -1) }
+	}
+	sleep := func(ctx *types.Context, d int) (string, error) {
+		defer run()()
+		select {
+		case <-time.After(time.Duration(d) * time.Millisecond):
+			return "slept", nil
+		case <-ctx.Context().Done():
+			return "", ctx.Context().Err()
+		}
+	}
+	funcMap := map[string]*RPCFunc{
+		"sleep": NewRPCFunc(sleep, "d"),
+		"block": NewRPCFunc(func(ctx *types.Context, d int) (string, error) {
+			defer run()()
+			time.Sleep(time.Duration(d) * time.Millisecond)
+			return "blocked", nil
+		}, "d"),
+		"write": NewRPCFunc(func(ctx *types.Context, s string) (string, error) {
+			assert.Zero(t, atomic.LoadInt32(&running))
+			order = append(order, s)
+			return s, nil
+		}, "s", Ordered()),
+	}
+	mux := http.NewServeMux()
+	RegisterRPCFuncs(mux, funcMap, log.TestingLogger(), LimitBatches(BatchLimits{
+		MaxSize:     5,
+		Parallelism: 2,
+		CallTimeout: 500 * time.Millisecond,
+	}))
+
+	post := func(body string) (int, []types.RPCResponse) {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		var responses []types.RPCResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &responses); err != nil {
+			var response types.RPCResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+			responses = []types.RPCResponse{response}
+		}
+		return rec.Code, responses
+	}
+
+	// Calls run concurrently, up to the parallelism, and the slow one times
+	// out without holding up the others.
+	start := time.Now()
+	code, responses := post(`[
+		{"jsonrpc":"2.0","id":1,"method":"sleep","params":{"d":"100"}},
+		{"jsonrpc":"2.0","id":2,"method":"sleep","params":{"d":"100"}},
+		{"jsonrpc":"2.0","id":3,"method":"sleep","params":{"d":"100"}},
+		{"jsonrpc":"2.0","id":4,"method":"sleep","params":{"d":"10000"}}
+	]`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.EqualValues(t, 2, maxRunning)
+	require.Len(t, responses, 4)
+	for i, res := range responses[:3] {
+		assert.Equal(t, types.JSONRPCIntID(i+1), res.ID)
+		assert.Nil(t, res.Error, i)
+	}
+	require.NotNil(t, responses[3].Error)
+	assert.Contains(t, responses[3].Error.Data, ErrCallTimeout.Error())
+
+	// The call that timed out returns once its context is cancelled.
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&running) == 0 }, time.Second, 10*time.Millisecond)
+
+	// The calls that timed out still count towards the parallelism until they
+	// return, even if they ignore their context.
+	atomic.StoreInt32(&maxRunning, 0)
+	start = time.Now()
+	code, responses = post(`[
+		{"jsonrpc":"2.0","id":1,"method":"block","params":{"d":"800"}},
+		{"jsonrpc":"2.0","id":2,"method":"block","params":{"d":"800"}},
+		{"jsonrpc":"2.0","id":3,"method":"sleep","params":{"d":"10"}}
+	]`)
+	require.Equal(t, http.StatusOK, code)
+	assert.GreaterOrEqual(t, time.Since(start), 800*time.Millisecond)
+	assert.EqualValues(t, 2, maxRunning)
+	require.Len(t, responses, 3)
+	for _, res := range responses[:2] {
+		require.NotNil(t, res.Error)
+		assert.Contains(t, res.Error.Data, ErrCallTimeout.Error())
+	}
+	assert.Nil(t, responses[2].Error)
+	require.Eventually(t, func() bool { return atomic.LoadInt32(&running) == 0 }, time.Second, 10*time.Millisecond)
+
+	// Ordered calls run alone, in order.
+	code, responses = post(`[
+		{"jsonrpc":"2.0","id":1,"method":"write","params":{"s":"a"}},
+		{"jsonrpc":"2.0","id":2,"method":"sleep","params":{"d":"10"}},
+		{"jsonrpc":"2.0","id":3,"method":"sleep","params":{"d":"10"}},
+		{"jsonrpc":"2.0","id":4,"method":"write","params":{"s":"b"}},
+		{"jsonrpc":"2.0","id":5,"method":"write","params":{"s":"c"}}
+	]`)
+	require.Equal(t, http.StatusOK, code)
+	require.Len(t, responses, 5)
+	for _, res := range responses {
+		assert.Nil(t, res.Error)
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, order)
+
+	// Batches are limited in size.
+	code, responses = post(`[
+		{"jsonrpc":"2.0","id":1,"method":"write","params":{"s":"a"}},
+		{"jsonrpc":"2.0","id":2,"method":"write","params":{"s":"a"}},
+		{"jsonrpc":"2.0","id":3,"method":"write","params":{"s":"a"}},
+		{"jsonrpc":"2.0","id":4,"method":"write","params":{"s":"a"}},
+		{"jsonrpc":"2.0","id":5,"method":"write","params":{"s":"a"}},
+		{"jsonrpc":"2.0","id":6,"method":"write","params":{"s":"a"}}
+	]`)
+	require.Equal(t, http.StatusBadRequest, code)
+	require.Len(t, responses, 1)
+	require.NotNil(t, responses[0].Error)
+	assert.Contains(t, responses[0].Error.Data, "exceeds the maximum of 5")
+
+	// Single calls are not limited by the call timeout.
+	code, responses = post(`{"jsonrpc":"2.0","id":1,"method":"sleep","params":{"d":"600"}}`)
+	require.Equal(t, http.StatusOK, code)
+	assert.Nil(t, responses[0].Error)
+}
@@ -0,0 +1,33 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+// TimeoutCalls sets the maximum duration of the calls to the given routes,
+// after which their context is cancelled. RPC functions are expected to give
+// up once their context is done, e.g. when searching the indexers.
+func TimeoutCalls(timeouts map[string]time.Duration) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.timeouts = timeouts
+	}
+}
+
+// withCallTimeout returns ctx with a deadline if method has a timeout in
+// timeouts, and a function releasing its resources, to call once the call
+// returns.
+func withCallTimeout(
+	ctx *types.Context,
+	timeouts map[string]time.Duration,
+	method string,
+) (*types.Context, context.CancelFunc) {
+	timeout, ok := timeouts[method]
+	if !ok || timeout <= 0 {
+		return ctx, func() {}
+	}
+	c, cancel := context.WithTimeout(ctx.Context(), timeout)
+	return ctx.WithContext(c), cancel
+}
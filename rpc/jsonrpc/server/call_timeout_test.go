@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+func TestCallTimeouts(t *testing.T) {
+	wait := func(ctx *types.Context) (string, error) {
+		select {
+		case <-time.After(10 * time.Second):
+			return "waited", nil
+		case <-ctx.Context().Done():
+			return "", ctx.Context().Err()
+		}
+	}
+	deadline := func(ctx *types.Context) (bool, error) {
+		_, ok := ctx.Context().Deadline()
+		return ok, nil
+	}
+	funcMap := map[string]*RPCFunc{
+		"wait":     NewRPCFunc(wait, ""),
+		"deadline": NewRPCFunc(deadline, ""),
+	}
+	mux := http.NewServeMux()
+	RegisterRPCFuncs(mux, funcMap, log.TestingLogger(),
+		TimeoutCalls(map[string]time.Duration{"wait": 100 * time.Millisecond}))
+
+	call := func(req *http.Request) types.RPCResponse {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		var response types.RPCResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+		return response
+	}
+
+	for _, req := range []func(method string) *http.Request{
+		func(method string) *http.Request {
+			return httptest.NewRequest(http.MethodGet, "http://localhost/"+method, nil)
+		},
+		func(method string) *http.Request {
+			body := `{"jsonrpc":"2.0","id":1,"method":"` + method + `"}`
+			return httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(body))
+		},
+	} {
+		start := time.Now()
+		res := call(req("wait"))
+		assert.Less(t, time.Since(start), 5*time.Second)
+		require.NotNil(t, res.Error)
+		assert.Contains(t, res.Error.Data, "deadline exceeded")
+
+		// Routes without a timeout have no deadline.
+		res = call(req("deadline"))
+		require.Nil(t, res.Error)
+		assert.Equal(t, "false", string(res.Result))
+	}
+}
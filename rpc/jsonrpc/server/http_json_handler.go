@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"reflect"
 	"sort"
+	"time"
 
 	cmtjson "github.com/tendermint/tendermint/libs/json"
 	"github.com/tendermint/tendermint/libs/log"
@@ -17,8 +18,14 @@ import (
 // HTTP + JSON handler
 
 // jsonrpc calls grab the given method's function info and runs reflect.Call
-func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.HandlerFunc {
+func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger, opts *handlerOptions) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		routes, err := opts.auth.authenticate(r)
+		if err != nil {
+			writeUnauthenticated(w, r, types.RPCUnauthorizedError(nil, err), opts.rateLimiter, logger)
+			return
+		}
+
 		b, err := io.ReadAll(r.Body)
 		if err != nil {
 			res := types.RPCInvalidRequestError(nil,
@@ -55,11 +62,22 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 			requests = []types.RPCRequest{request}
 		}
 
+		if err := opts.batch.checkSize(len(requests)); err != nil {
+			res := types.RPCInvalidRequestError(nil, err)
+			if wErr := WriteRPCResponseHTTPError(w, http.StatusBadRequest, res); wErr != nil {
+				logger.Error("failed to write response", "err", wErr)
+			}
+			return
+		}
+
 		// Set the default response cache to true unless
 		// 1. Any RPC request error.
 		// 2. Any RPC request doesn't allow to be cached.
 		// 3. Any RPC request has the height argument and the value is 0 (the default).
 		cache := true
+		client := rateLimitClient(r, opts.auth)
+		rateLimited, retryAfter := 0, time.Duration(0)
+		var calls []*batchCall
 		for _, request := range requests {
 			request := request
 
@@ -86,8 +104,19 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 				cache = false
 				continue
 			}
-			ctx := &types.Context{JSONReq: &request, HTTPReq: r}
-			args := []reflect.Value{reflect.ValueOf(ctx)}
+			if err := routes.authorize(request.Method); err != nil {
+				responses = append(responses, types.RPCUnauthorizedError(request.ID, err))
+				cache = false
+				continue
+			}
+			if ok, wait := opts.rateLimiter.allow(client, request.Method); !ok {
+				responses = append(responses, types.RPCRateLimitedError(request.ID, ErrRateLimited))
+				rateLimited++
+				retryAfter = wait
+				cache = false
+				continue
+			}
+			var args []reflect.Value
 			if len(request.Params) > 0 {
 				fnArgs, err := jsonParamsToArgs(rpcFunc, request.Params)
 				if err != nil {
@@ -98,20 +127,32 @@ func makeJSONRPCHandler(funcMap map[string]*RPCFunc, logger log.Logger) http.Han
 					cache = false
 					continue
 				}
-				args = append(args, fnArgs...)
+				args = fnArgs
 			}
 
-			if cache && !rpcFunc.cacheableWithArgs(args) {
+			if cache && !rpcFunc.cacheableWithArgs(append([]reflect.Value{{}}, args...)) {
 				cache = false
 			}
 
-			returns := rpcFunc.f.Call(args)
-			result, err := unreflectResult(returns)
-			if err != nil {
-				responses = append(responses, types.RPCInternalError(request.ID, err))
-				continue
+			// The response is set once the call is executed.
+			calls = append(calls, &batchCall{index: len(responses), request: request, rpcFunc: rpcFunc, args: args})
+			responses = append(responses, types.RPCResponse{})
+		}
+		opts.batch.execute(r, calls, len(requests) > 1, opts, responses)
+		for _, call := range calls {
+			if responses[call.index].Error != nil {
+				cache = false
+			}
+		}
+
+		// A single call exceeding the rate limit gets a 429, while the calls of a
+		// batch fail individually.
+		if len(responses) == 1 && rateLimited == 1 {
+			setRetryAfter(w, retryAfter)
+			if wErr := WriteRPCResponseHTTPError(w, http.StatusTooManyRequests, responses[0]); wErr != nil {
+				logger.Error("failed to write response", "err", wErr)
 			}
-			responses = append(responses, types.NewRPCSuccessResponse(request.ID, result))
+			return
 		}
 
 		if len(responses) > 0 {
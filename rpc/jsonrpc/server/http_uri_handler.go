@@ -18,7 +18,12 @@ import (
 var reInt = regexp.MustCompile(`^-?[0-9]+$`)
 
 // convert from a function name to the http handler
-func makeHTTPHandler(rpcFunc *RPCFunc, logger log.Logger) func(http.ResponseWriter, *http.Request) {
+func makeHTTPHandler(
+	funcName string,
+	rpcFunc *RPCFunc,
+	logger log.Logger,
+	opts *handlerOptions,
+) func(http.ResponseWriter, *http.Request) {
 	// Always return -1 as there's no ID here.
 	dummyID := types.JSONRPCIntID(-1) // URIClientRequestID
 
@@ -36,7 +41,29 @@ func makeHTTPHandler(rpcFunc *RPCFunc, logger log.Logger) func(http.ResponseWrit
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger.Debug("HTTP HANDLER", "req", r)
 
-		ctx := &types.Context{HTTPReq: r}
+		routes, err := opts.auth.authenticate(r)
+		if err != nil {
+			writeUnauthenticated(w, r, types.RPCUnauthorizedError(dummyID, err), opts.rateLimiter, logger)
+			return
+		}
+		if err := routes.authorize(funcName); err != nil {
+			if wErr := WriteRPCResponseHTTPError(w, http.StatusForbidden,
+				types.RPCUnauthorizedError(dummyID, err)); wErr != nil {
+				logger.Error("failed to write response", "err", wErr)
+			}
+			return
+		}
+		if ok, wait := opts.rateLimiter.allow(rateLimitClient(r, opts.auth), funcName); !ok {
+			setRetryAfter(w, wait)
+			if wErr := WriteRPCResponseHTTPError(w, http.StatusTooManyRequests,
+				types.RPCRateLimitedError(dummyID, ErrRateLimited)); wErr != nil {
+				logger.Error("failed to write response", "err", wErr)
+			}
+			return
+		}
+
+		ctx, cancel := withCallTimeout(&types.Context{HTTPReq: r}, opts.timeouts, funcName)
+		defer cancel()
 		args := []reflect.Value{reflect.ValueOf(ctx)}
 
 		fnArgs, err := httpParamsToArgs(rpcFunc, r)
@@ -51,10 +78,9 @@ func makeHTTPHandler(rpcFunc *RPCFunc, logger log.Logger) func(http.ResponseWrit
 		}
 		args = append(args, fnArgs...)
 
-		returns := rpcFunc.f.Call(args)
+		result, err := opts.cache.call(funcName, rpcFunc, args)
 
-		logger.Debug("HTTPRestRPC", "method", r.URL.Path, "args", args, "returns", returns)
-		result, err := unreflectResult(returns)
+		logger.Debug("HTTPRestRPC", "method", r.URL.Path, "args", args, "result", result, "err", err)
 		if err != nil {
 			if err := WriteRPCResponseHTTPError(w, http.StatusInternalServerError,
 				types.RPCInternalError(dummyID, err)); err != nil {
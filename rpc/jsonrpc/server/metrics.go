@@ -0,0 +1,79 @@
+package server
+
+import (
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "rpc"
+)
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of calls rejected by the rate limiter, by route and bucket
+	// ("default" or "expensive").
+	RateLimitedCalls metrics.Counter
+
+	// Number of calls accepted by the rate limiter, by route and bucket.
+	AllowedCalls metrics.Counter
+
+	// Number of cacheable calls served from the response cache, by route.
+	CacheHits metrics.Counter
+
+	// Number of cacheable calls not found in the response cache, by route.
+	CacheMisses metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(namespace string, labelsAndValues ...string) *Metrics {
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		RateLimitedCalls: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "rate_limited_calls",
+			Help:      "Number of calls rejected by the rate limiter.",
+		}, append(labels, "route", "bucket")).With(labelsAndValues...),
+
+		AllowedCalls: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "allowed_calls",
+			Help:      "Number of calls accepted by the rate limiter.",
+		}, append(labels, "route", "bucket")).With(labelsAndValues...),
+
+		CacheHits: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "cache_hits",
+			Help:      "Number of cacheable calls served from the response cache.",
+		}, append(labels, "route")).With(labelsAndValues...),
+
+		CacheMisses: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: MetricsSubsystem,
+			Name:      "cache_misses",
+			Help:      "Number of cacheable calls not found in the response cache.",
+		}, append(labels, "route")).With(labelsAndValues...),
+	}
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() *Metrics {
+	return &Metrics{
+		RateLimitedCalls: discard.NewCounter(),
+		AllowedCalls:     discard.NewCounter(),
+		CacheHits:        discard.NewCounter(),
+		CacheMisses:      discard.NewCounter(),
+	}
+}
@@ -0,0 +1,294 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/tendermint/tendermint/libs/log"
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+// Documents describing the routes of a server, generated from its function
+// map, so that they are always in sync with what is registered.
+//
+// Schemas follow the encoding of libs/json: 64-bit integers are strings,
+// byte slices are base64 strings, and registered interfaces are objects with
+// a "type" and a "value".
+
+// schema is a JSON Schema, as used by both OpenRPC and OpenAPI.
+type schema map[string]interface{}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaGenerator generates schemas from Go types. Named struct types are
+// defined once in the components of the document, and referenced elsewhere.
+type schemaGenerator struct {
+	refPrefix  string
+	components map[string]schema
+}
+
+func newSchemaGenerator(refPrefix string) *schemaGenerator {
+	return &schemaGenerator{refPrefix: refPrefix, components: make(map[string]schema)}
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return schema{"type": "string", "format": "date-time"}
+	}
+	if t.Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		// Custom encodings can't be reflected, but those of byte slices, like
+		// HexBytes, are strings.
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return schema{"type": "string"}
+		}
+		return schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return schema{"type": "boolean"}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return schema{"type": "integer"}
+	case reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64:
+		return schema{"type": "string", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return schema{"type": "number"}
+	case reflect.String:
+		return schema{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return schema{"type": "string", "format": "byte"}
+		}
+		return schema{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Map:
+		return schema{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Interface:
+		if t.NumMethod() == 0 {
+			return schema{}
+		}
+		return schema{
+			"type": "object",
+			"properties": schema{
+				"type":  schema{"type": "string"},
+				"value": schema{},
+			},
+		}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := schemaName(t)
+		if _, ok := g.components[name]; !ok {
+			// Reserve the name first, for recursive types.
+			g.components[name] = schema{}
+			g.components[name] = g.structSchema(t)
+		}
+		return schema{"$ref": g.refPrefix + name}
+	default:
+		return schema{}
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) schema {
+	properties := schema{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !unicode.IsUpper(rune(f.Name[0])) {
+			continue
+		}
+		name := f.Name
+		if tag := f.Tag.Get("json"); tag == "-" {
+			continue
+		} else if tagName := strings.Split(tag, ",")[0]; tagName != "" {
+			name = tagName
+		}
+		properties[name] = g.schema(f.Type)
+	}
+	return schema{"type": "object", "properties": properties}
+}
+
+// schemaName returns a unique name for a named type, made of its package path
+// relative to the module and its name, e.g. "rpc.core.types.ResultStatus".
+func schemaName(t reflect.Type) string {
+	path := strings.TrimPrefix(t.PkgPath(), "github.com/tendermint/tendermint/")
+	return strings.ReplaceAll(path, "/", ".") + "." + t.Name()
+}
+
+// sortedRoutes returns the names of the routes of funcMap, sorted.
+func sortedRoutes(funcMap map[string]*RPCFunc) []string {
+	names := make([]string, 0, len(funcMap))
+	for name := range funcMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// OpenRPCDocument returns an OpenRPC document describing the routes of
+// funcMap. See https://spec.open-rpc.org.
+func OpenRPCDocument(funcMap map[string]*RPCFunc, title, version string) interface{} {
+	g := newSchemaGenerator("#/components/schemas/")
+
+	methods := make([]interface{}, 0, len(funcMap))
+	for _, name := range sortedRoutes(funcMap) {
+		rpcFunc := funcMap[name]
+		params := make([]interface{}, len(rpcFunc.argNames))
+		for i, argName := range rpcFunc.argNames {
+			params[i] = map[string]interface{}{
+				"name":   argName,
+				"schema": g.schema(rpcFunc.args[i+1]),
+			}
+		}
+		method := map[string]interface{}{
+			"name":           name,
+			"params":         params,
+			"paramStructure": "either",
+			"result": map[string]interface{}{
+				"name":   name + "_result",
+				"schema": g.schema(rpcFunc.returns[0]),
+			},
+		}
+		if rpcFunc.ws {
+			method["description"] = "Only available over the websocket."
+		}
+		methods = append(methods, method)
+	}
+
+	return map[string]interface{}{
+		"openrpc": "1.2.6",
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": version,
+		},
+		"methods":    methods,
+		"components": map[string]interface{}{"schemas": g.components},
+	}
+}
+
+// OpenAPIDocument returns an OpenAPI document describing the URI (GET) routes
+// of funcMap. See https://spec.openapis.org/oas/v3.0.3.
+func OpenAPIDocument(funcMap map[string]*RPCFunc, title, version string) interface{} {
+	g := newSchemaGenerator("#/components/schemas/")
+
+	paths := make(map[string]interface{}, len(funcMap))
+	for _, name := range sortedRoutes(funcMap) {
+		rpcFunc := funcMap[name]
+		if rpcFunc.ws {
+			continue
+		}
+		params := make([]interface{}, len(rpcFunc.argNames))
+		for i, argName := range rpcFunc.argNames {
+			params[i] = map[string]interface{}{
+				"name":     argName,
+				"in":       "query",
+				"required": false,
+				"schema":   g.schema(rpcFunc.args[i+1]),
+			}
+		}
+		paths["/"+name] = map[string]interface{}{
+			"get": map[string]interface{}{
+				"operationId": name,
+				"parameters":  params,
+				"responses": map[string]interface{}{
+					"200": map[string]interface{}{
+						"description": "JSON-RPC response",
+						"content": map[string]interface{}{
+							"application/json": map[string]interface{}{
+								"schema": schema{
+									"type": "object",
+									"properties": schema{
+										"jsonrpc": schema{"type": "string"},
+										"id":      schema{"type": "integer"},
+										"result":  g.schema(rpcFunc.returns[0]),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   title,
+			"version": version,
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": g.components},
+	}
+}
+
+// DocumentHandler returns a handler serving the document returned by
+// generate as JSON. The document is generated on the first request, once all
+// routes are registered.
+func DocumentHandler(generate func() interface{}, logger log.Logger) http.HandlerFunc {
+	var (
+		once sync.Once
+		js   []byte
+		err  error
+	)
+	return func(w http.ResponseWriter, r *http.Request) {
+		once.Do(func() { js, err = json.MarshalIndent(generate(), "", "  ") })
+		if err != nil {
+			logger.Error("failed to generate document", "err", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if _, err := w.Write(js); err != nil {
+			logger.Error("failed to write document", "err", err)
+		}
+	}
+}
+
+// RegisterDocument registers at path the handler serving the document
+// returned by generate, behind the API key authentication and the rate limits
+// of options, like the routes registered by RegisterRPCFuncs. Any valid API key
+// can read the document, which is rate limited as the route named after path.
+func RegisterDocument(
+	mux *http.ServeMux,
+	path string,
+	generate func() interface{},
+	logger log.Logger,
+	options ...HandlerOption,
+) {
+	opts := &handlerOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+	route := strings.TrimPrefix(path, "/")
+	serveDocument := DocumentHandler(generate, logger)
+
+	mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+		dummyID := types.JSONRPCIntID(-1) // URIClientRequestID
+		if _, err := opts.auth.authenticate(r); err != nil {
+			writeUnauthenticated(w, r, types.RPCUnauthorizedError(dummyID, err), opts.rateLimiter, logger)
+			return
+		}
+		if ok, wait := opts.rateLimiter.allow(rateLimitClient(r, opts.auth), route); !ok {
+			setRetryAfter(w, wait)
+			if wErr := WriteRPCResponseHTTPError(w, http.StatusTooManyRequests,
+				types.RPCRateLimitedError(dummyID, ErrRateLimited)); wErr != nil {
+				logger.Error("failed to write response", "err", wErr)
+			}
+			return
+		}
+		serveDocument(w, r)
+	})
+}
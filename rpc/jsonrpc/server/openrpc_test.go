@@ -0,0 +1,128 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+type openRPCTestResult struct {
+	Height int64  `json:"height"`
+	Hash   []byte `json:"hash"`
+	Note   string `json:"note,omitempty"`
+	hidden bool
+}
+
+func openRPCTestFuncMap() map[string]*RPCFunc {
+	return map[string]*RPCFunc{
+		"block": NewRPCFunc(func(ctx *types.Context, height int64) (*openRPCTestResult, error) {
+			return nil, nil
+		}, "height"),
+		"subscribe": NewWSRPCFunc(func(ctx *types.Context, query string) (*openRPCTestResult, error) {
+			return nil, nil
+		}, "query"),
+	}
+}
+
+func TestOpenRPCDocument(t *testing.T) {
+	doc := OpenRPCDocument(openRPCTestFuncMap(), "test", "1.0")
+	js, err := json.Marshal(doc)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Methods []struct {
+			Name   string `json:"name"`
+			Params []struct {
+				Name   string                 `json:"name"`
+				Schema map[string]interface{} `json:"schema"`
+			} `json:"params"`
+			Result struct {
+				Schema map[string]interface{} `json:"schema"`
+			} `json:"result"`
+		} `json:"methods"`
+		Components struct {
+			Schemas map[string]map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	require.NoError(t, json.Unmarshal(js, &decoded))
+
+	require.Len(t, decoded.Methods, 2)
+	block := decoded.Methods[0]
+	assert.Equal(t, "block", block.Name)
+	require.Len(t, block.Params, 1)
+	assert.Equal(t, "height", block.Params[0].Name)
+	// 64-bit integers are encoded as strings.
+	assert.Equal(t, "string", block.Params[0].Schema["type"])
+
+	name := "rpc.jsonrpc.server.openRPCTestResult"
+	assert.Equal(t, "#/components/schemas/"+name, block.Result.Schema["$ref"])
+	require.Contains(t, decoded.Components.Schemas, name)
+	properties := decoded.Components.Schemas[name]["properties"].(map[string]interface{})
+	assert.Len(t, properties, 3)
+	assert.Contains(t, properties, "hash")
+	assert.Contains(t, properties, "note")
+
+	assert.Equal(t, "subscribe", decoded.Methods[1].Name)
+}
+
+func TestOpenAPIDocument(t *testing.T) {
+	doc := OpenAPIDocument(openRPCTestFuncMap(), "test", "1.0").(map[string]interface{})
+	paths := doc["paths"].(map[string]interface{})
+	assert.Contains(t, paths, "/block")
+	// Websocket routes have no URI.
+	assert.NotContains(t, paths, "/subscribe")
+}
+
+func TestDocumentHandler(t *testing.T) {
+	calls := 0
+	handler := DocumentHandler(func() interface{} {
+		calls++
+		return OpenRPCDocument(openRPCTestFuncMap(), "test", "1.0")
+	}, log.TestingLogger())
+
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		handler(rec, httptest.NewRequest(http.MethodGet, "http://localhost/openrpc.json", nil))
+		require.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+		assert.True(t, json.Valid(rec.Body.Bytes()))
+	}
+	assert.Equal(t, 1, calls)
+}
+
+func TestRegisterDocument(t *testing.T) {
+	mux := http.NewServeMux()
+	RegisterDocument(mux, "/openrpc.json", func() interface{} {
+		return OpenRPCDocument(openRPCTestFuncMap(), "test", "1.0")
+	}, log.TestingLogger(),
+		RequireAPIKey(NewAPIKeyAuth(map[string][]string{"secret": {"status"}})),
+		RateLimitCalls(NewRateLimiter(RateLimit{Rate: 1, Burst: 1}, RateLimit{Rate: 1, Burst: 1}, nil, NopMetrics())),
+	)
+	get := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/openrpc.json", nil)
+		if apiKey != "" {
+			req.Header.Set("X-API-Key", apiKey)
+		}
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The document requires an API key, of any route, and is rate limited, as
+	// the requests failing authentication.
+	assert.Equal(t, http.StatusUnauthorized, get("").Code)
+	assert.Equal(t, http.StatusTooManyRequests, get("wrong").Code)
+	rec := get("secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, json.Valid(rec.Body.Bytes()))
+	rec = get("secret")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get("Retry-After"))
+}
@@ -0,0 +1,247 @@
+package server
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/tendermint/tendermint/libs/log"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+const (
+	// Buckets of the rate limiter, as reported in the metrics.
+	rateLimitBucketDefault   = "default"
+	rateLimitBucketExpensive = "expensive"
+
+	// Idle buckets are pruned at most this often.
+	rateLimitPruneInterval = time.Minute
+
+	// The requests failing authentication are rate limited as this route.
+	unauthenticatedRoute = "unauthenticated"
+)
+
+// ErrRateLimited is returned for calls exceeding the rate limit of the client.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// RateLimit is the rate at which a client can call routes: Rate calls per
+// second on average, and up to Burst calls at once. A zero Rate means no
+// limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiter limits the rate of calls of each client, identified by its API
+// key if any, or else by its IP. Calls to expensive routes are counted in a
+// separate bucket with its own limit.
+type RateLimiter struct {
+	defaultLimit    RateLimit
+	expensiveLimit  RateLimit
+	expensiveRoutes map[string]struct{}
+	metrics         *Metrics
+	now             func() time.Time
+
+	mtx       cmtsync.Mutex
+	buckets   map[rateLimitBucketKey]*tokenBucket
+	lastPrune time.Time
+}
+
+type rateLimitBucketKey struct {
+	client string
+	bucket string
+}
+
+// NewRateLimiter returns a RateLimiter applying defaultLimit to all routes
+// but expensiveRoutes, and expensiveLimit to expensiveRoutes. A Burst lower
+// than 1 defaults to the Rate, rounded up.
+func NewRateLimiter(
+	defaultLimit, expensiveLimit RateLimit,
+	expensiveRoutes []string,
+	metrics *Metrics,
+) *RateLimiter {
+	routes := make(map[string]struct{}, len(expensiveRoutes))
+	for _, route := range expensiveRoutes {
+		routes[route] = struct{}{}
+	}
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
+	for _, limit := range []*RateLimit{&defaultLimit, &expensiveLimit} {
+		if limit.Burst < 1 {
+			limit.Burst = int(math.Ceil(limit.Rate))
+		}
+	}
+	return &RateLimiter{
+		defaultLimit:    defaultLimit,
+		expensiveLimit:  expensiveLimit,
+		expensiveRoutes: routes,
+		metrics:         metrics,
+		now:             time.Now,
+		buckets:         make(map[rateLimitBucketKey]*tokenBucket),
+	}
+}
+
+// allow takes a token from the bucket of client for route. If there is none
+// left, it returns false and how long to wait before retrying. A nil
+// RateLimiter allows all calls.
+func (rl *RateLimiter) allow(client, route string) (bool, time.Duration) {
+	if rl == nil {
+		return true, 0
+	}
+
+	bucket, limit := rateLimitBucketDefault, rl.defaultLimit
+	if _, ok := rl.expensiveRoutes[route]; ok {
+		bucket, limit = rateLimitBucketExpensive, rl.expensiveLimit
+	}
+	if limit.Rate <= 0 {
+		return true, 0
+	}
+
+	rl.mtx.Lock()
+	now := rl.now()
+	if now.Sub(rl.lastPrune) >= rateLimitPruneInterval {
+		rl.prune(now)
+	}
+	key := rateLimitBucketKey{client: client, bucket: bucket}
+	b, ok := rl.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limit.Burst), last: now}
+		rl.buckets[key] = b
+	}
+	allowed, wait := b.take(now, limit)
+	rl.mtx.Unlock()
+
+	if allowed {
+		rl.metrics.AllowedCalls.With("route", route, "bucket", bucket).Add(1)
+	} else {
+		rl.metrics.RateLimitedCalls.With("route", route, "bucket", bucket).Add(1)
+	}
+	return allowed, wait
+}
+
+// prune drops the buckets which are full again, since they are equivalent to
+// new ones. It must be called with the mutex held.
+func (rl *RateLimiter) prune(now time.Time) {
+	for key, b := range rl.buckets {
+		limit := rl.defaultLimit
+		if key.bucket == rateLimitBucketExpensive {
+			limit = rl.expensiveLimit
+		}
+		b.refill(now, limit)
+		if b.tokens >= float64(limit.Burst) {
+			delete(rl.buckets, key)
+		}
+	}
+	rl.lastPrune = now
+}
+
+// tokenBucket holds up to Burst tokens, refilled at Rate tokens per second.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) refill(now time.Time, limit RateLimit) {
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+		b.last = now
+	}
+}
+
+func (b *tokenBucket) take(now time.Time, limit RateLimit) (bool, time.Duration) {
+	b.refill(now, limit)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	wait := time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	return false, wait
+}
+
+// RateLimitCalls makes the handlers reject calls exceeding the limits of limiter.
+func RateLimitCalls(limiter *RateLimiter) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.rateLimiter = limiter
+	}
+}
+
+// rateLimitClient identifies the client of r for rate limiting: by its API
+// key if authentication is enabled, or else by its IP.
+func rateLimitClient(r *http.Request, auth *APIKeyAuth) string {
+	if auth != nil {
+		return "key:" + APIKey(r)
+	}
+	return rateLimitIP(r)
+}
+
+// rateLimitIP identifies the client of r by its IP.
+func rateLimitIP(r *http.Request) string {
+	return rateLimitAddr(r.RemoteAddr)
+}
+
+// rateLimitAddr identifies the client at the network address addr by its IP.
+func rateLimitAddr(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return "ip:" + host
+}
+
+// writeUnauthenticated writes res, the error response to r failing
+// authentication. Such requests are rate limited by IP, as
+// unauthenticatedRoute, for clients not to flood the server or try API keys at
+// no cost: over the limit, ErrRateLimited is returned instead.
+func writeUnauthenticated(
+	w http.ResponseWriter,
+	r *http.Request,
+	res types.RPCResponse,
+	rl *RateLimiter,
+	logger log.Logger,
+) {
+	status := http.StatusUnauthorized
+	if ok, wait := rl.allow(rateLimitIP(r), unauthenticatedRoute); !ok {
+		setRetryAfter(w, wait)
+		status, res = http.StatusTooManyRequests, types.RPCRateLimitedError(res.ID, ErrRateLimited)
+	}
+	if wErr := WriteRPCResponseHTTPError(w, status, res); wErr != nil {
+		logger.Error("failed to write response", "err", wErr)
+	}
+}
+
+// setRetryAfter sets the Retry-After header of a 429 response.
+func setRetryAfter(w http.ResponseWriter, wait time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
+
+// AuthorizeCall applies auth and rl to a call of route with the API key key,
+// from the client at remoteAddr, for the servers other than the HTTP and
+// WebSocket ones (e.g. gRPC) to enforce the same limits. It returns
+// ErrUnauthenticated, an error wrapping ErrRouteNotAllowed, or ErrRateLimited
+// along with how long to wait before retrying. Calls failing authentication
+// are rate limited by IP, as for the HTTP handlers.
+func AuthorizeCall(auth *APIKeyAuth, rl *RateLimiter, key, remoteAddr, route string) (time.Duration, error) {
+	routes, err := auth.routesOf(key)
+	if err != nil {
+		if ok, wait := rl.allow(rateLimitAddr(remoteAddr), unauthenticatedRoute); !ok {
+			return wait, ErrRateLimited
+		}
+		return 0, err
+	}
+	if err := routes.authorize(route); err != nil {
+		return 0, err
+	}
+	client := rateLimitAddr(remoteAddr)
+	if auth != nil {
+		client = "key:" + key
+	}
+	if ok, wait := rl.allow(client, route); !ok {
+		return wait, ErrRateLimited
+	}
+	return 0, nil
+}
@@ -0,0 +1,120 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+func TestRateLimiter(t *testing.T) {
+	rl := NewRateLimiter(RateLimit{Rate: 2, Burst: 3}, RateLimit{Rate: 0.5}, []string{"search"}, nil)
+	now := time.Now()
+	rl.now = func() time.Time { return now }
+
+	// The burst is available at once, per client.
+	for i := 0; i < 3; i++ {
+		ok, _ := rl.allow("a", "status")
+		require.True(t, ok, i)
+	}
+	ok, wait := rl.allow("a", "status")
+	require.False(t, ok)
+	assert.Equal(t, 500*time.Millisecond, wait)
+	ok, _ = rl.allow("b", "status")
+	require.True(t, ok)
+
+	// Expensive routes have their own bucket, with a burst defaulting to 1.
+	ok, _ = rl.allow("a", "search")
+	require.True(t, ok)
+	ok, wait = rl.allow("a", "search")
+	require.False(t, ok)
+	assert.Equal(t, 2*time.Second, wait)
+
+	// Tokens are refilled over time.
+	now = now.Add(500 * time.Millisecond)
+	ok, _ = rl.allow("a", "status")
+	require.True(t, ok)
+	ok, _ = rl.allow("a", "status")
+	require.False(t, ok)
+
+	// Full buckets are pruned.
+	now = now.Add(rateLimitPruneInterval)
+	ok, _ = rl.allow("a", "status")
+	require.True(t, ok)
+	assert.Len(t, rl.buckets, 1)
+
+	// No limit.
+	rl = NewRateLimiter(RateLimit{}, RateLimit{}, nil, nil)
+	for i := 0; i < 100; i++ {
+		ok, _ := rl.allow("a", "status")
+		require.True(t, ok)
+	}
+}
+
+func TestRateLimitHTTP(t *testing.T) {
+	funcMap := map[string]*RPCFunc{
+		"c": NewRPCFunc(func(ctx *types.Context, s string, i int) (string, error) { return "foo", nil }, "s,i"),
+	}
+	mux := http.NewServeMux()
+	rl := NewRateLimiter(RateLimit{Rate: 1}, RateLimit{}, nil, nil)
+	RegisterRPCFuncs(mux, funcMap, log.TestingLogger(), RateLimitCalls(rl))
+
+	get := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost/c?s=\"a\"&i=1", nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+	require.Equal(t, http.StatusOK, get().Code)
+	rec := get()
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	body := `{"jsonrpc":"2.0","id":1,"method":"c","params":{"s":"a","i":"1"}}`
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(body))
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Contains(t, rec.Body.String(), ErrRateLimited.Error())
+}
+
+func TestRateLimitUnauthenticated(t *testing.T) {
+	funcMap := map[string]*RPCFunc{
+		"c": NewRPCFunc(func(ctx *types.Context) (string, error) { return "foo", nil }, ""),
+	}
+	mux := http.NewServeMux()
+	rl := NewRateLimiter(RateLimit{Rate: 1}, RateLimit{}, nil, nil)
+	RegisterRPCFuncs(mux, funcMap, log.TestingLogger(),
+		RequireAPIKey(NewAPIKeyAuth(map[string][]string{"secret": {"c"}})), RateLimitCalls(rl))
+
+	call := func(remoteAddr, apiKey string) *httptest.ResponseRecorder {
+		body := `{"jsonrpc":"2.0","id":1,"method":"c"}`
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(body))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-API-Key", apiKey)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// The requests failing authentication are limited by IP, whatever their key.
+	assert.Equal(t, http.StatusUnauthorized, call("192.0.2.1:1234", "a").Code)
+	rec := call("192.0.2.1:1235", "b")
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "1", rec.Header().Get("Retry-After"))
+	assert.Contains(t, rec.Body.String(), ErrRateLimited.Error())
+	assert.Equal(t, http.StatusUnauthorized, call("192.0.2.2:1234", "a").Code)
+
+	// Valid keys are limited by key, apart.
+	rec = call("192.0.2.1:1234", "secret")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "foo")
+	assert.Equal(t, http.StatusTooManyRequests, call("192.0.2.2:1234", "secret").Code)
+}
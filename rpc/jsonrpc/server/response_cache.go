@@ -0,0 +1,143 @@
+package server
+
+import (
+	"container/list"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	cmtjson "github.com/tendermint/tendermint/libs/json"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+)
+
+// ResponseCache is a thread-safe LRU cache of the results of cacheable calls,
+// bounded by their size in bytes. Results are cached once marshaled to JSON.
+type ResponseCache struct {
+	maxBytes int
+	metrics  *Metrics
+
+	mtx      cmtsync.Mutex
+	bytes    int
+	entryMap map[string]*list.Element
+	list     *list.List
+}
+
+type responseCacheEntry struct {
+	key    string
+	result json.RawMessage
+}
+
+// NewResponseCache returns a ResponseCache holding up to maxBytes of keys and
+// results.
+func NewResponseCache(maxBytes int, metrics *Metrics) *ResponseCache {
+	if metrics == nil {
+		metrics = NopMetrics()
+	}
+	return &ResponseCache{
+		maxBytes: maxBytes,
+		metrics:  metrics,
+		entryMap: make(map[string]*list.Element),
+		list:     list.New(),
+	}
+}
+
+// finalResult is implemented by the results of cacheable calls which may not
+// be final yet, e.g. the commit of the latest block, and are not cached until
+// they are.
+type finalResult interface {
+	Final() bool
+}
+
+// CacheResponses makes the handlers serve the results of cacheable calls
+// from cache.
+func CacheResponses(cache *ResponseCache) HandlerOption {
+	return func(opts *handlerOptions) {
+		opts.cache = cache
+	}
+}
+
+// call calls rpcFunc with args and returns its result. If the call is
+// cacheable and its result immutable, or pinned by an explicit height in its
+// arguments, the result is served from the cache if present, or else added to
+// it once final. A nil ResponseCache never caches results.
+func (c *ResponseCache) call(method string, rpcFunc *RPCFunc, args []reflect.Value) (interface{}, error) {
+	if c == nil || !rpcFunc.pinnedWithArgs(args) {
+		return unreflectResult(rpcFunc.f.Call(args))
+	}
+
+	key, err := responseCacheKey(method, args)
+	if err != nil {
+		return unreflectResult(rpcFunc.f.Call(args))
+	}
+	if result, ok := c.get(key); ok {
+		c.metrics.CacheHits.With("route", method).Add(1)
+		return result, nil
+	}
+	c.metrics.CacheMisses.With("route", method).Add(1)
+
+	result, err := unreflectResult(rpcFunc.f.Call(args))
+	if err != nil {
+		return nil, err
+	}
+	// The result is a pointer to the value returned.
+	if r, ok := reflect.ValueOf(result).Elem().Interface().(finalResult); ok && !r.Final() {
+		return result, nil
+	}
+	js, err := cmtjson.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	c.put(key, js)
+	return json.RawMessage(js), nil
+}
+
+func (c *ResponseCache) get(key string) (json.RawMessage, bool) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	e, ok := c.entryMap[key]
+	if !ok {
+		return nil, false
+	}
+	c.list.MoveToBack(e)
+	return e.Value.(*responseCacheEntry).result, true
+}
+
+func (c *ResponseCache) put(key string, result json.RawMessage) {
+	size := len(key) + len(result)
+	if size > c.maxBytes {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if _, ok := c.entryMap[key]; ok {
+		return
+	}
+	for c.bytes+size > c.maxBytes {
+		front := c.list.Front()
+		entry := front.Value.(*responseCacheEntry)
+		c.bytes -= len(entry.key) + len(entry.result)
+		delete(c.entryMap, entry.key)
+		c.list.Remove(front)
+	}
+	c.entryMap[key] = c.list.PushBack(&responseCacheEntry{key: key, result: result})
+	c.bytes += size
+}
+
+// responseCacheKey returns the key of a call to method with args, made of the
+// method and the JSON encoding of the arguments but the context.
+func responseCacheKey(method string, args []reflect.Value) (string, error) {
+	var sb strings.Builder
+	sb.WriteString(method)
+	for _, arg := range args[1:] {
+		js, err := cmtjson.Marshal(arg.Interface())
+		if err != nil {
+			return "", err
+		}
+		sb.WriteByte(' ')
+		sb.Write(js)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,113 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	types "github.com/tendermint/tendermint/rpc/jsonrpc/types"
+)
+
+func TestResponseCacheEviction(t *testing.T) {
+	cache := NewResponseCache(20, nil)
+
+	cache.put("a", []byte("1234567"))
+	cache.put("b", []byte("1234567"))
+	_, ok := cache.get("a")
+	require.True(t, ok)
+
+	// "b" is the least recently used entry.
+	cache.put("c", []byte("1234567"))
+	_, ok = cache.get("b")
+	assert.False(t, ok)
+	_, ok = cache.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 16, cache.bytes)
+
+	// Too large to be cached.
+	cache.put("d", []byte("12345678901234567890"))
+	_, ok = cache.get("d")
+	assert.False(t, ok)
+	assert.Equal(t, 2, cache.list.Len())
+}
+
+func TestResponseCacheHTTP(t *testing.T) {
+	calls := 0
+	funcMap := map[string]*RPCFunc{
+		"block": NewRPCFunc(func(ctx *types.Context, h int) (string, error) {
+			calls++
+			return "block", nil
+		}, "height", Cacheable("height")),
+		"abci_info": NewRPCFunc(func(ctx *types.Context) (string, error) {
+			calls++
+			return "info", nil
+		}, "", Cacheable()),
+		"genesis_chunked": NewRPCFunc(func(ctx *types.Context, chunk int) (string, error) {
+			calls++
+			return "chunk", nil
+		}, "chunk", Cacheable(), Immutable()),
+		"commit": NewRPCFunc(func(ctx *types.Context, h int) (*testCommit, error) {
+			calls++
+			return &testCommit{Canonical: h < 3}, nil
+		}, "height", Cacheable("height")),
+	}
+	mux := http.NewServeMux()
+	RegisterRPCFuncs(mux, funcMap, log.TestingLogger(), CacheResponses(NewResponseCache(1<<20, nil)))
+
+	get := func(path string) string {
+		req := httptest.NewRequest(http.MethodGet, "http://localhost"+path, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+	post := func(body string) string {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost/", strings.NewReader(body))
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return rec.Body.String()
+	}
+
+	first := get("/block?height=1")
+	assert.Equal(t, first, get("/block?height=1"))
+	assert.Contains(t, post(`{"jsonrpc":"2.0","id":-1,"method":"block","params":{"height":"1"}}`), `"block"`)
+	assert.Equal(t, 1, calls)
+
+	get("/block?height=2")
+	assert.Equal(t, 2, calls)
+
+	// The latest height is not cacheable.
+	get("/block")
+	get("/block")
+	assert.Equal(t, 4, calls)
+
+	// Nor are the calls without a height.
+	get("/abci_info")
+	get("/abci_info")
+	assert.Equal(t, 6, calls)
+
+	// Unless immutable.
+	get("/genesis_chunked?chunk=0")
+	get("/genesis_chunked?chunk=0")
+	assert.Equal(t, 7, calls)
+
+	// The results which are not final are not cached.
+	get("/commit?height=3")
+	get("/commit?height=3")
+	assert.Equal(t, 9, calls)
+	get("/commit?height=2")
+	get("/commit?height=2")
+	assert.Equal(t, 10, calls)
+}
+
+type testCommit struct {
+	Canonical bool `json:"canonical"`
+}
+
+func (c *testCommit) Final() bool { return c.Canonical }
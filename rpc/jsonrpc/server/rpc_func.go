@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/tendermint/tendermint/libs/log"
 )
@@ -13,14 +14,36 @@ import (
 // general jsonrpc and websocket handlers for all functions. "result" is the
 // interface on which the result objects are registered, and is popualted with
 // every RPCResponse
-func RegisterRPCFuncs(mux *http.ServeMux, funcMap map[string]*RPCFunc, logger log.Logger) {
+func RegisterRPCFuncs(
+	mux *http.ServeMux,
+	funcMap map[string]*RPCFunc,
+	logger log.Logger,
+	options ...HandlerOption,
+) {
+	opts := &handlerOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
 	// HTTP endpoints
 	for funcName, rpcFunc := range funcMap {
-		mux.HandleFunc("/"+funcName, makeHTTPHandler(rpcFunc, logger))
+		mux.HandleFunc("/"+funcName, makeHTTPHandler(funcName, rpcFunc, logger, opts))
 	}
 
 	// JSONRPC endpoints
-	mux.HandleFunc("/", handleInvalidJSONRPCPaths(makeJSONRPCHandler(funcMap, logger)))
+	mux.HandleFunc("/", handleInvalidJSONRPCPaths(makeJSONRPCHandler(funcMap, logger, opts)))
+}
+
+// HandlerOption sets an optional parameter of the handlers registered by
+// RegisterRPCFuncs.
+type HandlerOption func(*handlerOptions)
+
+type handlerOptions struct {
+	auth        *APIKeyAuth
+	rateLimiter *RateLimiter
+	cache       *ResponseCache
+	batch       BatchLimits
+	timeouts    map[string]time.Duration
 }
 
 type Option func(*RPCFunc)
@@ -41,6 +64,15 @@ func Cacheable(noCacheDefArgs ...string) Option {
 	}
 }
 
+// Immutable marks the results of a cacheable RPC function as never changing,
+// e.g. those of the genesis, for a ResponseCache to cache them without
+// explicit arguments.
+func Immutable() Option {
+	return func(r *RPCFunc) {
+		r.immutable = true
+	}
+}
+
 // Ws enables WebSocket communication.
 func Ws() Option {
 	return func(r *RPCFunc) {
@@ -48,6 +80,15 @@ func Ws() Option {
 	}
 }
 
+// Ordered makes the calls to RPC functions to which it is applied execute in
+// order with the other calls of a batch request, rather than concurrently
+// with them, e.g. for functions with side effects.
+func Ordered() Option {
+	return func(r *RPCFunc) {
+		r.ordered = true
+	}
+}
+
 // RPCFunc contains the introspected type information for a function
 type RPCFunc struct {
 	f              reflect.Value          // underlying rpc function
@@ -55,7 +96,9 @@ type RPCFunc struct {
 	returns        []reflect.Type         // type of each return arg
 	argNames       []string               // name of each argument
 	cacheable      bool                   // enable cache control
+	immutable      bool                   // the results never change
 	ws             bool                   // enable websocket communication
+	ordered        bool                   // execute in order within batches
 	noCacheDefArgs map[string]interface{} // a lookup table of args that, if not supplied or are set to default values, cause us to not cache
 }
 
@@ -95,6 +138,14 @@ func (f *RPCFunc) cacheableWithArgs(args []reflect.Value) bool {
 	return true
 }
 
+// pinnedWithArgs returns whether or not a call to this function is cacheable
+// and pins its result, being immutable or given explicit arguments, e.g. a
+// height, given the specified arguments. The results of the other cacheable
+// calls, e.g. abci_info or blockchain, change with the latest height.
+func (f *RPCFunc) pinnedWithArgs(args []reflect.Value) bool {
+	return (f.immutable || len(f.noCacheDefArgs) > 0) && f.cacheableWithArgs(args)
+}
+
 func newRPCFunc(f interface{}, args string, options ...Option) *RPCFunc {
 	var argNames []string
 	if args != "" {
@@ -34,6 +34,9 @@ type WebsocketManager struct {
 
 	funcMap       map[string]*RPCFunc
 	logger        log.Logger
+	auth          *APIKeyAuth
+	rateLimiter   *RateLimiter
+	timeouts      map[string]time.Duration
 	wsConnOptions []func(*wsConnection)
 }
 
@@ -69,9 +72,33 @@ func (wm *WebsocketManager) SetLogger(l log.Logger) {
 	wm.logger = l
 }
 
+// SetAPIKeyAuth makes the manager reject connections without a valid API key,
+// and calls to routes not allowed for the key.
+func (wm *WebsocketManager) SetAPIKeyAuth(auth *APIKeyAuth) {
+	wm.auth = auth
+}
+
+// SetRateLimiter makes the manager reject calls exceeding the limits of
+// limiter.
+func (wm *WebsocketManager) SetRateLimiter(limiter *RateLimiter) {
+	wm.rateLimiter = limiter
+}
+
+// SetCallTimeouts sets the maximum duration of the calls to the given
+// routes, after which their context is cancelled.
+func (wm *WebsocketManager) SetCallTimeouts(timeouts map[string]time.Duration) {
+	wm.timeouts = timeouts
+}
+
 // WebsocketHandler upgrades the request/response (via http.Hijack) and starts
 // the wsConnection.
 func (wm *WebsocketManager) WebsocketHandler(w http.ResponseWriter, r *http.Request) {
+	routes, err := wm.auth.authenticate(r)
+	if err != nil {
+		writeUnauthenticated(w, r, types.RPCUnauthorizedError(nil, err), wm.rateLimiter, wm.logger)
+		return
+	}
+
 	wsConn, err := wm.Upgrade(w, r, nil)
 	if err != nil {
 		// TODO - return http error
@@ -86,6 +113,10 @@ func (wm *WebsocketManager) WebsocketHandler(w http.ResponseWriter, r *http.Requ
 
 	// register connection
 	con := newWSConnection(wsConn, wm.funcMap, wm.wsConnOptions...)
+	con.routes = routes
+	con.rateLimiter = wm.rateLimiter
+	con.timeouts = wm.timeouts
+	con.rateLimitClient = rateLimitClient(r, wm.auth)
 	con.SetLogger(wm.logger.With("remote", wsConn.RemoteAddr()))
 	wm.logger.Info("New websocket connection", "remote", con.remoteAddr)
 	err = con.Start() // BLOCKING
@@ -118,6 +149,14 @@ type wsConnection struct {
 
 	funcMap map[string]*RPCFunc
 
+	// routes the connection is allowed to call, or nil for all routes
+	routes apiKeyRoutes
+
+	// rate limiter of the calls, and client of the connection for it
+	rateLimiter     *RateLimiter
+	timeouts        map[string]time.Duration
+	rateLimitClient string
+
 	// write channel capacity
 	writeChanCapacity int
 
@@ -362,11 +401,22 @@ func (wsc *wsConnection) readRoutine() {
 				}
 				continue
 			}
+			if err := wsc.routes.authorize(request.Method); err != nil {
+				if err := wsc.WriteRPCResponse(writeCtx, types.RPCUnauthorizedError(request.ID, err)); err != nil {
+					wsc.Logger.Error("Error writing RPC response", "err", err)
+				}
+				continue
+			}
+			if ok, _ := wsc.rateLimiter.allow(wsc.rateLimitClient, request.Method); !ok {
+				if err := wsc.WriteRPCResponse(writeCtx, types.RPCRateLimitedError(request.ID, ErrRateLimited)); err != nil {
+					wsc.Logger.Error("Error writing RPC response", "err", err)
+				}
+				continue
+			}
 
-			ctx := &types.Context{JSONReq: &request, WSConn: wsc}
-			args := []reflect.Value{reflect.ValueOf(ctx)}
+			var fnArgs []reflect.Value
 			if len(request.Params) > 0 {
-				fnArgs, err := jsonParamsToArgs(rpcFunc, request.Params)
+				fnArgs, err = jsonParamsToArgs(rpcFunc, request.Params)
 				if err != nil {
 					if err := wsc.WriteRPCResponse(writeCtx,
 						types.RPCInternalError(request.ID, fmt.Errorf("error converting json params to arguments: %w", err)),
@@ -375,10 +425,12 @@ func (wsc *wsConnection) readRoutine() {
 					}
 					continue
 				}
-				args = append(args, fnArgs...)
 			}
 
+			ctx, cancel := withCallTimeout(&types.Context{JSONReq: &request, WSConn: wsc}, wsc.timeouts, request.Method)
+			args := append([]reflect.Value{reflect.ValueOf(ctx)}, fnArgs...)
 			returns := rpcFunc.f.Call(args)
+			cancel()
 
 			// TODO: Need to encode args/returns to string if we want to log them
 			wsc.Logger.Info("WSJSONRPC", "method", request.Method)
@@ -246,6 +246,14 @@ func RPCServerError(id jsonrpcid, err error) RPCResponse {
 	return NewRPCErrorResponse(id, -32000, "Server error", err.Error())
 }
 
+func RPCUnauthorizedError(id jsonrpcid, err error) RPCResponse {
+	return NewRPCErrorResponse(id, -32001, "Unauthorized", err.Error())
+}
+
+func RPCRateLimitedError(id jsonrpcid, err error) RPCResponse {
+	return NewRPCErrorResponse(id, -32002, "Too many requests", err.Error())
+}
+
 //----------------------------------------
 
 // WSRPCConnection represents a websocket connection.
@@ -273,6 +281,18 @@ type Context struct {
 	WSConn WSRPCConnection
 	// http request
 	HTTPReq *http.Request
+
+	// context of the call, if derived from that of the request, e.g. with a
+	// deadline
+	ctx context.Context
+}
+
+// WithContext returns a copy of ctx whose Context is c, which is usually
+// derived from ctx.Context(), e.g. to set a deadline on the call.
+func (ctx *Context) WithContext(c context.Context) *Context {
+	ctx2 := *ctx
+	ctx2.ctx = c
+	return &ctx2
 }
 
 // RemoteAddr returns the remote address (usually a string "IP:port").
@@ -293,7 +313,7 @@ func (ctx *Context) RemoteAddr() string {
 	return ""
 }
 
-// Context returns the request's context.
+// Context returns the request's context, or the one set with WithContext.
 // The returned context is always non-nil; it defaults to the background context.
 // HTTP:
 //
@@ -304,7 +324,9 @@ func (ctx *Context) RemoteAddr() string {
 //
 //	The context is canceled when the client's connections closes.
 func (ctx *Context) Context() context.Context {
-	if ctx.HTTPReq != nil {
+	if ctx.ctx != nil {
+		return ctx.ctx
+	} else if ctx.HTTPReq != nil {
 		return ctx.HTTPReq.Context()
 	} else if ctx.WSConn != nil {
 		return ctx.WSConn.Context()
@@ -19,7 +19,11 @@ import (
 	"github.com/tendermint/tendermint/types"
 )
 
-var _ indexer.BlockIndexer = (*BlockerIndexer)(nil)
+var (
+	_ indexer.BlockIndexer = (*BlockerIndexer)(nil)
+	_ indexer.Pruner       = (*BlockerIndexer)(nil)
+	_ indexer.Rewinder     = (*BlockerIndexer)(nil)
+)
 
 // BlockerIndexer implements a block indexer, indexing BeginBlock and EndBlock
 // events with an underlying KV store. Block events are indexed by their height,
@@ -30,12 +34,29 @@ type BlockerIndexer struct {
 	// Add unique event identifier to use when querying
 	// Matching will be done both on height AND eventSeq
 	eventSeq int64
+
+	// Selects the event attributes indexed
+	filter *indexer.EventFilter
+}
+
+// Option sets an optional parameter on the BlockerIndexer.
+type Option func(*BlockerIndexer)
+
+// WithEventFilter restricts the event attributes indexed to the ones allowed
+// by filter. The attributes are still only indexed if the application set
+// their index flag.
+func WithEventFilter(filter *indexer.EventFilter) Option {
+	return func(idx *BlockerIndexer) { idx.filter = filter }
 }
 
-func New(store dbm.DB) *BlockerIndexer {
-	return &BlockerIndexer{
+func New(store dbm.DB, options ...Option) *BlockerIndexer {
+	idx := &BlockerIndexer{
 		store: store,
 	}
+	for _, option := range options {
+		option(idx)
+	}
+	return idx
 }
 
 // Has returns true if the given height has been indexed. An error is returned
@@ -55,6 +76,7 @@ func (idx *BlockerIndexer) Has(height int64) (bool, error) {
 // primary key: encode(block.height | height) => encode(height)
 // BeginBlock events: encode(eventType.eventAttr|eventValue|height|begin_block) => encode(height)
 // EndBlock events: encode(eventType.eventAttr|eventValue|height|end_block) => encode(height)
+// event keys: encode(height_events | height) => the keys of the events above
 func (idx *BlockerIndexer) Index(bh types.EventDataNewBlockHeader) error {
 	batch := idx.store.NewBatch()
 	defer batch.Close()
@@ -71,23 +93,168 @@ func (idx *BlockerIndexer) Index(bh types.EventDataNewBlockHeader) error {
 	}
 
 	// 2. index BeginBlock events
-	if err := idx.indexEvents(batch, bh.ResultBeginBlock.Events, "begin_block", height); err != nil {
+	beginKeys, err := idx.indexEvents(batch, bh.ResultBeginBlock.Events, "begin_block", height)
+	if err != nil {
 		return fmt.Errorf("failed to index BeginBlock events: %w", err)
 	}
 
 	// 3. index EndBlock events
-	if err := idx.indexEvents(batch, bh.ResultEndBlock.Events, "end_block", height); err != nil {
+	endKeys, err := idx.indexEvents(batch, bh.ResultEndBlock.Events, "end_block", height)
+	if err != nil {
 		return fmt.Errorf("failed to index EndBlock events: %w", err)
 	}
 
+	// 4. record the event keys, for the height to be removed without scanning
+	// the events of all the heights
+	key, err = eventKeysKey(height)
+	if err != nil {
+		return fmt.Errorf("failed to create block event keys key: %w", err)
+	}
+	if err := batch.Set(key, encodeKeys(append(beginKeys, endKeys...))); err != nil {
+		return err
+	}
+
 	return batch.WriteSync()
 }
 
+// Prune removes the heights indexed below retainHeight, along with their
+// events, and returns their number.
+func (idx *BlockerIndexer) Prune(retainHeight int64) (uint64, error) {
+	end, err := heightKey(retainHeight)
+	if err != nil {
+		return 0, err
+	}
+	return idx.removeHeights(nil, end)
+}
+
+// Rewind removes the blocks indexed above height, along with their events, and
+// returns their number.
+func (idx *BlockerIndexer) Rewind(height int64) (uint64, error) {
+	start, err := heightKey(height + 1)
+	if err != nil {
+		return 0, err
+	}
+	return idx.removeHeights(start, nil)
+}
+
+// removeHeights removes the height keys from start to end, nil meaning the
+// first or last of them, along with the events of their heights, and returns
+// the number of heights removed. The events are found from the keys recorded
+// along with each height, except for the heights indexed before the keys were
+// recorded, whose events are found by scanning all of them.
+func (idx *BlockerIndexer) removeHeights(start, end []byte) (uint64, error) {
+	heightPrefix, err := orderedcode.Append(nil, types.BlockHeightKey)
+	if err != nil {
+		return 0, err
+	}
+	if start == nil {
+		start = heightPrefix
+	}
+
+	batch := idx.store.NewBatch()
+	defer batch.Close()
+
+	it, err := idx.store.Iterator(start, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create iterator: %w", err)
+	}
+	var (
+		removed  uint64
+		unlisted = make(map[int64]struct{})
+	)
+	for ; it.Valid() && bytes.HasPrefix(it.Key(), heightPrefix); it.Next() {
+		height := int64FromBytes(it.Value())
+		if err := batch.Delete(it.Key()); err != nil {
+			it.Close()
+			return 0, err
+		}
+		removed++
+
+		listed, err := idx.removeEventKeys(batch, height)
+		if err != nil {
+			it.Close()
+			return 0, err
+		}
+		if !listed {
+			unlisted[height] = struct{}{}
+		}
+	}
+	if err := it.Error(); err != nil {
+		it.Close()
+		return 0, err
+	}
+	it.Close()
+
+	if len(unlisted) > 0 {
+		if err := idx.removeUnlistedEvents(batch, unlisted); err != nil {
+			return 0, err
+		}
+	}
+	return removed, batch.WriteSync()
+}
+
+// removeEventKeys adds to batch the removal of the events recorded at height,
+// and returns false if their keys were not recorded.
+func (idx *BlockerIndexer) removeEventKeys(batch dbm.Batch, height int64) (bool, error) {
+	key, err := eventKeysKey(height)
+	if err != nil {
+		return false, err
+	}
+	bz, err := idx.store.Get(key)
+	if err != nil || bz == nil {
+		return false, err
+	}
+	keys, err := decodeKeys(bz)
+	if err != nil {
+		return false, fmt.Errorf("invalid event keys at height %d: %w", height, err)
+	}
+	for _, k := range append(keys, key) {
+		if err := batch.Delete(k); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// removeUnlistedEvents adds to batch the removal of the events of heights,
+// found by scanning the events of all the heights.
+func (idx *BlockerIndexer) removeUnlistedEvents(batch dbm.Batch, heights map[int64]struct{}) error {
+	heightPrefix, err := orderedcode.Append(nil, types.BlockHeightKey)
+	if err != nil {
+		return err
+	}
+	it, err := idx.store.Iterator(nil, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create iterator: %w", err)
+	}
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		if bytes.HasPrefix(it.Key(), heightPrefix) {
+			continue
+		}
+		keyHeight, err := parseHeightFromEventKey(it.Key())
+		if err != nil {
+			continue
+		}
+		if _, ok := heights[keyHeight]; !ok {
+			continue
+		}
+		if err := batch.Delete(it.Key()); err != nil {
+			return err
+		}
+	}
+	return it.Error()
+}
+
 // Search performs a query for block heights that match a given BeginBlock
 // and Endblock event search criteria. The given query can match against zero,
 // one or more block heights. In the case of height queries, i.e. block.height=H,
 // if the height is indexed, that height alone will be returned. An error and
 // nil slice is returned. Otherwise, a non-nil slice and nil error is returned.
+//
+// The query is rewritten as alternatives of conditions, each searched
+// separately before their results are merged.
 func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64, error) {
 	results := make([]int64, 0)
 	select {
@@ -97,10 +264,119 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 	default:
 	}
 
-	conditions, err := q.Conditions()
+	conjunctions, err := q.Disjunction()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse query conditions: %w", err)
 	}
+	if len(conjunctions) == 1 {
+		return idx.searchConjunction(ctx, conjunctions[0])
+	}
+
+	resultMap := make(map[int64]struct{})
+	for _, conditions := range conjunctions {
+		heights, err := idx.searchConjunction(ctx, conditions)
+		if err != nil {
+			return nil, err
+		}
+		for _, h := range heights {
+			if _, ok := resultMap[h]; !ok {
+				resultMap[h] = struct{}{}
+				results = append(results, h)
+			}
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i] < results[j] })
+
+	return results, nil
+}
+
+// searchConjunction returns the heights matching all the given conditions.
+// The heights matching negated conditions are removed from the ones matching
+// the others, or from all the indexed heights if all the conditions are
+// negated.
+func (idx *BlockerIndexer) searchConjunction(ctx context.Context, conditions []query.Condition) ([]int64, error) {
+	var positive, negated []query.Condition
+	for _, c := range conditions {
+		if c.Negated {
+			c.Negated = false
+			negated = append(negated, c)
+		} else {
+			positive = append(positive, c)
+		}
+	}
+
+	var (
+		results []int64
+		err     error
+	)
+	if len(positive) == 0 || (len(positive) == 1 && positive[0].CompositeKey == types.MatchEventKey) {
+		results, err = idx.heights(ctx)
+	} else {
+		results, err = idx.searchConditions(ctx, positive)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, c := range negated {
+		if len(results) == 0 {
+			break
+		}
+		excluded, err := idx.searchConditions(ctx, []query.Condition{c})
+		if err != nil {
+			return nil, err
+		}
+		excludedMap := make(map[int64]struct{}, len(excluded))
+		for _, h := range excluded {
+			excludedMap[h] = struct{}{}
+		}
+		filtered := results[:0]
+		for _, h := range results {
+			if _, ok := excludedMap[h]; !ok {
+				filtered = append(filtered, h)
+			}
+		}
+		results = filtered
+	}
+
+	return results, nil
+}
+
+// heights returns all the indexed heights, in ascending order.
+func (idx *BlockerIndexer) heights(ctx context.Context) ([]int64, error) {
+	prefix, err := orderedcode.Append(nil, types.BlockHeightKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prefix key: %w", err)
+	}
+
+	it, err := dbm.IteratePrefix(idx.store, prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prefix iterator: %w", err)
+	}
+	defer it.Close()
+
+	results := make([]int64, 0)
+	for ; it.Valid(); it.Next() {
+		results = append(results, int64FromBytes(it.Value()))
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return results, it.Error()
+}
+
+// searchConditions returns the heights matching all the given conditions,
+// none of which is negated, in ascending order.
+func (idx *BlockerIndexer) searchConditions(ctx context.Context, conditions []query.Condition) ([]int64, error) {
+	results := make([]int64, 0)
+
 	// conditions to skip because they're handled before "everything else"
 	skipIndexes := make([]int, 0)
 
@@ -252,11 +528,8 @@ func (idx *BlockerIndexer) Search(ctx context.Context, q *query.Query) ([]int64,
 			results = append(results, h)
 		}
 
-		select {
-		case <-ctx.Done():
+		if ctx.Err() != nil {
 			break
-
-		default:
 		}
 	}
 
@@ -330,11 +603,8 @@ LOOP:
 			}
 		}
 
-		select {
-		case <-ctx.Done():
+		if ctx.Err() != nil {
 			break
-
-		default:
 		}
 	}
 
@@ -474,11 +744,8 @@ func (idx *BlockerIndexer) match(
 
 			idx.setTmpHeights(tmpHeights, it, matchEvents)
 
-			select {
-			case <-ctx.Done():
+			if ctx.Err() != nil {
 				break
-
-			default:
 			}
 		}
 
@@ -512,17 +779,82 @@ func (idx *BlockerIndexer) match(
 				idx.setTmpHeights(tmpHeights, it, matchEvents)
 			}
 
-			select {
-			case <-ctx.Done():
+			if ctx.Err() != nil {
 				break
+			}
+		}
+		if err := it.Error(); err != nil {
+			return nil, err
+		}
 
-			default:
+	case c.Op == query.OpStartsWith:
+		// The encoded values starting with the operand start with its
+		// encoding, without the terminator of the string.
+		prefix, err := orderedcode.Append(nil, c.CompositeKey, c.Operand.(string))
+		if err != nil {
+			return nil, err
+		}
+		prefix = prefix[:len(prefix)-2]
+
+		it, err := dbm.IteratePrefix(idx.store, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create prefix iterator: %w", err)
+		}
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			eventValue, err := parseValueFromEventKey(it.Key())
+			if err != nil || !strings.HasPrefix(eventValue, c.Operand.(string)) {
+				continue
+			}
+
+			keyHeight, err := parseHeightFromEventKey(it.Key())
+			if err != nil || !checkHeightConditions(heightInfo, keyHeight) {
+				continue
+			}
+			idx.setTmpHeights(tmpHeights, it, matchEvents)
+
+			if ctx.Err() != nil {
+				break
 			}
 		}
 		if err := it.Error(); err != nil {
 			return nil, err
 		}
 
+	case c.Op == query.OpIn && c.CompositeKey == types.BlockHeightKey:
+		for _, operand := range c.Operand.([]interface{}) {
+			height, ok := operand.(*big.Int)
+			if !ok || !height.IsInt64() {
+				continue
+			}
+			ok, err := idx.Has(height.Int64())
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				heightBz := int64ToBytes(height.Int64())
+				tmpHeights[string(heightBz)] = heightBz
+			}
+		}
+
+	case c.Op == query.OpIn:
+		// match each operand as an equality condition
+		for _, operand := range c.Operand.([]interface{}) {
+			eq := query.Condition{CompositeKey: c.CompositeKey, Op: query.OpEqual, Operand: operand}
+			startKey, err := orderedcode.Append(nil, eq.CompositeKey, fmt.Sprintf("%v", eq.Operand))
+			if err != nil {
+				return nil, err
+			}
+			matches, err := idx.match(ctx, eq, startKey, nil, true, matchEvents, heightInfo)
+			if err != nil {
+				return nil, err
+			}
+			for k, v := range matches {
+				tmpHeights[k] = v
+			}
+		}
+
 	default:
 		return nil, errors.New("other operators should be handled already")
 	}
@@ -557,8 +889,10 @@ func (idx *BlockerIndexer) match(
 	return filteredHeights, nil
 }
 
-func (idx *BlockerIndexer) indexEvents(batch dbm.Batch, events []abci.Event, typ string, height int64) error {
+// indexEvents adds the events to batch, and returns their keys.
+func (idx *BlockerIndexer) indexEvents(batch dbm.Batch, events []abci.Event, typ string, height int64) ([][]byte, error) {
 	heightBz := int64ToBytes(height)
+	var keys [][]byte
 
 	for _, event := range events {
 		idx.eventSeq = idx.eventSeq + 1
@@ -575,21 +909,22 @@ func (idx *BlockerIndexer) indexEvents(batch dbm.Batch, events []abci.Event, typ
 			// index iff the event specified index:true and it's not a reserved event
 			compositeKey := fmt.Sprintf("%s.%s", event.Type, string(attr.Key))
 			if compositeKey == types.BlockHeightKey {
-				return fmt.Errorf("event type and attribute key \"%s\" is reserved; please use a different key", compositeKey)
+				return nil, fmt.Errorf("event type and attribute key \"%s\" is reserved; please use a different key", compositeKey)
 			}
 
-			if attr.GetIndex() {
+			if attr.GetIndex() && idx.filter.Allows(compositeKey) {
 				key, err := eventKey(compositeKey, typ, string(attr.Value), height, idx.eventSeq)
 				if err != nil {
-					return fmt.Errorf("failed to create block index key: %w", err)
+					return nil, fmt.Errorf("failed to create block index key: %w", err)
 				}
 
 				if err := batch.Set(key, heightBz); err != nil {
-					return err
+					return nil, err
 				}
+				keys = append(keys, key)
 			}
 		}
 	}
 
-	return nil
+	return keys, nil
 }
@@ -6,10 +6,12 @@ import (
 	"testing"
 
 	db "github.com/cometbft/cometbft-db"
+	"github.com/google/orderedcode"
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/state/indexer"
 	blockidxkv "github.com/tendermint/tendermint/state/indexer/block/kv"
 	"github.com/tendermint/tendermint/types"
 )
@@ -141,6 +143,42 @@ func TestBlockIndexer(t *testing.T) {
 			q:       query.MustParse("end_event.foo CONTAINS '1'"),
 			results: []int64{1, 10},
 		},
+		"end_event.foo = 2 OR end_event.foo >= 10": {
+			q:       query.MustParse("end_event.foo = 2 OR end_event.foo >= 10"),
+			results: []int64{1, 2, 10},
+		},
+		"(end_event.foo = 2 OR block.height = 3) AND begin_event.proposer EXISTS": {
+			q:       query.MustParse("(end_event.foo = 2 OR block.height = 3) AND begin_event.proposer EXISTS"),
+			results: []int64{2, 3},
+		},
+		"NOT end_event.foo EXISTS": {
+			q:       query.MustParse("NOT end_event.foo EXISTS"),
+			results: []int64{3, 5, 7, 9, 11},
+		},
+		"end_event.foo < 8 AND NOT block.height = 4": {
+			q:       query.MustParse("end_event.foo < 8 AND NOT block.height = 4"),
+			results: []int64{2, 6},
+		},
+		"end_event.foo IN (4, 6, 7)": {
+			q:       query.MustParse("end_event.foo IN (4, 6, 7)"),
+			results: []int64{4, 6},
+		},
+		"block.height IN (4, 100)": {
+			q:       query.MustParse("block.height IN (4, 100)"),
+			results: []int64{4},
+		},
+		"end_event.foo STARTS WITH '1'": {
+			q:       query.MustParse("end_event.foo STARTS WITH '1'"),
+			results: []int64{1, 10},
+		},
+		"end_event.foo STARTS WITH '10'": {
+			q:       query.MustParse("end_event.foo STARTS WITH '10'"),
+			results: []int64{1, 10},
+		},
+		"end_event.foo STARTS WITH '100'": {
+			q:       query.MustParse("end_event.foo STARTS WITH '100'"),
+			results: []int64{1},
+		},
 	}
 
 	for name, tc := range testCases {
@@ -359,6 +397,146 @@ func TestBlockIndexerMulti(t *testing.T) {
 	}
 }
 
+func TestBlockIndexerEventFilter(t *testing.T) {
+	store := db.NewPrefixDB(db.NewMemDB(), []byte("block_events"))
+	filter := indexer.NewEventFilter(nil, []string{"begin_event.*"})
+	indexer := blockidxkv.New(store, blockidxkv.WithEventFilter(filter))
+
+	require.NoError(t, indexer.Index(types.EventDataNewBlockHeader{
+		Header: types.Header{Height: 1},
+		ResultBeginBlock: abci.ResponseBeginBlock{
+			Events: []abci.Event{
+				{
+					Type: "begin_event",
+					Attributes: []abci.EventAttribute{
+						{
+							Key:   []byte("proposer"),
+							Value: []byte("FCAA001"),
+							Index: true,
+						},
+					},
+				},
+			},
+		},
+		ResultEndBlock: abci.ResponseEndBlock{
+			Events: []abci.Event{
+				{
+					Type: "end_event",
+					Attributes: []abci.EventAttribute{
+						{
+							Key:   []byte("foo"),
+							Value: []byte("100"),
+							Index: true,
+						},
+					},
+				},
+			},
+		},
+	}))
+
+	results, err := indexer.Search(context.Background(), query.MustParse("begin_event.proposer = 'FCAA001'"))
+	require.NoError(t, err)
+	require.Empty(t, results)
+	results, err = indexer.Search(context.Background(), query.MustParse("end_event.foo = 100"))
+	require.NoError(t, err)
+	require.Equal(t, []int64{1}, results)
+}
+
+func TestBlockIndexerPrune(t *testing.T) {
+	store := db.NewPrefixDB(db.NewMemDB(), []byte("block_events"))
+	indexer := blockidxkv.New(store)
+
+	for i := int64(1); i <= 10; i++ {
+		require.NoError(t, indexer.Index(types.EventDataNewBlockHeader{
+			Header: types.Header{Height: i},
+			ResultEndBlock: abci.ResponseEndBlock{
+				Events: []abci.Event{
+					{
+						Type: "end_event",
+						Attributes: []abci.EventAttribute{
+							{
+								Key:   []byte("foo"),
+								Value: []byte(fmt.Sprintf("%d", i)),
+								Index: true,
+							},
+						},
+					},
+				},
+			},
+		}))
+	}
+
+	// The event keys of height 2 are not recorded, as when indexed by a
+	// previous version.
+	key, err := orderedcode.Append(nil, "height_events", int64(2))
+	require.NoError(t, err)
+	require.NoError(t, store.Delete(key))
+
+	pruned, err := indexer.Prune(6)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, pruned)
+
+	for i := int64(1); i <= 10; i++ {
+		has, err := indexer.Has(i)
+		require.NoError(t, err)
+		require.Equal(t, i >= 6, has)
+	}
+	results, err := indexer.Search(context.Background(), query.MustParse("end_event.foo > 0"))
+	require.NoError(t, err)
+	require.Equal(t, []int64{6, 7, 8, 9, 10}, results)
+
+	// Nothing is left once all the heights are pruned.
+	pruned, err = indexer.Prune(11)
+	require.NoError(t, err)
+	require.EqualValues(t, 5, pruned)
+	it, err := store.Iterator(nil, nil)
+	require.NoError(t, err)
+	defer it.Close()
+	require.False(t, it.Valid())
+}
+
+func TestBlockIndexerRewind(t *testing.T) {
+	store := db.NewPrefixDB(db.NewMemDB(), []byte("block_events"))
+	indexer := blockidxkv.New(store)
+
+	for i := int64(1); i <= 10; i++ {
+		require.NoError(t, indexer.Index(types.EventDataNewBlockHeader{
+			Header: types.Header{Height: i},
+			ResultEndBlock: abci.ResponseEndBlock{
+				Events: []abci.Event{
+					{
+						Type: "end_event",
+						Attributes: []abci.EventAttribute{
+							{
+								Key:   []byte("foo"),
+								Value: []byte(fmt.Sprintf("%d", i)),
+								Index: true,
+							},
+						},
+					},
+				},
+			},
+		}))
+	}
+
+	removed, err := indexer.Rewind(6)
+	require.NoError(t, err)
+	require.EqualValues(t, 4, removed)
+
+	for i := int64(1); i <= 10; i++ {
+		has, err := indexer.Has(i)
+		require.NoError(t, err)
+		require.Equal(t, i <= 6, has)
+	}
+	results, err := indexer.Search(context.Background(), query.MustParse("end_event.foo > 0"))
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3, 4, 5, 6}, results)
+
+	removed, err = indexer.Rewind(6)
+	require.NoError(t, err)
+	require.EqualValues(t, 0, removed)
+}
+
 func TestBigInt(t *testing.T) {
 
 	bigInt := "10000000000000000000"
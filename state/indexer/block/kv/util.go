@@ -2,6 +2,7 @@ package kv
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"github.com/google/orderedcode"
 	"github.com/tendermint/tendermint/libs/pubsub/query"
@@ -11,6 +12,9 @@ import (
 	"strconv"
 )
 
+// eventKeysPrefix prefixes the keys of the event keys indexed per height.
+const eventKeysPrefix = "height_events"
+
 type HeightInfo struct {
 	heightRange     indexer.QueryRange
 	height          int64
@@ -48,6 +52,45 @@ func heightKey(height int64) ([]byte, error) {
 	)
 }
 
+// eventKeysKey returns the key of the event keys indexed at height. Unlike the
+// composite keys of the events, its prefix holds no dot.
+func eventKeysKey(height int64) ([]byte, error) {
+	return orderedcode.Append(
+		nil,
+		eventKeysPrefix,
+		height,
+	)
+}
+
+// encodeKeys encodes keys as their number followed by each length-prefixed
+// key, as decoded by decodeKeys.
+func encodeKeys(keys [][]byte) []byte {
+	bz := binary.AppendUvarint(nil, uint64(len(keys)))
+	for _, key := range keys {
+		bz = binary.AppendUvarint(bz, uint64(len(key)))
+		bz = append(bz, key...)
+	}
+	return bz
+}
+
+func decodeKeys(bz []byte) ([][]byte, error) {
+	count, n := binary.Uvarint(bz)
+	if n <= 0 {
+		return nil, errors.New("invalid key count")
+	}
+	bz = bz[n:]
+	keys := make([][]byte, 0, count)
+	for i := uint64(0); i < count; i++ {
+		size, n := binary.Uvarint(bz)
+		if n <= 0 || uint64(len(bz)-n) < size {
+			return nil, fmt.Errorf("invalid key %d", i)
+		}
+		keys = append(keys, bz[n:n+int(size)])
+		bz = bz[n+int(size):]
+	}
+	return keys, nil
+}
+
 func eventKey(compositeKey, typ, eventValue string, height int64, eventSeq int64) ([]byte, error) {
 	return orderedcode.Append(
 		nil,
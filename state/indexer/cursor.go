@@ -0,0 +1,84 @@
+package indexer
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// Cursor is the position of a search result, after which the next page of
+// results starts. Blocks have no index.
+type Cursor struct {
+	Height int64
+	Index  uint32
+}
+
+// String encodes c into an opaque token, to be decoded with ParseCursor.
+func (c Cursor) String() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d/%d", c.Height, c.Index)))
+}
+
+// ParseCursor decodes a token returned by Cursor.String.
+func ParseCursor(token string) (Cursor, error) {
+	bz, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+	var c Cursor
+	if n, err := fmt.Sscanf(string(bz), "%d/%d", &c.Height, &c.Index); err != nil || n != 2 || c.Height < 1 {
+		return Cursor{}, errors.New("invalid cursor")
+	}
+	return c, nil
+}
+
+// Less returns true if c comes before other, by height and then by index.
+func (c Cursor) Less(other Cursor) bool {
+	if c.Height != other.Height {
+		return c.Height < other.Height
+	}
+	return c.Index < other.Index
+}
+
+// Page selects a page of search results, ordered by height and index.
+type Page struct {
+	// Position after which the page starts, in the order of the page. nil
+	// starts from the first result.
+	After *Cursor
+	// Number of results skipped after After.
+	Skip int
+	// Maximum number of results. 0 means no limit.
+	Limit int
+	// Whether results are in descending order.
+	Desc bool
+}
+
+// Less returns true if a comes before b in the order of p.
+func (p Page) Less(a, b Cursor) bool {
+	if p.Desc {
+		return b.Less(a)
+	}
+	return a.Less(b)
+}
+
+// Slice returns the bounds of p within n results sorted in its order, the
+// position of each being returned by cursor, and the position of the last
+// result of p if more results follow it.
+func (p Page) Slice(n int, cursor func(i int) Cursor) (start, end int, next *Cursor) {
+	if p.After != nil {
+		start = sort.Search(n, func(i int) bool { return p.Less(*p.After, cursor(i)) })
+	}
+	start += p.Skip
+	if start > n {
+		start = n
+	}
+	end = n
+	if p.Limit > 0 && start+p.Limit < n {
+		end = start + p.Limit
+	}
+	if end < n && end > start {
+		c := cursor(end - 1)
+		next = &c
+	}
+	return start, end, next
+}
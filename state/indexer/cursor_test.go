@@ -0,0 +1,48 @@
+package indexer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/state/indexer"
+)
+
+func TestCursor(t *testing.T) {
+	c := indexer.Cursor{Height: 10, Index: 2}
+	parsed, err := indexer.ParseCursor(c.String())
+	require.NoError(t, err)
+	assert.Equal(t, c, parsed)
+
+	for _, token := range []string{"", "10/2", "MTA", "MC8w"} {
+		_, err := indexer.ParseCursor(token)
+		assert.Error(t, err, token)
+	}
+}
+
+func TestPageSlice(t *testing.T) {
+	heights := []int64{1, 2, 4, 5, 7}
+	cursor := func(i int) indexer.Cursor { return indexer.Cursor{Height: heights[i]} }
+
+	start, end, next := indexer.Page{Limit: 2}.Slice(len(heights), cursor)
+	assert.Equal(t, 0, start)
+	assert.Equal(t, 2, end)
+	assert.Equal(t, &indexer.Cursor{Height: 2}, next)
+
+	// The cursor needs not be a result.
+	start, end, next = indexer.Page{After: &indexer.Cursor{Height: 3}, Limit: 2}.Slice(len(heights), cursor)
+	assert.Equal(t, 2, start)
+	assert.Equal(t, 4, end)
+	assert.Equal(t, &indexer.Cursor{Height: 5}, next)
+
+	start, end, next = indexer.Page{After: &indexer.Cursor{Height: 5}, Limit: 2}.Slice(len(heights), cursor)
+	assert.Equal(t, 4, start)
+	assert.Equal(t, 5, end)
+	assert.Nil(t, next)
+
+	start, end, next = indexer.Page{Skip: 10, Limit: 2}.Slice(len(heights), cursor)
+	assert.Equal(t, 5, start)
+	assert.Equal(t, 5, end)
+	assert.Nil(t, next)
+}
@@ -0,0 +1,48 @@
+package indexer
+
+import "strings"
+
+// EventFilter selects the event attributes indexed, by their composite key
+// ("type.key"). Patterns are either composite keys, such as
+// "transfer.recipient", or prefixes ending with '*', such as "message.*".
+//
+// A nil EventFilter allows every attribute.
+type EventFilter struct {
+	include []string
+	exclude []string
+}
+
+// NewEventFilter returns a filter allowing the composite keys matching one of
+// the include patterns, or all of them if include is empty, unless they match
+// one of the exclude patterns. It returns nil if both lists are empty.
+func NewEventFilter(include, exclude []string) *EventFilter {
+	if len(include) == 0 && len(exclude) == 0 {
+		return nil
+	}
+	return &EventFilter{include: include, exclude: exclude}
+}
+
+// Allows returns true if the attributes with the given composite key are to
+// be indexed.
+func (f *EventFilter) Allows(compositeKey string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.include) > 0 && !matchesAny(f.include, compositeKey) {
+		return false
+	}
+	return !matchesAny(f.exclude, compositeKey)
+}
+
+func matchesAny(patterns []string, compositeKey string) bool {
+	for _, pattern := range patterns {
+		if prefix := strings.TrimSuffix(pattern, "*"); prefix != pattern {
+			if strings.HasPrefix(compositeKey, prefix) {
+				return true
+			}
+		} else if compositeKey == pattern {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,35 @@
+package indexer_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tendermint/tendermint/state/indexer"
+)
+
+func TestEventFilter(t *testing.T) {
+	var none *indexer.EventFilter
+	assert.True(t, none.Allows("transfer.recipient"))
+	assert.Nil(t, indexer.NewEventFilter(nil, nil))
+
+	testCases := []struct {
+		include, exclude []string
+		key              string
+		allowed          bool
+	}{
+		{[]string{"transfer.recipient"}, nil, "transfer.recipient", true},
+		{[]string{"transfer.recipient"}, nil, "transfer.sender", false},
+		{[]string{"message.*"}, nil, "message.action", true},
+		{[]string{"message.*"}, nil, "messages.action", false},
+		{nil, []string{"message.*"}, "message.action", false},
+		{nil, []string{"message.*"}, "transfer.sender", true},
+		{[]string{"transfer.*"}, []string{"transfer.memo"}, "transfer.memo", false},
+		{[]string{"transfer.*"}, []string{"transfer.memo"}, "transfer.amount", true},
+		{[]string{"*"}, nil, "transfer.amount", true},
+	}
+	for _, tc := range testCases {
+		f := indexer.NewEventFilter(tc.include, tc.exclude)
+		assert.Equal(t, tc.allowed, f.Allows(tc.key), "%v %v %s", tc.include, tc.exclude, tc.key)
+	}
+}
@@ -0,0 +1,17 @@
+package indexer
+
+// Pruner is implemented by the transaction and block indexers able to remove
+// the data indexed at old heights.
+type Pruner interface {
+	// Prune removes the data indexed at the heights below retainHeight, and
+	// returns the number of transactions, or blocks, removed.
+	Prune(retainHeight int64) (uint64, error)
+}
+
+// Rewinder is implemented by the transaction and block indexers able to
+// remove the data indexed at the last heights, as when rolling back.
+type Rewinder interface {
+	// Rewind removes the data indexed at the heights above height, and returns
+	// the number of transactions, or blocks, removed.
+	Rewind(height int64) (uint64, error)
+}
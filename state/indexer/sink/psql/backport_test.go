@@ -8,4 +8,6 @@ import (
 var (
 	_ indexer.BlockIndexer = BackportBlockIndexer{}
 	_ txindex.TxIndexer    = BackportTxIndexer{}
+	_ indexer.Pruner       = BackportBlockIndexer{}
+	_ indexer.Pruner       = BackportTxIndexer{}
 )
@@ -19,6 +19,8 @@ import (
 	"github.com/stretchr/testify/require"
 
 	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/state/indexer/sink/sqlsink"
 	"github.com/tendermint/tendermint/state/txindex"
 	"github.com/tendermint/tendermint/types"
 
@@ -139,33 +141,37 @@ func TestMain(m *testing.M) {
 
 func TestIndexing(t *testing.T) {
 	t.Run("IndexBlockEvents", func(t *testing.T) {
-		indexer := &EventSink{store: testDB(), chainID: chainID}
+		indexer := &EventSink{sqlsink.NewEventSink(testDB(), chainID, dialect{})}
 		require.NoError(t, indexer.IndexBlockEvents(newTestBlockHeader()))
 
 		verifyBlock(t, 1)
 		verifyBlock(t, 2)
 
-		verifyNotImplemented(t, "hasBlock", func() (bool, error) { return indexer.HasBlock(1) })
-		verifyNotImplemented(t, "hasBlock", func() (bool, error) { return indexer.HasBlock(2) })
+		has, err := indexer.HasBlock(1)
+		require.NoError(t, err)
+		assert.True(t, has)
+		has, err = indexer.HasBlock(3)
+		require.NoError(t, err)
+		assert.False(t, has)
 
-		verifyNotImplemented(t, "block search", func() (bool, error) {
-			v, err := indexer.SearchBlockEvents(context.Background(), nil)
-			return v != nil, err
-		})
+		heights, err := indexer.SearchBlockEvents(context.Background(),
+			query.MustParse("begin_event.proposer = 'FCAA001'"))
+		require.NoError(t, err)
+		assert.Equal(t, []int64{1}, heights)
 
-		require.NoError(t, verifyTimeStamp(tableBlocks))
+		require.NoError(t, verifyTimeStamp(sqlsink.TableBlocks))
 
 		// Attempting to reindex the same events should gracefully succeed.
 		require.NoError(t, indexer.IndexBlockEvents(newTestBlockHeader()))
 	})
 
 	t.Run("IndexTxEvents", func(t *testing.T) {
-		indexer := &EventSink{store: testDB(), chainID: chainID}
+		indexer := &EventSink{sqlsink.NewEventSink(testDB(), chainID, dialect{})}
 
 		txResult := txResultWithEvents([]abci.Event{
-			makeIndexedEvent("account.number", "1"),
-			makeIndexedEvent("account.owner", "Ivan"),
-			makeIndexedEvent("account.owner", "Yulieta"),
+			sqlsink.MakeIndexedEvent("account.number", "1"),
+			sqlsink.MakeIndexedEvent("account.owner", "Ivan"),
+			sqlsink.MakeIndexedEvent("account.owner", "Yulieta"),
 
 			{Type: "", Attributes: []abci.EventAttribute{
 				{
@@ -181,17 +187,16 @@ func TestIndexing(t *testing.T) {
 		require.NoError(t, err)
 		assert.Equal(t, txResult, txr)
 
-		require.NoError(t, verifyTimeStamp(tableTxResults))
+		require.NoError(t, verifyTimeStamp(sqlsink.TableTxResults))
 		require.NoError(t, verifyTimeStamp(viewTxEvents))
 
-		verifyNotImplemented(t, "getTxByHash", func() (bool, error) {
-			txr, err := indexer.GetTxByHash(types.Tx(txResult.Tx).Hash())
-			return txr != nil, err
-		})
-		verifyNotImplemented(t, "tx search", func() (bool, error) {
-			txr, err := indexer.SearchTxEvents(context.Background(), nil)
-			return txr != nil, err
-		})
+		txr, err = indexer.GetTxByHash(types.Tx(txResult.Tx).Hash())
+		require.NoError(t, err)
+		assert.Equal(t, txResult, txr)
+
+		txrs, err := indexer.SearchTxEvents(context.Background(), query.MustParse("account.owner = 'Ivan'"))
+		require.NoError(t, err)
+		assert.Equal(t, []*abci.TxResult{txResult}, txrs)
 
 		// try to insert the duplicate tx events.
 		err = indexer.IndexTxEvents([]*abci.TxResult{txResult})
@@ -199,7 +204,7 @@ func TestIndexing(t *testing.T) {
 	})
 
 	t.Run("IndexerService", func(t *testing.T) {
-		indexer := &EventSink{store: testDB(), chainID: chainID}
+		indexer := &EventSink{sqlsink.NewEventSink(testDB(), chainID, dialect{})}
 
 		// event bus
 		eventBus := types.NewEventBus()
@@ -211,7 +216,7 @@ func TestIndexing(t *testing.T) {
 			}
 		})
 
-		service := txindex.NewIndexerService(indexer.TxIndexer(), indexer.BlockIndexer(), eventBus, true)
+		service := txindex.NewIndexerService(indexer.TxIndexer(), indexer.BlockIndexer(), eventBus, "", true)
 		err = service.Start()
 		require.NoError(t, err)
 		t.Cleanup(func() {
@@ -248,8 +253,87 @@ func TestIndexing(t *testing.T) {
 	})
 }
 
+func TestSearch(t *testing.T) {
+	// A chain of its own, not to match the txs and blocks of other tests.
+	indexer := &EventSink{sqlsink.NewEventSink(testDB(), "search-chainID", dialect{})}
+	ctx := context.Background()
+
+	var txResults []*abci.TxResult
+	for height := int64(1); height <= 5; height++ {
+		require.NoError(t, indexer.IndexBlockEvents(types.EventDataNewBlockHeader{
+			Header: types.Header{Height: height},
+			ResultEndBlock: abci.ResponseEndBlock{
+				Events: []abci.Event{sqlsink.MakeIndexedEvent("end_event.foo", fmt.Sprint(height*10))},
+			},
+		}))
+
+		txResult := txResultWithEvents([]abci.Event{
+			{Type: "transfer", Attributes: []abci.EventAttribute{
+				{Key: []byte("sender"), Value: []byte(fmt.Sprintf("sender%d", height%2)), Index: true},
+				{Key: []byte("amount"), Value: []byte(fmt.Sprint(height)), Index: true},
+				{Key: []byte("fee"), Value: []byte(fmt.Sprintf("%d.5stake", height)), Index: true},
+			}},
+			{Type: "transfer", Attributes: []abci.EventAttribute{
+				{Key: []byte("sender"), Value: []byte("fee"), Index: true},
+				{Key: []byte("amount"), Value: []byte("100"), Index: true},
+			}},
+		})
+		txResult.Tx = types.Tx(fmt.Sprintf("tx %d", height))
+		txResult.Height = height
+		require.NoError(t, indexer.IndexTxEvents([]*abci.TxResult{txResult}))
+		txResults = append(txResults, txResult)
+	}
+
+	txTestCases := []struct {
+		q       string
+		heights []int64
+	}{
+		{"transfer.sender = 'sender1'", []int64{1, 3, 5}},
+		{"transfer.sender = 'sender1' AND tx.height > 1", []int64{3, 5}},
+		{"transfer.amount >= 4", []int64{1, 2, 3, 4, 5}},
+		{"transfer.amount < 3", []int64{1, 2}},
+		{"transfer.fee > 3", []int64{4, 5}},
+		{"match.events = 1 AND transfer.sender = 'sender0' AND transfer.amount >= 4", []int64{4}},
+		{"match.events = 1 AND transfer.sender = 'fee' AND transfer.amount = 3", []int64{}},
+		{"transfer.sender = 'fee' AND transfer.amount = 3", []int64{3}},
+		{"tx.height = 2 OR transfer.sender STARTS WITH 'sender1'", []int64{1, 2, 3, 5}},
+		{"transfer.sender EXISTS AND NOT tx.height IN (1, 2)", []int64{3, 4, 5}},
+		{"transfer EXISTS AND NOT transfer.sender CONTAINS '0'", []int64{1, 3, 5}},
+		{"tx.hash = '" + fmt.Sprintf("%x", types.Tx(txResults[3].Tx).Hash()) + "'", []int64{4}},
+	}
+	for _, tc := range txTestCases {
+		txrs, err := indexer.SearchTxEvents(ctx, query.MustParse(tc.q))
+		require.NoError(t, err, tc.q)
+		heights := make([]int64, 0, len(txrs))
+		for _, txr := range txrs {
+			heights = append(heights, txr.Height)
+		}
+		assert.ElementsMatch(t, tc.heights, heights, tc.q)
+	}
+
+	blockTestCases := []struct {
+		q       string
+		heights []int64
+	}{
+		{"end_event.foo > 25", []int64{3, 4, 5}},
+		{"end_event.foo > 25 AND block.height < 5", []int64{3, 4}},
+		{"end_event EXISTS AND NOT end_event.foo = 30", []int64{1, 2, 4, 5}},
+	}
+	for _, tc := range blockTestCases {
+		heights, err := indexer.SearchBlockEvents(ctx, query.MustParse(tc.q))
+		require.NoError(t, err, tc.q)
+		assert.Equal(t, tc.heights, heights, tc.q)
+	}
+
+	// The negated conditions are only removed from the matches of others.
+	_, err := indexer.SearchTxEvents(ctx, query.MustParse("NOT transfer.sender CONTAINS '0'"))
+	assert.Error(t, err)
+	_, err = indexer.SearchBlockEvents(ctx, query.MustParse("block.height = 1 OR NOT end_event.foo = 30"))
+	assert.Error(t, err)
+}
+
 func TestStop(t *testing.T) {
-	indexer := &EventSink{store: testDB()}
+	indexer := &EventSink{sqlsink.NewEventSink(testDB(), "", dialect{})}
 	require.NoError(t, indexer.Stop())
 }
 
@@ -260,14 +344,14 @@ func newTestBlockHeader() types.EventDataNewBlockHeader {
 		Header: types.Header{Height: 1},
 		ResultBeginBlock: abci.ResponseBeginBlock{
 			Events: []abci.Event{
-				makeIndexedEvent("begin_event.proposer", "FCAA001"),
-				makeIndexedEvent("thingy.whatzit", "O.O"),
+				sqlsink.MakeIndexedEvent("begin_event.proposer", "FCAA001"),
+				sqlsink.MakeIndexedEvent("thingy.whatzit", "O.O"),
 			},
 		},
 		ResultEndBlock: abci.ResponseEndBlock{
 			Events: []abci.Event{
-				makeIndexedEvent("end_event.foo", "100"),
-				makeIndexedEvent("thingy.whatzit", "-.O"),
+				sqlsink.MakeIndexedEvent("end_event.foo", "100"),
+				sqlsink.MakeIndexedEvent("thingy.whatzit", "-.O"),
 			},
 		},
 	}
@@ -320,7 +404,7 @@ func loadTxResult(hash []byte) (*abci.TxResult, error) {
 	hashString := fmt.Sprintf("%X", hash)
 	var resultData []byte
 	if err := testDB().QueryRow(`
-SELECT tx_result FROM `+tableTxResults+` WHERE tx_hash = $1;
+SELECT tx_result FROM `+sqlsink.TableTxResults+` WHERE tx_hash = $1;
 `, hashString).Scan(&resultData); err != nil {
 		return nil, fmt.Errorf("lookup transaction for hash %q failed: %v", hashString, err)
 	}
@@ -344,7 +428,7 @@ SELECT DISTINCT %[1]s.created_at
 func verifyBlock(t *testing.T, height int64) {
 	// Check that the blocks table contains an entry for this height.
 	if err := testDB().QueryRow(`
-SELECT height FROM `+tableBlocks+` WHERE height = $1;
+SELECT height FROM `+sqlsink.TableBlocks+` WHERE height = $1;
 `, height).Err(); err == sql.ErrNoRows {
 		t.Errorf("No block found for height=%d", height)
 	} else if err != nil {
@@ -371,20 +455,6 @@ SELECT type, height, chain_id FROM `+viewBlockEvents+`
 	}
 }
 
-// verifyNotImplemented calls f and verifies that it returns both a
-// false-valued flag and a non-nil error whose string matching the expected
-// "not supported" message with label prefixed.
-func verifyNotImplemented(t *testing.T, label string, f func() (bool, error)) {
-	t.Helper()
-	t.Logf("Verifying that %q reports it is not implemented", label)
-
-	want := label + " is not supported via the postgres event sink"
-	ok, err := f()
-	assert.False(t, ok)
-	require.NotNil(t, err)
-	assert.Equal(t, want, err.Error())
-}
-
 // waitForInterrupt blocks until a SIGINT is received by the process.
 func waitForInterrupt() {
 	ch := make(chan os.Signal, 1)
@@ -0,0 +1,103 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/state/indexer"
+	"github.com/tendermint/tendermint/state/txindex"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	_ indexer.BlockIndexer = BlockIndexer{}
+	_ txindex.TxIndexer    = TxIndexer{}
+	_ indexer.Pruner       = BlockIndexer{}
+	_ indexer.Pruner       = TxIndexer{}
+)
+
+// TxIndexer returns a transaction indexer backed by es.
+func (es *EventSink) TxIndexer() TxIndexer {
+	return TxIndexer{sqlite: es}
+}
+
+// TxIndexer implements the txindex.TxIndexer interface by delegating indexing
+// operations to an underlying SQLite event sink.
+type TxIndexer struct{ sqlite *EventSink }
+
+// AddBatch indexes a batch of transactions in SQLite, as part of TxIndexer.
+func (t TxIndexer) AddBatch(batch *txindex.Batch) error {
+	return t.sqlite.IndexTxEvents(batch.Ops)
+}
+
+// Index indexes a single transaction result in SQLite, as part of TxIndexer.
+func (t TxIndexer) Index(txr *abci.TxResult) error {
+	return t.sqlite.IndexTxEvents([]*abci.TxResult{txr})
+}
+
+// Get returns the transaction with the given hash, or nil if it was not
+// indexed, as part of TxIndexer.
+func (t TxIndexer) Get(hash []byte) (*abci.TxResult, error) {
+	return t.sqlite.GetTxByHash(hash)
+}
+
+// Search returns the transactions matching q, as part of TxIndexer.
+func (t TxIndexer) Search(ctx context.Context, q *query.Query) ([]*abci.TxResult, error) {
+	return t.sqlite.SearchTxEvents(ctx, q)
+}
+
+// Prune removes the transactions indexed below retainHeight, as part of
+// indexer.Pruner.
+func (t TxIndexer) Prune(retainHeight int64) (uint64, error) {
+	return t.sqlite.PruneTxs(retainHeight)
+}
+
+// AddPod is part of TxIndexer. The pods of the "evm" stations are only stored
+// by the kv indexer.
+func (TxIndexer) AddPod(_ *txindex.Batch, stationType string) error {
+	if stationType == "evm" {
+		return errors.New("pods are not supported by the sqlite event sink")
+	}
+	return nil
+}
+
+// GetbytedataFortracks is part of TxIndexer. The sqlite event sink stores no
+// tracks data.
+func (TxIndexer) GetbytedataFortracks([]byte) ([]byte, error) {
+	return nil, nil
+}
+
+// BlockIndexer returns a block indexer backed by es.
+func (es *EventSink) BlockIndexer() BlockIndexer {
+	return BlockIndexer{sqlite: es}
+}
+
+// BlockIndexer implements the indexer.BlockIndexer interface by delegating
+// indexing operations to an underlying SQLite event sink.
+type BlockIndexer struct{ sqlite *EventSink }
+
+// Has returns true if the block at height was indexed, as part of
+// BlockIndexer.
+func (b BlockIndexer) Has(height int64) (bool, error) {
+	return b.sqlite.HasBlock(height)
+}
+
+// Index indexes block begin and end events for the specified block, as part
+// of BlockIndexer.
+func (b BlockIndexer) Index(block types.EventDataNewBlockHeader) error {
+	return b.sqlite.IndexBlockEvents(block)
+}
+
+// Search returns the heights of the blocks matching q, as part of
+// BlockIndexer.
+func (b BlockIndexer) Search(ctx context.Context, q *query.Query) ([]int64, error) {
+	return b.sqlite.SearchBlockEvents(ctx, q)
+}
+
+// Prune removes the blocks indexed below retainHeight, along with their
+// transactions, as part of indexer.Pruner.
+func (b BlockIndexer) Prune(retainHeight int64) (uint64, error) {
+	return b.sqlite.PruneBlocks(retainHeight)
+}
@@ -0,0 +1,75 @@
+// Package sqlite implements an event sink backed by a SQLite database, for the
+// nodes which don't need a PostgreSQL server. It shares the implementation of
+// the psql event sink, with the schema and SQL dialect of SQLite.
+package sqlite
+
+import (
+	"database/sql"
+	_ "embed" // embed the schema
+	"fmt"
+	"net/url"
+
+	// Register the pure Go SQLite database driver.
+	_ "modernc.org/sqlite"
+
+	"github.com/tendermint/tendermint/state/indexer/sink/sqlsink"
+)
+
+const (
+	driverName = "sqlite"
+
+	// busyTimeout is how long a connection waits for the database to be
+	// unlocked by another one, in milliseconds.
+	busyTimeout = 5000
+)
+
+// schema is installed when opening the database, unless it already exists.
+//
+//go:embed schema.sql
+var schema string
+
+// EventSink is an indexer backend providing the tx/block index services.  This
+// implementation stores records in a SQLite database using the schema defined
+// in state/indexer/sink/sqlite/schema.sql.
+type EventSink struct {
+	*sqlsink.EventSink
+}
+
+// NewEventSink opens the SQLite database file at path, creating it along with
+// its schema if needed, and constructs an event sink associated with it.
+// Events written to the sink are attributed to the specified chainID.
+func NewEventSink(path, chainID string) (*EventSink, error) {
+	params := url.Values{}
+	params.Add("_pragma", fmt.Sprintf("busy_timeout(%d)", busyTimeout))
+	params.Add("_pragma", "journal_mode(WAL)")
+	params.Add("_pragma", "foreign_keys(1)")
+	db, err := sql.Open(driverName, "file:"+path+"?"+params.Encode())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("installing the schema: %w", err)
+	}
+
+	return &EventSink{sqlsink.NewEventSink(db, chainID, dialect{})}, nil
+}
+
+// dialect writes the statements of the sink for SQLite.
+type dialect struct{}
+
+func (dialect) Placeholder(n int) string { return fmt.Sprintf("?%d", n) }
+
+func (dialect) HasPrefix(expr, prefix string) string {
+	return fmt.Sprintf("substr(%[1]s, 1, length(%[2]s)) = %[2]s", expr, prefix)
+}
+
+func (dialect) Contains(expr, substr string) string {
+	return fmt.Sprintf("instr(%s, %s) > 0", expr, substr)
+}
+
+func (dialect) Integer(expr string) string {
+	return fmt.Sprintf(
+		"CASE WHEN length(%[1]s) BETWEEN 1 AND 18 AND %[1]s NOT GLOB '*[^0-9]*' THEN CAST(%[1]s AS INTEGER) END",
+		expr)
+}
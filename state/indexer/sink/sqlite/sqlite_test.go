@@ -0,0 +1,234 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/state/indexer/sink/sqlsink"
+	"github.com/tendermint/tendermint/types"
+)
+
+const chainID = "test-chainID"
+
+func newTestEventSink(t *testing.T) *EventSink {
+	t.Helper()
+	es, err := NewEventSink(filepath.Join(t.TempDir(), "tx_index.sqlite"), chainID)
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, es.Stop()) })
+	return es
+}
+
+func TestIndexing(t *testing.T) {
+	es := newTestEventSink(t)
+	ctx := context.Background()
+
+	require.NoError(t, es.IndexBlockEvents(newTestBlockHeader(1)))
+	// Attempting to reindex the same events should gracefully succeed.
+	require.NoError(t, es.IndexBlockEvents(newTestBlockHeader(1)))
+
+	has, err := es.HasBlock(1)
+	require.NoError(t, err)
+	assert.True(t, has)
+	has, err = es.HasBlock(2)
+	require.NoError(t, err)
+	assert.False(t, has)
+
+	heights, err := es.SearchBlockEvents(ctx, query.MustParse("begin_event.proposer = 'FCAA001'"))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{1}, heights)
+
+	txResult := txResultWithEvents(1, []abci.Event{
+		sqlsink.MakeIndexedEvent("account.number", "1"),
+		sqlsink.MakeIndexedEvent("account.owner", "Ivan"),
+		sqlsink.MakeIndexedEvent("account.owner", "Yulieta"),
+
+		{Type: "", Attributes: []abci.EventAttribute{
+			{
+				Key:   []byte("not_allowed"),
+				Value: []byte("Vlad"),
+				Index: true,
+			},
+		}},
+	})
+	require.NoError(t, es.IndexTxEvents([]*abci.TxResult{txResult}))
+	// try to insert the duplicate tx events.
+	require.NoError(t, es.IndexTxEvents([]*abci.TxResult{txResult}))
+
+	txr, err := es.GetTxByHash(types.Tx(txResult.Tx).Hash())
+	require.NoError(t, err)
+	assert.Equal(t, txResult, txr)
+	txr, err = es.GetTxByHash(types.Tx("missing").Hash())
+	require.NoError(t, err)
+	assert.Nil(t, txr)
+
+	for _, q := range []string{"account.owner = 'Yulieta'", "tx.height = 1", "account.number EXISTS"} {
+		txrs, err := es.SearchTxEvents(ctx, query.MustParse(q))
+		require.NoError(t, err, q)
+		assert.Equal(t, []*abci.TxResult{txResult}, txrs, q)
+	}
+	txrs, err := es.SearchTxEvents(ctx, query.MustParse("not_allowed EXISTS"))
+	require.NoError(t, err)
+	assert.Empty(t, txrs)
+
+	// The transactions of a block which was not indexed are rejected.
+	require.Error(t, es.IndexTxEvents([]*abci.TxResult{txResultWithEvents(2, nil)}))
+}
+
+func TestSearch(t *testing.T) {
+	es := newTestEventSink(t)
+	ctx := context.Background()
+
+	var txResults []*abci.TxResult
+	for height := int64(1); height <= 5; height++ {
+		require.NoError(t, es.IndexBlockEvents(types.EventDataNewBlockHeader{
+			Header: types.Header{Height: height},
+			ResultEndBlock: abci.ResponseEndBlock{
+				Events: []abci.Event{sqlsink.MakeIndexedEvent("end_event.foo", fmt.Sprint(height*10))},
+			},
+		}))
+
+		txResult := txResultWithEvents(height, []abci.Event{
+			{Type: "transfer", Attributes: []abci.EventAttribute{
+				{Key: []byte("sender"), Value: []byte(fmt.Sprintf("sender%d", height%2)), Index: true},
+				{Key: []byte("amount"), Value: []byte(fmt.Sprint(height)), Index: true},
+				{Key: []byte("fee"), Value: []byte(fmt.Sprintf("%d.5stake", height)), Index: true},
+			}},
+			{Type: "transfer", Attributes: []abci.EventAttribute{
+				{Key: []byte("sender"), Value: []byte("fee"), Index: true},
+				{Key: []byte("amount"), Value: []byte("100"), Index: true},
+			}},
+		})
+		require.NoError(t, es.IndexTxEvents([]*abci.TxResult{txResult}))
+		txResults = append(txResults, txResult)
+	}
+
+	txTestCases := []struct {
+		q       string
+		heights []int64
+	}{
+		{"transfer.sender = 'sender1'", []int64{1, 3, 5}},
+		{"transfer.sender = 'sender1' AND tx.height > 1", []int64{3, 5}},
+		{"transfer.amount >= 4", []int64{1, 2, 3, 4, 5}},
+		{"transfer.amount < 3", []int64{1, 2}},
+		{"transfer.fee > 3", []int64{4, 5}},
+		{"match.events = 1 AND transfer.sender = 'sender0' AND transfer.amount >= 4", []int64{4}},
+		{"match.events = 1 AND transfer.sender = 'fee' AND transfer.amount = 3", []int64{}},
+		{"transfer.sender = 'fee' AND transfer.amount = 3", []int64{3}},
+		{"tx.height = 2 OR transfer.sender STARTS WITH 'sender1'", []int64{1, 2, 3, 5}},
+		{"transfer.sender EXISTS AND NOT tx.height IN (1, 2)", []int64{3, 4, 5}},
+		{"transfer.sender IN ('sender0', 'nobody')", []int64{2, 4}},
+		{"transfer EXISTS AND NOT transfer.sender CONTAINS '0'", []int64{1, 3, 5}},
+		{"transfer EXISTS AND tx.height <= 2", []int64{1, 2}},
+		{"tx.hash = '" + fmt.Sprintf("%x", types.Tx(txResults[3].Tx).Hash()) + "'", []int64{4}},
+	}
+	for _, tc := range txTestCases {
+		txrs, err := es.SearchTxEvents(ctx, query.MustParse(tc.q))
+		require.NoError(t, err, tc.q)
+		heights := make([]int64, 0, len(txrs))
+		for _, txr := range txrs {
+			heights = append(heights, txr.Height)
+		}
+		assert.ElementsMatch(t, tc.heights, heights, tc.q)
+	}
+
+	blockTestCases := []struct {
+		q       string
+		heights []int64
+	}{
+		{"end_event.foo > 25", []int64{3, 4, 5}},
+		{"end_event.foo > 25 AND block.height < 5", []int64{3, 4}},
+		{"end_event EXISTS AND NOT end_event.foo = 30", []int64{1, 2, 4, 5}},
+		{"block.height >= 4", []int64{4, 5}},
+	}
+	for _, tc := range blockTestCases {
+		heights, err := es.SearchBlockEvents(ctx, query.MustParse(tc.q))
+		require.NoError(t, err, tc.q)
+		assert.Equal(t, tc.heights, heights, tc.q)
+	}
+
+	// The negated conditions are only removed from the matches of others.
+	_, err := es.SearchTxEvents(ctx, query.MustParse("NOT transfer.sender CONTAINS '0'"))
+	assert.Error(t, err)
+	_, err = es.SearchBlockEvents(ctx, query.MustParse("block.height = 1 OR NOT end_event.foo = 30"))
+	assert.Error(t, err)
+
+	// Other chains are not searched.
+	other := &EventSink{sqlsink.NewEventSink(es.DB(), "other-chainID", dialect{})}
+	txrs, err := other.SearchTxEvents(ctx, query.MustParse("transfer.sender EXISTS"))
+	require.NoError(t, err)
+	assert.Empty(t, txrs)
+}
+
+func TestPrune(t *testing.T) {
+	es := newTestEventSink(t)
+
+	for height := int64(1); height <= 5; height++ {
+		require.NoError(t, es.IndexBlockEvents(newTestBlockHeader(height)))
+		require.NoError(t, es.IndexTxEvents([]*abci.TxResult{
+			txResultWithEvents(height, []abci.Event{sqlsink.MakeIndexedEvent("account.owner", "Ivan")}),
+		}))
+	}
+
+	pruned, err := es.TxIndexer().Prune(3)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, pruned)
+	pruned, err = es.BlockIndexer().Prune(4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, pruned)
+
+	txrs, err := es.SearchTxEvents(context.Background(), query.MustParse("account.owner = 'Ivan'"))
+	require.NoError(t, err)
+	assert.Len(t, txrs, 2)
+	heights, err := es.SearchBlockEvents(context.Background(), query.MustParse("begin_event.proposer EXISTS"))
+	require.NoError(t, err)
+	assert.Equal(t, []int64{4, 5}, heights)
+}
+
+func TestStop(t *testing.T) {
+	es, err := NewEventSink(filepath.Join(t.TempDir(), "tx_index.sqlite"), chainID)
+	require.NoError(t, err)
+	require.NoError(t, es.Stop())
+}
+
+// newTestBlockHeader constructs a fresh copy of a block header containing
+// known test values to exercise the indexer.
+func newTestBlockHeader(height int64) types.EventDataNewBlockHeader {
+	return types.EventDataNewBlockHeader{
+		Header: types.Header{Height: height},
+		ResultBeginBlock: abci.ResponseBeginBlock{
+			Events: []abci.Event{
+				sqlsink.MakeIndexedEvent("begin_event.proposer", "FCAA001"),
+				sqlsink.MakeIndexedEvent("thingy.whatzit", "O.O"),
+			},
+		},
+		ResultEndBlock: abci.ResponseEndBlock{
+			Events: []abci.Event{
+				sqlsink.MakeIndexedEvent("end_event.foo", "100"),
+				sqlsink.MakeIndexedEvent("thingy.whatzit", "-.O"),
+			},
+		},
+	}
+}
+
+// txResultWithEvents constructs a fresh transaction result at height with
+// fixed values for testing, that includes the specified events.
+func txResultWithEvents(height int64, events []abci.Event) *abci.TxResult {
+	return &abci.TxResult{
+		Height: height,
+		Index:  0,
+		Tx:     types.Tx(fmt.Sprintf("HELLO WORLD %d", height)),
+		Result: abci.ResponseDeliverTx{
+			Data:   []byte{0},
+			Code:   abci.CodeTypeOK,
+			Log:    "",
+			Events: events,
+		},
+	}
+}
@@ -0,0 +1,415 @@
+package sqlsink
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/gogo/protobuf/proto"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/types"
+)
+
+// maxLoadedTxs is the number of tx_results loaded per query, below the
+// maximum number of parameters of a statement of the databases.
+const maxLoadedTxs = 500
+
+// searchScope selects the events searched, either those of the transactions,
+// identified by their row ID, or those of the blocks, identified by their
+// height.
+type searchScope struct {
+	// The reserved height key of the scope.
+	heightKey string
+	// SQL expression of the ID of the tx or block of an event.
+	id string
+	// SQL condition selecting the events of the scope.
+	filter string
+}
+
+var (
+	txScope = searchScope{
+		heightKey: types.TxHeightKey,
+		id:        TableEvents + ".tx_id",
+		filter:    TableEvents + ".tx_id IS NOT NULL",
+	}
+	blockScope = searchScope{
+		heightKey: types.BlockHeightKey,
+		id:        TableBlocks + ".height",
+		filter:    TableEvents + ".tx_id IS NULL",
+	}
+)
+
+// sqlOperators maps the comparison operators of queries to SQL.
+var sqlOperators = map[query.Operator]string{
+	query.OpLessEqual:    "<=",
+	query.OpGreaterEqual: ">=",
+	query.OpLess:         "<",
+	query.OpGreater:      ">",
+	query.OpEqual:        "=",
+}
+
+// eventMatch is an event matching a condition, within a tx or block.
+type eventMatch struct {
+	id      int64
+	eventID int64
+}
+
+// SearchBlockEvents returns the heights of the blocks matching q, in
+// ascending order, part of the indexer.EventSink interface.
+func (es *EventSink) SearchBlockEvents(ctx context.Context, q *query.Query) ([]int64, error) {
+	ids, err := es.search(ctx, q, blockScope)
+	if err != nil {
+		return nil, err
+	}
+
+	heights := make([]int64, 0, len(ids))
+	for height := range ids {
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, nil
+}
+
+// SearchTxEvents returns the transactions matching q, in no particular
+// order, part of the indexer.EventSink interface.
+func (es *EventSink) SearchTxEvents(ctx context.Context, q *query.Query) ([]*abci.TxResult, error) {
+	ids, err := es.search(ctx, q, txScope)
+	if err != nil {
+		return nil, err
+	}
+
+	rowIDs := make([]interface{}, 0, len(ids))
+	for id := range ids {
+		rowIDs = append(rowIDs, id)
+	}
+	results := make([]*abci.TxResult, 0, len(rowIDs))
+	for len(rowIDs) > 0 {
+		n := len(rowIDs)
+		if n > maxLoadedTxs {
+			n = maxLoadedTxs
+		}
+		loaded, err := es.loadTxs(ctx, rowIDs[:n])
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, loaded...)
+		rowIDs = rowIDs[n:]
+	}
+	return results, nil
+}
+
+// loadTxs returns the transactions with the given row IDs.
+func (es *EventSink) loadTxs(ctx context.Context, rowIDs []interface{}) ([]*abci.TxResult, error) {
+	params := make([]string, len(rowIDs))
+	for i := range rowIDs {
+		params[i] = es.dialect.Placeholder(i + 1)
+	}
+	rows, err := es.store.QueryContext(ctx, `
+SELECT tx_result FROM `+TableTxResults+`
+  WHERE rowid IN (`+strings.Join(params, ", ")+`);
+`, rowIDs...)
+	if err != nil {
+		return nil, fmt.Errorf("loading tx_results: %w", err)
+	}
+	defer rows.Close()
+
+	results := make([]*abci.TxResult, 0, len(rowIDs))
+	for rows.Next() {
+		txr, err := scanTxResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, txr)
+	}
+	return results, rows.Err()
+}
+
+// GetTxByHash returns the transaction with the given hash, or nil if it was
+// not indexed, part of the indexer.EventSink interface. If the transaction
+// was indexed at several heights, the latest successful one is returned, as
+// with the kv indexer.
+func (es *EventSink) GetTxByHash(hash []byte) (*abci.TxResult, error) {
+	rows, err := es.store.Query(es.bind(`
+SELECT tx_result FROM `+TableTxResults+`
+  JOIN `+TableBlocks+` ON (`+TableTxResults+`.block_id = `+TableBlocks+`.rowid)
+  WHERE tx_hash = $1 AND chain_id = $2
+  ORDER BY height DESC;
+`), fmt.Sprintf("%X", hash), es.chainID)
+	if err != nil {
+		return nil, fmt.Errorf("loading tx_result: %w", err)
+	}
+	defer rows.Close()
+
+	var latest *abci.TxResult
+	for rows.Next() {
+		txr, err := scanTxResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		if txr.Result.IsOK() {
+			return txr, nil
+		}
+		if latest == nil {
+			latest = txr
+		}
+	}
+	return latest, rows.Err()
+}
+
+// HasBlock returns true if the block at height h was indexed, part of the
+// indexer.EventSink interface.
+func (es *EventSink) HasBlock(h int64) (bool, error) {
+	var exists bool
+	err := es.store.QueryRow(es.bind(`
+SELECT EXISTS (SELECT 1 FROM `+TableBlocks+` WHERE height = $1 AND chain_id = $2);
+`), h, es.chainID).Scan(&exists)
+	return exists, err
+}
+
+// search returns the IDs of the txs or blocks, depending on scope, matching
+// q. The query is rewritten as alternatives of conditions, each matched
+// separately before their results are merged.
+func (es *EventSink) search(ctx context.Context, q *query.Query, scope searchScope) (map[int64]struct{}, error) {
+	conjunctions, err := q.Disjunction()
+	if err != nil {
+		return nil, fmt.Errorf("error during parsing conditions from query: %w", err)
+	}
+
+	ids := make(map[int64]struct{})
+	for _, conditions := range conjunctions {
+		matches, err := es.searchConjunction(ctx, conditions, scope)
+		if err != nil {
+			return nil, err
+		}
+		for id := range matches {
+			ids[id] = struct{}{}
+		}
+	}
+	return ids, nil
+}
+
+// searchConjunction returns the IDs matching all the given conditions. The
+// IDs matching negated conditions are removed from the ones matching the
+// others, which can't all be negated.
+//
+// If the conditions include "match.events = 1", the attributes of the
+// conditions, other than the height and the hash, must all be found in the
+// same event.
+func (es *EventSink) searchConjunction(
+	ctx context.Context,
+	conditions []query.Condition,
+	scope searchScope,
+) (map[int64]struct{}, error) {
+	var (
+		matchEvents       bool
+		positive, negated []query.Condition
+	)
+	for _, c := range conditions {
+		switch {
+		case c.CompositeKey == types.MatchEventKey:
+			if n, ok := c.Operand.(*big.Int); ok && c.Op == query.OpEqual && !c.Negated {
+				matchEvents = n.Cmp(big.NewInt(1)) == 0
+			}
+		case c.Negated:
+			c.Negated = false
+			negated = append(negated, c)
+		default:
+			positive = append(positive, c)
+		}
+	}
+	if len(positive) == 0 {
+		// Matching all the txs or blocks, to remove the negated ones, would
+		// scan the whole index.
+		return nil, fmt.Errorf(
+			"the conditions of each alternative can't all be negated, add one such as a range of %s",
+			scope.heightKey)
+	}
+
+	// The IDs, and the events if matching events, matching the conditions so
+	// far, nil before the first one.
+	var (
+		ids    map[int64]struct{}
+		events map[eventMatch]struct{}
+	)
+	for _, c := range positive {
+		matches, err := es.matchCondition(ctx, c, scope)
+		if err != nil {
+			return nil, err
+		}
+
+		if matchEvents && c.CompositeKey != scope.heightKey && c.CompositeKey != types.TxHashKey {
+			matched := make(map[eventMatch]struct{}, len(matches))
+			for _, m := range matches {
+				if _, ok := events[m]; ok || events == nil {
+					matched[m] = struct{}{}
+				}
+			}
+			events = matched
+		} else {
+			matched := make(map[int64]struct{}, len(matches))
+			for _, m := range matches {
+				if _, ok := ids[m.id]; ok || ids == nil {
+					matched[m.id] = struct{}{}
+				}
+			}
+			ids = matched
+		}
+	}
+	if events != nil {
+		matched := make(map[int64]struct{}, len(events))
+		for m := range events {
+			if _, ok := ids[m.id]; ok || ids == nil {
+				matched[m.id] = struct{}{}
+			}
+		}
+		ids = matched
+	}
+
+	for _, c := range negated {
+		if len(ids) == 0 {
+			break
+		}
+		excluded, err := es.matchCondition(ctx, c, scope)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range excluded {
+			delete(ids, m.id)
+		}
+	}
+	return ids, nil
+}
+
+// matchCondition returns the events matching c, which is not negated. The
+// attributes are selected by composite key, and by value when the condition
+// can be expressed in SQL, before their values are matched against c.
+//
+// The events are read from the tables rather than from the event_attributes
+// view, which doesn't expose the events the attributes belong to.
+func (es *EventSink) matchCondition(ctx context.Context, c query.Condition, scope searchScope) ([]eventMatch, error) {
+	if c.CompositeKey == types.TxHashKey {
+		c = upperHashes(c)
+	}
+
+	args := []interface{}{es.chainID}
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return es.dialect.Placeholder(len(args))
+	}
+	where := []string{TableBlocks + ".chain_id = " + es.dialect.Placeholder(1), scope.filter}
+
+	if c.Op == query.OpExists && !strings.Contains(c.CompositeKey, ".") {
+		// Searching for the attributes of an event type, by prefix.
+		key := arg(c.CompositeKey)
+		where = append(where, es.dialect.HasPrefix("composite_key", key))
+	} else {
+		where = append(where, "composite_key = "+arg(c.CompositeKey))
+	}
+
+	switch operand := c.Operand.(type) {
+	case string:
+		switch c.Op {
+		case query.OpEqual:
+			where = append(where, "value = "+arg(operand))
+		case query.OpStartsWith:
+			where = append(where, es.dialect.HasPrefix("value", arg(operand)))
+		case query.OpContains:
+			where = append(where, es.dialect.Contains("value", arg(operand)))
+		}
+
+	case *big.Int:
+		op, ok := sqlOperators[c.Op]
+		switch {
+		case !ok || !operand.IsInt64():
+		case c.CompositeKey == scope.heightKey:
+			// The heights are compared to the ones of the blocks, rather
+			// than to the values of their attributes.
+			where = append(where, fmt.Sprintf("%s.height %s %s", TableBlocks, op, arg(operand.Int64())))
+		default:
+			// The values which are integers are compared in SQL, the others,
+			// such as decimals or amounts with a denomination, being left to
+			// be matched below.
+			value := es.dialect.Integer("value")
+			where = append(where, fmt.Sprintf("(%[1]s IS NULL OR %[1]s %s %s)", value, op, arg(operand.Int64())))
+		}
+
+	case []interface{}:
+		var values []string
+		for _, v := range operand {
+			s, ok := v.(string)
+			if !ok {
+				values = nil
+				break
+			}
+			values = append(values, arg(s))
+		}
+		if len(values) > 0 {
+			where = append(where, "value IN ("+strings.Join(values, ", ")+")")
+		}
+	}
+
+	rows, err := es.store.QueryContext(ctx, `
+SELECT `+scope.id+`, `+TableEvents+`.rowid, value FROM `+TableAttributes+`
+  JOIN `+TableEvents+` ON (`+TableEvents+`.rowid = `+TableAttributes+`.event_id)
+  JOIN `+TableBlocks+` ON (`+TableEvents+`.block_id = `+TableBlocks+`.rowid)
+  WHERE `+strings.Join(where, " AND ")+`;
+`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("matching %s: %w", c.CompositeKey, err)
+	}
+	defer rows.Close()
+
+	var matches []eventMatch
+	for rows.Next() {
+		var (
+			m     eventMatch
+			value sql.NullString
+		)
+		if err := rows.Scan(&m.id, &m.eventID, &value); err != nil {
+			return nil, err
+		}
+		// The values which can't be compared to the operand don't match.
+		if ok, err := c.MatchesValue(value.String); err != nil || !ok {
+			continue
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// upperHashes returns c with its hash operands in upper case, as the hashes
+// are indexed.
+func upperHashes(c query.Condition) query.Condition {
+	switch operand := c.Operand.(type) {
+	case string:
+		c.Operand = strings.ToUpper(operand)
+	case []interface{}:
+		operands := make([]interface{}, len(operand))
+		for i, v := range operand {
+			if s, ok := v.(string); ok {
+				v = strings.ToUpper(s)
+			}
+			operands[i] = v
+		}
+		c.Operand = operands
+	}
+	return c
+}
+
+// scanTxResult decodes the tx_result column of the current row.
+func scanTxResult(rows *sql.Rows) (*abci.TxResult, error) {
+	var resultData []byte
+	if err := rows.Scan(&resultData); err != nil {
+		return nil, err
+	}
+	txr := new(abci.TxResult)
+	if err := proto.Unmarshal(resultData, txr); err != nil {
+		return nil, fmt.Errorf("unmarshaling tx_result: %w", err)
+	}
+	return txr, nil
+}
@@ -0,0 +1,350 @@
+// Package sqlsink implements the indexing and search of the event sinks backed
+// by SQL databases, shared by the psql and sqlite event sinks, which adapt it
+// to their database with a Dialect.
+package sqlsink
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/types"
+)
+
+// The tables of the schemas of the event sinks.
+const (
+	TableBlocks     = "blocks"
+	TableTxResults  = "tx_results"
+	TableEvents     = "events"
+	TableAttributes = "attributes"
+)
+
+// Dialect adapts the statements of an EventSink to its database.
+type Dialect interface {
+	// Placeholder returns the placeholder of the nth argument of a statement,
+	// from 1, which may be used several times.
+	Placeholder(n int) string
+	// HasPrefix returns a condition true if the string expr starts with
+	// prefix.
+	HasPrefix(expr, prefix string) string
+	// Contains returns a condition true if the string expr contains substr.
+	Contains(expr, substr string) string
+	// Integer returns an expression of the string expr as a 64-bit integer,
+	// if it is made of at most 18 digits, or NULL otherwise.
+	Integer(expr string) string
+}
+
+// placeholders matches the placeholders of the statements of this package,
+// written $1, $2, etc.
+var placeholders = regexp.MustCompile(`\$[0-9]+`)
+
+// EventSink is an indexer backend providing the tx/block index services. It
+// stores records in a SQL database with the schema of the psql or sqlite
+// event sink.
+type EventSink struct {
+	store   *sql.DB
+	chainID string
+	dialect Dialect
+}
+
+// NewEventSink constructs an event sink storing records in db, with the
+// statements of dialect. Events written to the sink are attributed to the
+// specified chainID.
+func NewEventSink(db *sql.DB, chainID string, dialect Dialect) *EventSink {
+	return &EventSink{
+		store:   db,
+		chainID: chainID,
+		dialect: dialect,
+	}
+}
+
+// DB returns the underlying database connection used by the sink.
+// This is exported to support testing.
+func (es *EventSink) DB() *sql.DB { return es.store }
+
+// bind returns query with its placeholders in the dialect of es.
+func (es *EventSink) bind(query string) string {
+	return placeholders.ReplaceAllStringFunc(query, func(p string) string {
+		n, _ := strconv.Atoi(p[1:])
+		return es.dialect.Placeholder(n)
+	})
+}
+
+// runInTransaction executes query in a fresh database transaction.
+// If query reports an error, the transaction is rolled back and the
+// error from query is reported to the caller.
+// Otherwise, the result of committing the transaction is returned.
+func runInTransaction(db *sql.DB, query func(*sql.Tx) error) error {
+	dbtx, err := db.Begin()
+	if err != nil {
+		return err
+	}
+	if err := query(dbtx); err != nil {
+		_ = dbtx.Rollback() // report the initial error, not the rollback
+		return err
+	}
+	return dbtx.Commit()
+}
+
+// queryWithID executes the specified SQL query with the given arguments,
+// expecting a single-row, single-column result containing an ID. If the query
+// succeeds, the ID from the result is returned.
+func (es *EventSink) queryWithID(tx *sql.Tx, query string, args ...interface{}) (int64, error) {
+	var id int64
+	if err := tx.QueryRow(es.bind(query), args...).Scan(&id); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// insertEvents inserts a slice of events and any indexed attributes of those
+// events into the database associated with dbtx.
+//
+// If txID > 0, the event is attributed to the transaction with that
+// ID; otherwise it is recorded as a block event.
+func (es *EventSink) insertEvents(dbtx *sql.Tx, blockID, txID int64, evts []abci.Event) error {
+	// Populate the transaction ID field iff one is defined (> 0).
+	var txIDArg interface{}
+	if txID > 0 {
+		txIDArg = txID
+	}
+
+	// Add each event to the events table, and retrieve its row ID to use when
+	// adding any attributes the event provides.
+	for _, evt := range evts {
+		// Skip events with an empty type.
+		if evt.Type == "" {
+			continue
+		}
+
+		eid, err := es.queryWithID(dbtx, `
+INSERT INTO `+TableEvents+` (block_id, tx_id, type) VALUES ($1, $2, $3)
+  RETURNING rowid;
+`, blockID, txIDArg, evt.Type)
+		if err != nil {
+			return err
+		}
+
+		// Add any attributes flagged for indexing.
+		for _, attr := range evt.Attributes {
+			if !attr.Index {
+				continue
+			}
+			compositeKey := evt.Type + "." + string(attr.Key)
+			if _, err := dbtx.Exec(es.bind(`
+INSERT INTO `+TableAttributes+` (event_id, key, composite_key, value)
+  VALUES ($1, $2, $3, $4)
+  ON CONFLICT DO NOTHING;
+`), eid, string(attr.Key), compositeKey, string(attr.Value)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// MakeIndexedEvent constructs an event from the specified composite key and
+// value. If the key has the form "type.name", the event will have a single
+// attribute with that name and the value; otherwise the event will have only
+// a type and no attributes.
+func MakeIndexedEvent(compositeKey, value string) abci.Event {
+	i := strings.Index(compositeKey, ".")
+	if i < 0 {
+		return abci.Event{Type: compositeKey}
+	}
+	return abci.Event{Type: compositeKey[:i], Attributes: []abci.EventAttribute{
+		{Key: []byte(compositeKey[i+1:]), Value: []byte(value), Index: true},
+	}}
+}
+
+// IndexBlockEvents indexes the specified block header, part of the
+// indexer.EventSink interface.
+func (es *EventSink) IndexBlockEvents(h types.EventDataNewBlockHeader) error {
+	ts := time.Now().UTC()
+
+	return runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		// Add the block to the blocks table and report back its row ID for use
+		// in indexing the events for the block.
+		blockID, err := es.queryWithID(dbtx, `
+INSERT INTO `+TableBlocks+` (height, chain_id, created_at)
+  VALUES ($1, $2, $3)
+  ON CONFLICT DO NOTHING
+  RETURNING rowid;
+`, h.Header.Height, es.chainID, ts)
+		if err == sql.ErrNoRows {
+			return nil // we already saw this block; quietly succeed
+		} else if err != nil {
+			return fmt.Errorf("indexing block header: %w", err)
+		}
+
+		// Insert the special block meta-event for height.
+		if err := es.insertEvents(dbtx, blockID, 0, []abci.Event{
+			MakeIndexedEvent(types.BlockHeightKey, fmt.Sprint(h.Header.Height)),
+		}); err != nil {
+			return fmt.Errorf("block meta-events: %w", err)
+		}
+		// Insert all the block events. Order is important here,
+		if err := es.insertEvents(dbtx, blockID, 0, h.ResultBeginBlock.Events); err != nil {
+			return fmt.Errorf("begin-block events: %w", err)
+		}
+		if err := es.insertEvents(dbtx, blockID, 0, h.ResultEndBlock.Events); err != nil {
+			return fmt.Errorf("end-block events: %w", err)
+		}
+		return nil
+	})
+}
+
+// IndexTxEvents indexes the specified transaction results, part of the
+// indexer.EventSink interface. The block of the transactions must have been
+// indexed first.
+func (es *EventSink) IndexTxEvents(txrs []*abci.TxResult) error {
+	ts := time.Now().UTC()
+
+	return runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		for _, txr := range txrs {
+			if err := es.insertTx(dbtx, txr, ts); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// insertTx indexes a transaction result, along with its events, within dbtx.
+func (es *EventSink) insertTx(dbtx *sql.Tx, txr *abci.TxResult, ts time.Time) error {
+	// Encode the result message in protobuf wire format for indexing.
+	resultData, err := proto.Marshal(txr)
+	if err != nil {
+		return fmt.Errorf("marshaling tx_result: %w", err)
+	}
+
+	// Index the hash of the underlying transaction as a hex string.
+	txHash := fmt.Sprintf("%X", types.Tx(txr.Tx).Hash())
+
+	// Find the block associated with this transaction. The block header
+	// must have been indexed prior to the transactions belonging to it.
+	blockID, err := es.queryWithID(dbtx, `
+SELECT rowid FROM `+TableBlocks+` WHERE height = $1 AND chain_id = $2;
+`, txr.Height, es.chainID)
+	if err != nil {
+		return fmt.Errorf("finding block ID: %w", err)
+	}
+
+	// Insert a record for this tx_result and capture its ID for indexing events.
+	txID, err := es.queryWithID(dbtx, `
+INSERT INTO `+TableTxResults+` (block_id, "index", created_at, tx_hash, tx_result)
+  VALUES ($1, $2, $3, $4, $5)
+  ON CONFLICT DO NOTHING
+  RETURNING rowid;
+`, blockID, txr.Index, ts, txHash, resultData)
+	if err == sql.ErrNoRows {
+		return nil // we already saw this transaction; quietly succeed
+	} else if err != nil {
+		return fmt.Errorf("indexing tx_result: %w", err)
+	}
+
+	// Insert the special transaction meta-events for hash and height.
+	if err := es.insertEvents(dbtx, blockID, txID, []abci.Event{
+		MakeIndexedEvent(types.TxHashKey, txHash),
+		MakeIndexedEvent(types.TxHeightKey, fmt.Sprint(txr.Height)),
+	}); err != nil {
+		return fmt.Errorf("indexing transaction meta-events: %w", err)
+	}
+	// Index any events packaged with the transaction.
+	if err := es.insertEvents(dbtx, blockID, txID, txr.Result.Events); err != nil {
+		return fmt.Errorf("indexing transaction events: %w", err)
+	}
+	return nil
+}
+
+// PruneTxs removes the transactions indexed below retainHeight, along with
+// their events, and returns their number.
+func (es *EventSink) PruneTxs(retainHeight int64) (uint64, error) {
+	var pruned int64
+	err := runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		var err error
+		pruned, err = es.pruneTxs(dbtx, retainHeight)
+		return err
+	})
+	return uint64(pruned), err
+}
+
+// PruneBlocks removes the blocks indexed below retainHeight, along with their
+// events and transactions, and returns their number.
+func (es *EventSink) PruneBlocks(retainHeight int64) (uint64, error) {
+	var pruned int64
+	err := runInTransaction(es.store, func(dbtx *sql.Tx) error {
+		// The transactions refer to their block.
+		if _, err := es.pruneTxs(dbtx, retainHeight); err != nil {
+			return err
+		}
+
+		if _, err := dbtx.Exec(es.bind(`
+DELETE FROM `+TableAttributes+` WHERE event_id IN (
+  SELECT `+TableEvents+`.rowid FROM `+TableEvents+`
+    JOIN `+TableBlocks+` ON (`+TableEvents+`.block_id = `+TableBlocks+`.rowid)
+  WHERE height < $1 AND chain_id = $2
+);
+`), retainHeight, es.chainID); err != nil {
+			return fmt.Errorf("pruning block attributes: %w", err)
+		}
+		if _, err := dbtx.Exec(es.bind(`
+DELETE FROM `+TableEvents+` WHERE block_id IN (
+  SELECT rowid FROM `+TableBlocks+` WHERE height < $1 AND chain_id = $2
+);
+`), retainHeight, es.chainID); err != nil {
+			return fmt.Errorf("pruning block events: %w", err)
+		}
+		res, err := dbtx.Exec(es.bind(`
+DELETE FROM `+TableBlocks+` WHERE height < $1 AND chain_id = $2;
+`), retainHeight, es.chainID)
+		if err != nil {
+			return fmt.Errorf("pruning blocks: %w", err)
+		}
+		pruned, err = res.RowsAffected()
+		return err
+	})
+	return uint64(pruned), err
+}
+
+// pruneTxs removes the transactions indexed below retainHeight, along with
+// their events, within dbtx, and returns their number.
+func (es *EventSink) pruneTxs(dbtx *sql.Tx, retainHeight int64) (int64, error) {
+	if _, err := dbtx.Exec(es.bind(`
+DELETE FROM `+TableAttributes+` WHERE event_id IN (
+  SELECT `+TableEvents+`.rowid FROM `+TableEvents+`
+    JOIN `+TableTxResults+` ON (`+TableEvents+`.tx_id = `+TableTxResults+`.rowid)
+    JOIN `+TableBlocks+` ON (`+TableTxResults+`.block_id = `+TableBlocks+`.rowid)
+  WHERE height < $1 AND chain_id = $2
+);
+`), retainHeight, es.chainID); err != nil {
+		return 0, fmt.Errorf("pruning tx attributes: %w", err)
+	}
+	if _, err := dbtx.Exec(es.bind(`
+DELETE FROM `+TableEvents+` WHERE tx_id IN (
+  SELECT `+TableTxResults+`.rowid FROM `+TableTxResults+`
+    JOIN `+TableBlocks+` ON (`+TableTxResults+`.block_id = `+TableBlocks+`.rowid)
+  WHERE height < $1 AND chain_id = $2
+);
+`), retainHeight, es.chainID); err != nil {
+		return 0, fmt.Errorf("pruning tx events: %w", err)
+	}
+	res, err := dbtx.Exec(es.bind(`
+DELETE FROM `+TableTxResults+` WHERE block_id IN (
+  SELECT rowid FROM `+TableBlocks+` WHERE height < $1 AND chain_id = $2
+);
+`), retainHeight, es.chainID)
+	if err != nil {
+		return 0, fmt.Errorf("pruning tx_results: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// Stop closes the underlying database.
+func (es *EventSink) Stop() error { return es.store.Close() }
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 
+	cmtmath "github.com/tendermint/tendermint/libs/math"
 	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	cmtversion "github.com/tendermint/tendermint/proto/tendermint/version"
 	"github.com/tendermint/tendermint/version"
@@ -110,3 +111,127 @@ func Rollback(bs BlockStore, ss Store) (int64, []byte, error) {
 
 	return rolledBackState.LastBlockHeight, rolledBackState.AppHash, nil
 }
+
+// RollbackTo overwrites the current CometBFT state with the state at height,
+// rebuilt from the blocks at height and height + 1, and from the validators
+// and consensus params stored for the heights above. The blocks above height
+// are left in the block store. It returns the height and app hash of the
+// rolled back state. With dryRun, the state is rebuilt but not saved.
+// Note that this function does not affect application state.
+func RollbackTo(bs BlockStore, ss Store, height int64, dryRun bool) (int64, []byte, error) {
+	invalidState, err := ss.Load()
+	if err != nil {
+		return -1, nil, err
+	}
+	if invalidState.IsEmpty() {
+		return -1, nil, errors.New("no state found")
+	}
+	if height < invalidState.InitialHeight || height > invalidState.LastBlockHeight {
+		return -1, nil, fmt.Errorf("cannot roll back to height %d, outside of the heights %d to %d of the state",
+			height, invalidState.InitialHeight, invalidState.LastBlockHeight)
+	}
+
+	// The state may already be rolled back, the blocks above it remaining.
+	if height == invalidState.LastBlockHeight {
+		return invalidState.LastBlockHeight, invalidState.AppHash, nil
+	}
+	// As in Rollback, the block store may be one height above the state.
+	if blockHeight := bs.Height(); blockHeight != invalidState.LastBlockHeight &&
+		blockHeight != invalidState.LastBlockHeight+1 {
+		return -1, nil, fmt.Errorf("statestore height (%d) is not one below or equal to blockstore height (%d)",
+			invalidState.LastBlockHeight, blockHeight)
+	}
+
+	rollbackBlock := bs.LoadBlockMeta(height)
+	if rollbackBlock == nil {
+		return -1, nil, fmt.Errorf("block at height %d not found", height)
+	}
+	// The app hash and last results hash of the block at height are only
+	// agreed upon in the following block.
+	nextBlock := bs.LoadBlockMeta(height + 1)
+	if nextBlock == nil {
+		return -1, nil, fmt.Errorf("block at height %d not found", height+1)
+	}
+
+	lastValidators, err := ss.LoadValidators(height)
+	if err != nil {
+		return -1, nil, err
+	}
+	validators, err := ss.LoadValidators(height + 1)
+	if err != nil {
+		return -1, nil, err
+	}
+	nextValidators, err := ss.LoadValidators(height + 2)
+	if err != nil {
+		return -1, nil, err
+	}
+	params, err := ss.LoadConsensusParams(height + 1)
+	if err != nil {
+		return -1, nil, err
+	}
+	valChangeHeight, paramsChangeHeight, err := lastHeightsChanged(ss, invalidState, height)
+	if err != nil {
+		return -1, nil, err
+	}
+
+	rolledBackState := State{
+		Version: cmtstate.Version{
+			Consensus: cmtversion.Consensus{
+				Block: version.BlockProtocol,
+				App:   params.Version.AppVersion,
+			},
+			Software: version.TMCoreSemVer,
+		},
+		// immutable fields
+		ChainID:       invalidState.ChainID,
+		InitialHeight: invalidState.InitialHeight,
+
+		LastBlockHeight: rollbackBlock.Header.Height,
+		LastBlockID:     rollbackBlock.BlockID,
+		LastBlockTime:   rollbackBlock.Header.Time,
+
+		NextValidators:              nextValidators,
+		Validators:                  validators,
+		LastValidators:              lastValidators,
+		LastHeightValidatorsChanged: valChangeHeight,
+
+		ConsensusParams:                  params,
+		LastHeightConsensusParamsChanged: paramsChangeHeight,
+
+		LastResultsHash: nextBlock.Header.LastResultsHash,
+		AppHash:         nextBlock.Header.AppHash,
+	}
+
+	if dryRun {
+		return rolledBackState.LastBlockHeight, rolledBackState.AppHash, nil
+	}
+	if err := ss.Save(rolledBackState); err != nil {
+		return -1, nil, fmt.Errorf("failed to save rolled back state: %w", err)
+	}
+
+	return rolledBackState.LastBlockHeight, rolledBackState.AppHash, nil
+}
+
+// lastHeightsChanged returns the heights the validators and the consensus
+// params of the state at height last changed at. They are stored along with
+// the next validators and consensus params of the state when ss is a database
+// store, and are bounded by the ones of the current state otherwise, as in
+// Rollback.
+func lastHeightsChanged(ss Store, current State, height int64) (int64, int64, error) {
+	store, ok := ss.(dbStore)
+	if !ok {
+		valChangeHeight := cmtmath.MinInt64(current.LastHeightValidatorsChanged, height+1)
+		paramsChangeHeight := cmtmath.MinInt64(current.LastHeightConsensusParamsChanged, height+1)
+		return valChangeHeight, paramsChangeHeight, nil
+	}
+
+	valInfo, err := loadValidatorsInfo(store.db, height+2)
+	if err != nil {
+		return 0, 0, fmt.Errorf("loading the validators of height %d: %w", height+2, err)
+	}
+	paramsInfo, err := store.loadConsensusParamsInfo(height + 1)
+	if err != nil {
+		return 0, 0, fmt.Errorf("loading the consensus params of height %d: %w", height+1, err)
+	}
+	return valInfo.LastHeightChanged, paramsInfo.LastHeightChanged, nil
+}
@@ -2,7 +2,9 @@ package state_test
 
 import (
 	"crypto/rand"
+	"fmt"
 	"testing"
+	"time"
 
 	dbm "github.com/cometbft/cometbft-db"
 	"github.com/stretchr/testify/require"
@@ -117,6 +119,77 @@ func TestRollbackDifferentStateHeight(t *testing.T) {
 	require.Equal(t, err.Error(), "statestore height (100) is not one below or equal to blockstore height (102)")
 }
 
+func TestRollbackTo(t *testing.T) {
+	const height = int64(100)
+	stateStore := setupStateStore(t, height)
+	initialState, err := stateStore.Load()
+	require.NoError(t, err)
+
+	// save the states up to height 105, the validators changing at height 104
+	states := map[int64]state.State{height: initialState}
+	for h := height + 1; h <= height+5; h++ {
+		prev := states[h-1]
+		next := prev.Copy()
+		next.LastBlockHeight = h
+		next.LastBlockID = makeBlockIDRandom()
+		next.LastBlockTime = prev.LastBlockTime.Add(time.Second)
+		next.AppHash = tmhash.Sum([]byte(fmt.Sprintf("app_hash_%d", h)))
+		next.LastResultsHash = tmhash.Sum([]byte(fmt.Sprintf("last_results_hash_%d", h)))
+		next.LastValidators = prev.Validators
+		next.Validators = prev.NextValidators
+		next.NextValidators = prev.NextValidators.CopyIncrementProposerPriority(1)
+		if h == height+2 {
+			next.NextValidators, _ = types.RandValidatorSet(3, 10)
+			next.LastHeightValidatorsChanged = h + 2
+		}
+		require.NoError(t, stateStore.Save(next))
+		states[h] = next
+	}
+
+	blockStore := &mocks.BlockStore{}
+	blockStore.On("Height").Return(height + 5)
+	for h := height + 1; h <= height+5; h++ {
+		blockStore.On("LoadBlockMeta", h).Return(&types.BlockMeta{
+			BlockID: states[h].LastBlockID,
+			Header: types.Header{
+				Height:          h,
+				Time:            states[h].LastBlockTime,
+				AppHash:         states[h-1].AppHash,
+				LastResultsHash: states[h-1].LastResultsHash,
+			},
+		})
+	}
+
+	// the heights outside of the state can't be rolled back to
+	_, _, err = state.RollbackTo(blockStore, stateStore, height+6, false)
+	require.Error(t, err)
+	_, _, err = state.RollbackTo(blockStore, stateStore, initialState.InitialHeight-1, false)
+	require.Error(t, err)
+
+	// a dry run leaves the state as is
+	rollbackHeight, rollbackHash, err := state.RollbackTo(blockStore, stateStore, height+2, true)
+	require.NoError(t, err)
+	require.EqualValues(t, height+2, rollbackHeight)
+	require.EqualValues(t, states[height+2].AppHash, rollbackHash)
+	loadedState, err := stateStore.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, states[height+5], loadedState)
+
+	// roll back over the validator change
+	rollbackHeight, rollbackHash, err = state.RollbackTo(blockStore, stateStore, height+2, false)
+	require.NoError(t, err)
+	require.EqualValues(t, height+2, rollbackHeight)
+	require.EqualValues(t, states[height+2].AppHash, rollbackHash)
+	loadedState, err = stateStore.Load()
+	require.NoError(t, err)
+	require.EqualValues(t, states[height+2], loadedState)
+
+	// rolling back to the height of the state again is a no-op
+	rollbackHeight, _, err = state.RollbackTo(blockStore, stateStore, height+2, false)
+	require.NoError(t, err)
+	require.EqualValues(t, height+2, rollbackHeight)
+}
+
 func setupStateStore(t *testing.T, height int64) state.Store {
 	stateStore := state.NewStore(dbm.NewMemDB(), state.StoreOptions{DiscardABCIResponses: false})
 	valSet, _ := types.RandValidatorSet(5, 10)
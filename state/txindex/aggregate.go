@@ -0,0 +1,129 @@
+package txindex
+
+import (
+	"context"
+	"math/big"
+	"sort"
+
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+)
+
+// Aggregator is implemented by the TxIndexers able to aggregate the
+// transactions matching a query without loading them.
+type Aggregator interface {
+	// Count returns the number of transactions matching q.
+	Count(ctx context.Context, q *query.Query) (int, error)
+	// Aggregate adds the transactions matching q to h, along with the integer
+	// values of their attribute, a composite key (e.g. "transfer.amount"),
+	// unless empty. Only indexed attributes may be aggregated.
+	Aggregate(ctx context.Context, q *query.Query, attribute string, h *Histogram) error
+}
+
+// HistogramBucket aggregates the transactions of a range of heights.
+type HistogramBucket struct {
+	FromHeight int64
+	ToHeight   int64
+	// Number of transactions.
+	Count int
+	// Number of integer values of the aggregated attribute, and their sum,
+	// minimum and maximum, nil if there are none.
+	Values        int
+	Sum, Min, Max *big.Int
+}
+
+// Histogram aggregates transactions by buckets of heights.
+type Histogram struct {
+	bucketSize int64
+	buckets    map[int64]*HistogramBucket // by first height
+}
+
+// NewHistogram returns an empty histogram with buckets of bucketSize heights,
+// starting from height 1.
+func NewHistogram(bucketSize int64) *Histogram {
+	return &Histogram{bucketSize: bucketSize, buckets: make(map[int64]*HistogramBucket)}
+}
+
+func (h *Histogram) bucket(height int64) *HistogramBucket {
+	from := (height-1)/h.bucketSize*h.bucketSize + 1
+	b, ok := h.buckets[from]
+	if !ok {
+		b = &HistogramBucket{FromHeight: from, ToHeight: from + h.bucketSize - 1}
+		h.buckets[from] = b
+	}
+	return b
+}
+
+// AddTx counts a transaction at height.
+func (h *Histogram) AddTx(height int64) {
+	h.bucket(height).Count++
+}
+
+// AddValue aggregates a value of the attribute of a transaction at height.
+func (h *Histogram) AddValue(height int64, v *big.Int) {
+	b := h.bucket(height)
+	b.Values++
+	if b.Sum == nil {
+		b.Sum, b.Min, b.Max = new(big.Int), new(big.Int).Set(v), new(big.Int).Set(v)
+	}
+	b.Sum.Add(b.Sum, v)
+	if v.Cmp(b.Min) < 0 {
+		b.Min.Set(v)
+	}
+	if v.Cmp(b.Max) > 0 {
+		b.Max.Set(v)
+	}
+}
+
+// Buckets returns the buckets with transactions, by height.
+func (h *Histogram) Buckets() []HistogramBucket {
+	buckets := make([]HistogramBucket, 0, len(h.buckets))
+	for _, b := range h.buckets {
+		buckets = append(buckets, *b)
+	}
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].FromHeight < buckets[j].FromHeight })
+	return buckets
+}
+
+// Count returns the number of transactions of txi matching q. Unless txi is
+// an Aggregator, all the matching transactions are loaded.
+func Count(ctx context.Context, txi TxIndexer, q *query.Query) (int, error) {
+	if a, ok := txi.(Aggregator); ok {
+		return a.Count(ctx, q)
+	}
+	results, err := txi.Search(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+	return len(results), nil
+}
+
+// Aggregate adds the transactions of txi matching q to h, along with the
+// integer values of their attribute, unless empty. Unless txi is an
+// Aggregator, all the matching transactions are loaded.
+func Aggregate(ctx context.Context, txi TxIndexer, q *query.Query, attribute string, h *Histogram) error {
+	if a, ok := txi.(Aggregator); ok {
+		return a.Aggregate(ctx, q, attribute, h)
+	}
+
+	results, err := txi.Search(ctx, q)
+	if err != nil {
+		return err
+	}
+	for _, res := range results {
+		h.AddTx(res.Height)
+		if attribute == "" {
+			continue
+		}
+		for _, event := range res.Result.Events {
+			for _, attr := range event.Attributes {
+				if !attr.GetIndex() || event.Type+"."+string(attr.Key) != attribute {
+					continue
+				}
+				if v, ok := new(big.Int).SetString(string(attr.Value), 10); ok {
+					h.AddValue(res.Height, v)
+				}
+			}
+		}
+	}
+	return nil
+}
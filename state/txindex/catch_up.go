@@ -0,0 +1,387 @@
+package txindex
+
+import (
+	"fmt"
+	"strconv"
+
+	dbm "github.com/cometbft/cometbft-db"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+)
+
+var (
+	// indexedHeightKey stores the height up to which all the blocks were
+	// indexed.
+	indexedHeightKey = []byte("indexed_height")
+	// podsHeightKey stores the height up to which the pods were indexed, in
+	// the order of the heights, for the pods not to be indexed twice, nor
+	// out of order, when the blocks are.
+	podsHeightKey = []byte("pods_height")
+)
+
+// BlockSource is the block store the missing heights are indexed from.
+type BlockSource interface {
+	BlockStore
+	// Height returns the last height of the stored blocks.
+	Height() int64
+	// LoadBlock returns the block at height, or nil if it is not stored.
+	LoadBlock(height int64) *types.Block
+}
+
+// ABCIResponsesStore is the state store the results of the blocks indexed
+// from a BlockSource are loaded from.
+type ABCIResponsesStore interface {
+	LoadABCIResponses(height int64) (*cmtstate.ABCIResponses, error)
+}
+
+// SetCatchUp enables the tracking of the indexed heights, the height up to
+// which all the blocks were indexed being persisted in progress. When the
+// service starts, the stored blocks which were not indexed, because indexing
+// failed or was disabled, are indexed in the background, from the blocks of
+// blockStore and the ABCI responses of stateStore.
+//
+// The progress must be stored along with the indexes, and be specific to the
+// indexer. It must be set before the service is started.
+func (is *IndexerService) SetCatchUp(progress dbm.DB, blockStore BlockSource, stateStore ABCIResponsesStore) {
+	is.progress = progress
+	is.blockSource = blockStore
+	is.responses = stateStore
+}
+
+// IndexedHeight returns the height up to which all the blocks were indexed,
+// and false if the indexed heights are not tracked.
+func (is *IndexerService) IndexedHeight() (int64, bool) {
+	if is.progress == nil {
+		return 0, false
+	}
+	is.mtx.Lock()
+	defer is.mtx.Unlock()
+	return is.indexedHeight, true
+}
+
+// CatchingUp returns true while the blocks missing from the indexes are
+// indexed.
+func (is *IndexerService) CatchingUp() bool {
+	is.mtx.Lock()
+	defer is.mtx.Unlock()
+	return is.catchingUp
+}
+
+// RewindIndexedHeight lowers the indexed height persisted in progress to
+// height, as when rolling back, for the heights above it to be indexed again.
+// The pods of those heights are not indexed again, unless the height of the
+// pods is rewound too.
+func RewindIndexedHeight(progress dbm.DB, height int64) error {
+	indexedHeight, err := loadHeight(progress, indexedHeightKey, -1)
+	if err != nil || indexedHeight <= height {
+		return err
+	}
+	// Without a height of their own, the pods were indexed along with the
+	// blocks.
+	podsHeight, err := loadHeight(progress, podsHeightKey, -1)
+	if err != nil {
+		return err
+	}
+	if podsHeight < 0 {
+		if err := progress.Set(podsHeightKey, []byte(strconv.FormatInt(indexedHeight, 10))); err != nil {
+			return err
+		}
+	}
+	return progress.SetSync(indexedHeightKey, []byte(strconv.FormatInt(height, 10)))
+}
+
+// RewindPodsHeight lowers the height of the pods persisted in progress to
+// height, once the pods of the heights above it were removed, for them to be
+// indexed again.
+func RewindPodsHeight(progress dbm.DB, height int64) error {
+	return rewindHeight(progress, podsHeightKey, height)
+}
+
+func rewindHeight(progress dbm.DB, key []byte, height int64) error {
+	current, err := loadHeight(progress, key, -1)
+	if err != nil || current <= height {
+		return err
+	}
+	return progress.SetSync(key, []byte(strconv.FormatInt(height, 10)))
+}
+
+// startCatchUp loads the indexed height, and starts indexing the stored
+// blocks above it, if any.
+func (is *IndexerService) startCatchUp() error {
+	indexedHeight, err := is.loadIndexedHeight()
+	if err != nil {
+		return err
+	}
+	// Without a height of their own, the pods were indexed along with the
+	// blocks.
+	podsHeight, err := loadHeight(is.progress, podsHeightKey, indexedHeight)
+	if err != nil {
+		return err
+	}
+
+	height := is.blockSource.Height()
+	from := indexedHeight + 1
+	if base := is.blockSource.Base(); from < base {
+		from = base
+	}
+	if is.retainBlocks > 0 && from < height-is.retainBlocks+1 {
+		// The heights which would be pruned right away are not indexed.
+		from = height - is.retainBlocks + 1
+	}
+	if from > indexedHeight+1 {
+		is.Logger.Info("skipping heights which can't be indexed", "from", indexedHeight+1, "to", from-1)
+	}
+
+	is.mtx.Lock()
+	is.indexedHeight = from - 1
+	is.catchingUp = from <= height
+	is.mtx.Unlock()
+	if from-1 > indexedHeight {
+		is.saveIndexedHeight(from - 1)
+	}
+	is.podsHeight = podsHeight
+	if from-1 > podsHeight {
+		is.savePodsHeight(from - 1)
+	}
+
+	if from <= height {
+		is.Logger.Info("indexing the missing heights", "from", from, "to", height)
+		go is.catchUp(from, height)
+	}
+	return nil
+}
+
+// loadIndexedHeight returns the persisted indexed height. Without one, the
+// indexes are assumed to be up to date if they include the last stored
+// block, as with indexes predating the tracking of the indexed heights, or
+// to be empty otherwise.
+func (is *IndexerService) loadIndexedHeight() (int64, error) {
+	height, err := loadHeight(is.progress, indexedHeightKey, -1)
+	if err != nil || height >= 0 {
+		return height, err
+	}
+
+	height = is.blockSource.Height()
+	if height == 0 {
+		return 0, nil
+	}
+	indexed, err := is.blockIdxr.Has(height)
+	if err != nil {
+		return 0, err
+	}
+	if indexed {
+		return height, nil
+	}
+	return 0, nil
+}
+
+// loadHeight returns the height persisted at key in progress, or def if
+// there is none.
+func loadHeight(progress dbm.DB, key []byte, def int64) (int64, error) {
+	bz, err := progress.Get(key)
+	if err != nil {
+		return 0, err
+	}
+	if len(bz) == 0 {
+		return def, nil
+	}
+	height, err := strconv.ParseInt(string(bz), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", key, bz, err)
+	}
+	return height, nil
+}
+
+func (is *IndexerService) saveIndexedHeight(height int64) {
+	if err := is.progress.Set(indexedHeightKey, []byte(strconv.FormatInt(height, 10))); err != nil {
+		is.Logger.Error("failed to save the indexed height", "height", height, "err", err)
+	}
+}
+
+// savePodsHeight advances the height of the pods, which is synced for the
+// pods not to be indexed again after a crash.
+func (is *IndexerService) savePodsHeight(height int64) {
+	is.podsHeight = height
+	if err := is.progress.SetSync(podsHeightKey, []byte(strconv.FormatInt(height, 10))); err != nil {
+		is.Logger.Error("failed to save the height of the pods", "height", height, "err", err)
+	}
+}
+
+// indexPods indexes the pods of the blocks indexed above the height of the
+// pods, in the order of the heights, those of the block at height from batch
+// and the others from the block source. It stops at the first block which
+// is not indexed yet, or whose pods can't be indexed, to be resumed once the
+// next block is indexed. Once a height is passed, the pods of the lower ones
+// are no longer indexed. It must be called with indexMtx held.
+func (is *IndexerService) indexPods(height int64, batch *Batch) {
+	if is.progress == nil {
+		return
+	}
+	for p := is.podsHeight + 1; is.isIndexed(p); p++ {
+		b := batch
+		if p != height {
+			if p < is.blockSource.Base() {
+				is.Logger.Error("skipping the pods of a pruned block", "height", p)
+				is.savePodsHeight(p)
+				continue
+			}
+			var err error
+			if _, b, err = is.loadBlock(p); err != nil {
+				is.Logger.Error("failed to load the block to index the pods of", "height", p, "err", err)
+				return
+			}
+		}
+		if err := is.txIdxr.AddPod(b, tracksStationType); err != nil {
+			is.Logger.Error("failed to index the block pods", "height", p, "err", err)
+			return
+		}
+		is.savePodsHeight(p)
+	}
+}
+
+// retryMissing indexes again the lowest stored block below height which
+// failed to be indexed live, unless the missing blocks are being indexed.
+// A single block is retried at each height, not to hold up the live ones.
+func (is *IndexerService) retryMissing(height int64) {
+	if is.progress == nil {
+		return
+	}
+	is.mtx.Lock()
+	from, catchingUp := is.indexedHeight+1, is.catchingUp
+	is.mtx.Unlock()
+	if base := is.blockSource.Base(); from < base {
+		from = base
+	}
+	if catchingUp {
+		return
+	}
+
+	for h := from; h < height; h++ {
+		if is.isIndexed(h) {
+			continue
+		}
+		header, batch, err := is.loadBlock(h)
+		if err == nil {
+			err = is.indexOnce(header, batch, true)
+		}
+		if err != nil {
+			is.Logger.Error("failed to index the missing block again", "height", h, "err", err)
+		}
+		return
+	}
+}
+
+// catchUp indexes the stored blocks from one height to another, skipping the
+// ones indexed live meanwhile, one block at a time with the live ones. It
+// stops at the first block which can't be indexed, the following ones being
+// indexed again from the live blocks, or at the next start.
+func (is *IndexerService) catchUp(from, to int64) {
+	defer func() {
+		is.mtx.Lock()
+		is.catchingUp = false
+		is.mtx.Unlock()
+	}()
+
+	for height := from; height <= to; height++ {
+		select {
+		case <-is.Quit():
+			return
+		default:
+		}
+		if is.isIndexed(height) {
+			continue
+		}
+
+		header, batch, err := is.loadBlock(height)
+		if err != nil {
+			is.Logger.Error("failed to load the block to index", "height", height, "err", err)
+			return
+		}
+		if err := is.indexOnce(header, batch, true); err != nil {
+			is.Logger.Error("failed to index the missing block", "height", height, "err", err)
+			return
+		}
+	}
+	is.Logger.Info("indexed the missing heights", "from", from, "to", to)
+}
+
+// loadBlock returns the header event and the txs of the stored block at
+// height, as published when the block was committed.
+func (is *IndexerService) loadBlock(height int64) (types.EventDataNewBlockHeader, *Batch, error) {
+	block := is.blockSource.LoadBlock(height)
+	if block == nil {
+		return types.EventDataNewBlockHeader{}, nil, fmt.Errorf("block %d is not stored", height)
+	}
+	responses, err := is.responses.LoadABCIResponses(height)
+	if err != nil {
+		return types.EventDataNewBlockHeader{}, nil, err
+	}
+	if len(responses.DeliverTxs) != len(block.Txs) {
+		return types.EventDataNewBlockHeader{}, nil, fmt.Errorf("%d tx results stored for %d txs",
+			len(responses.DeliverTxs), len(block.Txs))
+	}
+
+	header := types.EventDataNewBlockHeader{
+		Header:           block.Header,
+		NumTxs:           int64(len(block.Txs)),
+		ResultBeginBlock: *responses.BeginBlock,
+		ResultEndBlock:   *responses.EndBlock,
+	}
+	batch := NewBatch(header.NumTxs)
+	for i, tx := range block.Txs {
+		if err := batch.Add(&abci.TxResult{
+			Height: height,
+			Index:  uint32(i),
+			Tx:     tx,
+			Result: *responses.DeliverTxs[i],
+		}); err != nil {
+			return types.EventDataNewBlockHeader{}, nil, err
+		}
+	}
+	return header, batch, nil
+}
+
+func (is *IndexerService) isIndexed(height int64) bool {
+	is.mtx.Lock()
+	defer is.mtx.Unlock()
+	_, ok := is.indexedAbove[height]
+	return ok || height <= is.indexedHeight
+}
+
+// markIndexed records that the block at height was indexed, advancing the
+// indexed height if all the blocks below it were indexed, or if the missing
+// ones are no longer stored.
+func (is *IndexerService) markIndexed(height int64) {
+	if is.progress == nil {
+		return
+	}
+
+	is.mtx.Lock()
+	defer is.mtx.Unlock()
+	indexedHeight := is.indexedHeight
+	if base := is.blockSource.Base(); indexedHeight < base-1 {
+		indexedHeight = base - 1
+	}
+	if height <= indexedHeight {
+		return
+	}
+	is.indexedAbove[height] = struct{}{}
+	for {
+		if _, ok := is.indexedAbove[indexedHeight+1]; !ok {
+			break
+		}
+		delete(is.indexedAbove, indexedHeight+1)
+		indexedHeight++
+	}
+	for h := range is.indexedAbove {
+		if h <= indexedHeight {
+			delete(is.indexedAbove, h)
+		}
+	}
+	if indexedHeight > is.indexedHeight {
+		is.indexedHeight = indexedHeight
+		is.saveIndexedHeight(indexedHeight)
+	}
+}
@@ -3,9 +3,11 @@ package txindex
 import (
 	"context"
 	"errors"
+	"sort"
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/state/indexer"
 )
 
 // XXX/TODO: These types should be moved to the indexer package.
@@ -22,6 +24,47 @@ type TxIndexer interface {
 	Search(ctx context.Context, q *query.Query) ([]*abci.TxResult, error)
 }
 
+// PageSearcher is implemented by the TxIndexers able to search for a page of
+// results without loading all the matching transactions.
+type PageSearcher interface {
+	// SearchPage returns the transactions of page matching q, and the position
+	// of the last one if more results follow.
+	SearchPage(ctx context.Context, q *query.Query, page indexer.Page) ([]*abci.TxResult, *indexer.Cursor, error)
+}
+
+// AddressIndexer is implemented by the TxIndexers keeping a secondary index
+// of the transactions by the addresses found in their events.
+type AddressIndexer interface {
+	// AddressTxs returns the transactions of page involving address, and the
+	// position of the last one if more results follow. It returns
+	// ErrAddressIndexDisabled if no addresses are indexed.
+	AddressTxs(ctx context.Context, address string, page indexer.Page) ([]*abci.TxResult, *indexer.Cursor, error)
+}
+
+// SearchPage searches txi for the transactions of page matching q. Unless txi
+// is a PageSearcher, all the matching transactions are loaded and sorted.
+func SearchPage(
+	ctx context.Context,
+	txi TxIndexer,
+	q *query.Query,
+	page indexer.Page,
+) ([]*abci.TxResult, *indexer.Cursor, error) {
+	if ps, ok := txi.(PageSearcher); ok {
+		return ps.SearchPage(ctx, q, page)
+	}
+
+	results, err := txi.Search(ctx, q)
+	if err != nil {
+		return nil, nil, err
+	}
+	cursor := func(i int) indexer.Cursor {
+		return indexer.Cursor{Height: results[i].Height, Index: results[i].Index}
+	}
+	sort.Slice(results, func(i, j int) bool { return page.Less(cursor(i), cursor(j)) })
+	start, end, next := page.Slice(len(results), cursor)
+	return results[start:end], next, nil
+}
+
 // Batch groups together multiple Index operations to be performed at the same time.
 // NOTE: Batch is NOT thread-safe and must not be modified after starting its execution.
 type Batch struct {
@@ -48,3 +91,7 @@ func (b *Batch) Size() int {
 
 // ErrorEmptyHash indicates empty hash
 var ErrorEmptyHash = errors.New("transaction hash cannot be empty")
+
+// ErrAddressIndexDisabled is returned when searching for the transactions of
+// an address without an address index.
+var ErrAddressIndexDisabled = errors.New("address index is disabled")
@@ -2,8 +2,12 @@ package txindex
 
 import (
 	"context"
+	"fmt"
+
+	dbm "github.com/cometbft/cometbft-db"
 
 	"github.com/tendermint/tendermint/libs/service"
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
 	"github.com/tendermint/tendermint/state/indexer"
 	"github.com/tendermint/tendermint/types"
 )
@@ -12,6 +16,10 @@ import (
 
 const (
 	subscriber = "IndexerService"
+
+	// pruneInterval is the number of heights the retain height of
+	// retainBlocks must advance by before the indexes are pruned again.
+	pruneInterval = 100
 )
 
 var tracksStationType string
@@ -25,6 +33,31 @@ type IndexerService struct {
 	blockIdxr        indexer.BlockIndexer
 	eventBus         *types.EventBus
 	terminateOnError bool
+
+	blockStore   BlockStore
+	retainBlocks int64
+	retainHeight int64      // below which the indexes were pruned
+	pruneCh      chan int64 // the last indexed height, once pruning is due
+
+	progress    dbm.DB
+	blockSource BlockSource
+	responses   ABCIResponsesStore
+
+	// indexMtx serializes the indexing of the live and the missing blocks,
+	// the indexers not supporting concurrent indexing.
+	indexMtx   cmtsync.Mutex
+	podsHeight int64 // up to which the pods were indexed, guarded by indexMtx
+
+	mtx           cmtsync.Mutex
+	indexedHeight int64              // up to which all the blocks were indexed
+	indexedAbove  map[int64]struct{} // heights indexed above indexedHeight
+	catchingUp    bool
+}
+
+// BlockStore is the block store the indexes are pruned along with.
+type BlockStore interface {
+	// Base returns the first height of the stored blocks.
+	Base() int64
 }
 
 // NewIndexerService returns a new service instance.
@@ -37,11 +70,26 @@ func NewIndexerService(
 ) *IndexerService {
 	tracksStationType = stationType
 
-	is := &IndexerService{txIdxr: txIdxr, blockIdxr: blockIdxr, eventBus: eventBus, terminateOnError: terminateOnError}
+	is := &IndexerService{
+		txIdxr:           txIdxr,
+		blockIdxr:        blockIdxr,
+		eventBus:         eventBus,
+		terminateOnError: terminateOnError,
+		indexedAbove:     make(map[int64]struct{}),
+		pruneCh:          make(chan int64, 1),
+	}
 	is.BaseService = *service.NewBaseService(nil, "IndexerService", is)
 	return is
 }
 
+// SetPruning enables the pruning of the indexers implementing
+// indexer.Pruner. The heights whose blocks were pruned from blockStore are
+// pruned, along with all but the last retainBlocks heights if not 0.
+func (is *IndexerService) SetPruning(blockStore BlockStore, retainBlocks int64) {
+	is.blockStore = blockStore
+	is.retainBlocks = retainBlocks
+}
+
 // OnStart implements service.Service by subscribing for all transactions
 // and indexing them by events.
 func (is *IndexerService) OnStart() error {
@@ -61,6 +109,15 @@ func (is *IndexerService) OnStart() error {
 		return err
 	}
 
+	if is.progress != nil {
+		if err := is.startCatchUp(); err != nil {
+			return err
+		}
+	}
+	if is.blockStore != nil || is.retainBlocks > 0 {
+		go is.pruneRoutine()
+	}
+
 	go func() {
 		is.Logger.Info("tracks pods are enabled", "tracksStationType", tracksStationType)
 		for {
@@ -68,7 +125,7 @@ func (is *IndexerService) OnStart() error {
 			eventDataHeader := msg.Data().(types.EventDataNewBlockHeader)
 			height := eventDataHeader.Header.Height
 			batch := NewBatch(eventDataHeader.NumTxs)
-			numTxs := eventDataHeader.NumTxs
+			complete := true
 
 			for i := int64(0); i < eventDataHeader.NumTxs; i++ {
 				msg2 := <-txsSub.Out()
@@ -81,6 +138,7 @@ func (is *IndexerService) OnStart() error {
 						"index", txResult.Index,
 						"err", err,
 					)
+					complete = false
 
 					if is.terminateOnError {
 						if err := is.Stop(); err != nil {
@@ -91,7 +149,8 @@ func (is *IndexerService) OnStart() error {
 				}
 			}
 
-			if err := is.blockIdxr.Index(eventDataHeader); err != nil {
+			is.retryMissing(height)
+			if err := is.indexOnce(eventDataHeader, batch, complete); err != nil {
 				is.Logger.Error("failed to index block", "height", height, "err", err)
 				if is.terminateOnError {
 					if err := is.Stop(); err != nil {
@@ -99,39 +158,141 @@ func (is *IndexerService) OnStart() error {
 					}
 					return
 				}
-			} else {
-				is.Logger.Info("indexed block exents", "height", height)
 			}
 
-			if err = is.txIdxr.AddBatch(batch); err != nil {
-				is.Logger.Error("failed to index block txs", "height", height, "err", err)
-				if is.terminateOnError {
-					if err := is.Stop(); err != nil {
-						is.Logger.Error("failed to stop", "err", err)
-					}
-					return
-				}
-			} else {
-				is.Logger.Debug("indexed transactions", "height", height, "num_txs", eventDataHeader.NumTxs)
-			}
-
-			// index pods in database
-			if err = is.txIdxr.AddPod(batch, tracksStationType); err != nil {
-				is.Logger.Error("failed to index block txs", "height", height, "err", err)
-				if is.terminateOnError {
-					if err := is.Stop(); err != nil {
-						is.Logger.Error("failed to stop", "err", err)
-					}
-					return
-				}
-			} else {
-				is.Logger.Debug("indexed transactions", "height", height, "num_txs", numTxs)
-			}
+			is.schedulePrune(height)
 		}
 	}()
 	return nil
 }
 
+// indexOnce indexes the block, unless it was already indexed, and marks it
+// as indexed if the batch holds all its txs.
+func (is *IndexerService) indexOnce(header types.EventDataNewBlockHeader, batch *Batch, complete bool) error {
+	is.indexMtx.Lock()
+	defer is.indexMtx.Unlock()
+
+	height := header.Header.Height
+	if is.isIndexed(height) {
+		return nil
+	}
+	if err := is.indexBlock(header, batch); err != nil {
+		return err
+	}
+	if complete {
+		is.markIndexed(height)
+		is.indexPods(height, batch)
+	}
+	return nil
+}
+
+// indexBlock indexes the events of a block and its txs, and its pods unless
+// the indexed heights are tracked, the pods being indexed by indexPods then.
+func (is *IndexerService) indexBlock(header types.EventDataNewBlockHeader, batch *Batch) error {
+	height := header.Header.Height
+	if err := is.blockIdxr.Index(header); err != nil {
+		return fmt.Errorf("indexing block events: %w", err)
+	}
+	is.Logger.Info("indexed block exents", "height", height)
+
+	if err := is.txIdxr.AddBatch(batch); err != nil {
+		return fmt.Errorf("indexing block txs: %w", err)
+	}
+	is.Logger.Debug("indexed transactions", "height", height, "num_txs", header.NumTxs)
+
+	if is.progress != nil {
+		return nil
+	}
+	// index pods in database
+	if err := is.txIdxr.AddPod(batch, tracksStationType); err != nil {
+		return fmt.Errorf("indexing block pods: %w", err)
+	}
+	return nil
+}
+
+// schedulePrune hands the indexed height over to pruneRoutine, replacing the
+// previous one if it was not pruned yet, for indexing not to wait on pruning.
+func (is *IndexerService) schedulePrune(height int64) {
+	if is.blockStore == nil && is.retainBlocks == 0 {
+		return
+	}
+	for {
+		select {
+		case is.pruneCh <- height:
+			return
+		default:
+		}
+		select {
+		case <-is.pruneCh:
+		default:
+		}
+	}
+}
+
+// pruneRoutine prunes the indexes along with the indexed heights scheduled,
+// until the service stops.
+func (is *IndexerService) pruneRoutine() {
+	for {
+		select {
+		case height := <-is.pruneCh:
+			is.prune(height)
+		case <-is.Quit():
+			return
+		}
+	}
+}
+
+// prune prunes the indexes once the height has been indexed, if the retain
+// height of retainBlocks advanced enough since the last pruning, or as soon as
+// blocks were pruned from the block store: the txs of the pruned blocks can't
+// be proved anymore.
+func (is *IndexerService) prune(height int64) {
+	var retainHeight int64
+	if is.retainBlocks > 0 {
+		retainHeight = height - is.retainBlocks + 1
+		if is.retainHeight > 0 && retainHeight < is.retainHeight+pruneInterval {
+			retainHeight = is.retainHeight
+		}
+	}
+	if is.blockStore != nil {
+		if base := is.blockStore.Base(); base > retainHeight {
+			retainHeight = base
+		}
+	}
+	if retainHeight <= 1 || retainHeight <= is.retainHeight {
+		return
+	}
+
+	// The txs are pruned first, as they may refer to their block.
+	for _, idxr := range []struct {
+		name string
+		idxr interface{}
+	}{{"txs", is.txIdxr}, {"blocks", is.blockIdxr}} {
+		pruner, ok := idxr.idxr.(indexer.Pruner)
+		if !ok {
+			continue
+		}
+		pruned, err := pruner.Prune(retainHeight)
+		if err != nil {
+			// Pruning is retried at the next height.
+			is.Logger.Error("failed to prune indexed "+idxr.name, "retain_height", retainHeight, "err", err)
+			return
+		}
+		is.Logger.Info("pruned indexed "+idxr.name, "retain_height", retainHeight, "pruned", pruned)
+	}
+	is.mtx.Lock()
+	is.retainHeight = retainHeight
+	is.mtx.Unlock()
+}
+
+// PrunedHeight returns the height below which the indexes were last pruned,
+// or 0 if they were not pruned since the service started.
+func (is *IndexerService) PrunedHeight() int64 {
+	is.mtx.Lock()
+	defer is.mtx.Unlock()
+	return is.retainHeight
+}
+
 // OnStop implements service.Service by unsubscribing from all transactions.
 func (is *IndexerService) OnStop() {
 	if is.eventBus.IsRunning() {
@@ -1,6 +1,10 @@
 package txindex_test
 
 import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -9,6 +13,8 @@ import (
 
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/log"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+	cmtstate "github.com/tendermint/tendermint/proto/tendermint/state"
 	blockidxkv "github.com/tendermint/tendermint/state/indexer/block/kv"
 	"github.com/tendermint/tendermint/state/txindex"
 	"github.com/tendermint/tendermint/state/txindex/kv"
@@ -32,7 +38,7 @@ func TestIndexerServiceIndexesBlocks(t *testing.T) {
 	txIndexer := kv.NewTxIndex(store)
 	blockIndexer := blockidxkv.New(db.NewPrefixDB(store, []byte("block_events")))
 
-	service := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, false)
+	service := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, "", false)
 	service.SetLogger(log.TestingLogger())
 	err = service.Start()
 	require.NoError(t, err)
@@ -79,3 +85,407 @@ func TestIndexerServiceIndexesBlocks(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, txResult2, res)
 }
+
+func TestIndexerServiceCatchUp(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Blocks 1 to 3 were stored but only block 1 was indexed.
+	blocks := newTestBlocks(3)
+	store := db.NewMemDB()
+	txIndexer := kv.NewTxIndex(store)
+	blockIndexer := blockidxkv.New(db.NewPrefixDB(store, []byte("block_events")))
+	progress := db.NewPrefixDB(store, []byte("indexer_progress/"))
+	require.NoError(t, progress.Set([]byte("indexed_height"), []byte("1")))
+
+	service := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, "", false)
+	service.SetLogger(log.TestingLogger())
+	service.SetCatchUp(progress, blocks, blocks)
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	require.Eventually(t, func() bool {
+		height, ok := service.IndexedHeight()
+		return ok && height == 3 && !service.CatchingUp()
+	}, time.Second, 10*time.Millisecond)
+
+	ok, err := blockIndexer.Has(1)
+	require.NoError(t, err)
+	require.False(t, ok, "the indexed heights must not be indexed again")
+	for height := int64(2); height <= 3; height++ {
+		ok, err := blockIndexer.Has(height)
+		require.NoError(t, err)
+		require.True(t, ok)
+
+		res, err := txIndexer.Get(blocks.blocks[height].Txs[0].Hash())
+		require.NoError(t, err)
+		require.NotNil(t, res)
+		require.Equal(t, height, res.Height)
+	}
+
+	// The blocks indexed live advance the indexed height.
+	require.NoError(t, eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{
+		Header: types.Header{Height: 4},
+	}))
+	require.Eventually(t, func() bool {
+		height, _ := service.IndexedHeight()
+		return height == 4
+	}, time.Second, 10*time.Millisecond)
+
+	bz, err := progress.Get([]byte("indexed_height"))
+	require.NoError(t, err)
+	require.Equal(t, "4", string(bz))
+}
+
+func TestIndexerServiceCatchUpWhileIndexingLive(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	const stored, live = 1000, 10
+	blocks := newTestBlocks(stored)
+	store := db.NewMemDB()
+	blockIndexer := blockidxkv.New(db.NewPrefixDB(store, []byte("block_events")))
+	progress := db.NewPrefixDB(store, []byte("indexer_progress/"))
+	require.NoError(t, progress.Set([]byte("indexed_height"), []byte("0")))
+
+	service := txindex.NewIndexerService(kv.NewTxIndex(store), blockIndexer, eventBus, "", false)
+	service.SetLogger(log.TestingLogger())
+	service.SetCatchUp(progress, blocks, blocks)
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// The blocks committed meanwhile are indexed along with the missing ones.
+	for height := int64(stored + 1); height <= stored+live; height++ {
+		require.NoError(t, eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{
+			Header:         types.Header{Height: height},
+			ResultEndBlock: abci.ResponseEndBlock{Events: testBlockEvents(height)},
+		}))
+	}
+	require.Eventually(t, func() bool {
+		height, _ := service.IndexedHeight()
+		return height == stored+live && !service.CatchingUp()
+	}, 10*time.Second, 10*time.Millisecond)
+
+	// Each event is matched to its own block.
+	results, err := blockIndexer.Search(context.Background(),
+		query.MustParse(fmt.Sprintf("end.height > %d AND end.odd = 'true' AND match.events = 1", stored-live)))
+	require.NoError(t, err)
+	require.Len(t, results, live)
+}
+
+func TestIndexerServiceIndexesPodsOnce(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Blocks 2 and 3 were indexed live, along with their pods, after block
+	// 1 failed to be.
+	blocks := newTestBlocks(4)
+	store := db.NewMemDB()
+	txIndexer := &podsTxIndex{TxIndex: kv.NewTxIndex(store)}
+	blockIndexer := blockidxkv.New(db.NewPrefixDB(store, []byte("block_events")))
+	progress := db.NewPrefixDB(store, []byte("indexer_progress/"))
+	require.NoError(t, progress.Set([]byte("indexed_height"), []byte("0")))
+	require.NoError(t, progress.Set([]byte("pods_height"), []byte("3")))
+
+	service := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, "", false)
+	service.SetLogger(log.TestingLogger())
+	service.SetCatchUp(progress, blocks, blocks)
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	require.Eventually(t, func() bool {
+		height, _ := service.IndexedHeight()
+		return height == 4 && !service.CatchingUp()
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []int64{4}, txIndexer.podHeights())
+}
+
+func TestIndexerServiceRetriesMissingBlocks(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	blocks := newTestBlocks(4)
+	store := db.NewMemDB()
+	txIndexer := &podsTxIndex{TxIndex: kv.NewTxIndex(store), failHeight: 2}
+	blockIndexer := blockidxkv.New(db.NewPrefixDB(store, []byte("block_events")))
+	progress := db.NewPrefixDB(store, []byte("indexer_progress/"))
+	require.NoError(t, progress.Set([]byte("indexed_height"), []byte("0")))
+
+	service := txindex.NewIndexerService(txIndexer, blockIndexer, eventBus, "", false)
+	service.SetLogger(log.TestingLogger())
+	// The blocks are stored as they are committed.
+	committed := &committedBlocks{testBlocks: blocks}
+	service.SetCatchUp(progress, committed, blocks)
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	// Block 2 fails to be indexed once, and is indexed again along with
+	// block 3, before it.
+	for height := int64(1); height <= 4; height++ {
+		committed.commit(height)
+		block := blocks.blocks[height]
+		require.NoError(t, eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{
+			Header:         block.Header,
+			NumTxs:         1,
+			ResultEndBlock: abci.ResponseEndBlock{Events: testBlockEvents(height)},
+		}))
+		require.NoError(t, eventBus.PublishEventTx(types.EventDataTx{TxResult: abci.TxResult{
+			Height: height,
+			Tx:     block.Txs[0],
+			Result: abci.ResponseDeliverTx{Code: abci.CodeTypeOK},
+		}}))
+	}
+	require.Eventually(t, func() bool {
+		height, _ := service.IndexedHeight()
+		return height == 4
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, []int64{1, 2, 3, 4}, txIndexer.podHeights())
+}
+
+func TestIndexerServicePrunes(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	store := db.NewMemDB()
+	blockIndexer := blockidxkv.New(db.NewPrefixDB(store, []byte("block_events")))
+	service := txindex.NewIndexerService(kv.NewTxIndex(store), blockIndexer, eventBus, "", false)
+	service.SetLogger(log.TestingLogger())
+	service.SetPruning(nil, 2)
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	for height := int64(1); height <= 3; height++ {
+		require.NoError(t, eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{
+			Header: types.Header{Height: height},
+		}))
+	}
+	require.Eventually(t, func() bool {
+		return service.PrunedHeight() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	for height := int64(1); height <= 3; height++ {
+		ok, err := blockIndexer.Has(height)
+		require.NoError(t, err)
+		require.Equal(t, height >= 2, ok)
+	}
+}
+
+func TestIndexerServicePrunesWithBlocks(t *testing.T) {
+	eventBus := types.NewEventBus()
+	eventBus.SetLogger(log.TestingLogger())
+	require.NoError(t, eventBus.Start())
+	t.Cleanup(func() {
+		if err := eventBus.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	store := db.NewMemDB()
+	blockIndexer := blockidxkv.New(db.NewPrefixDB(store, []byte("block_events")))
+	service := txindex.NewIndexerService(kv.NewTxIndex(store), blockIndexer, eventBus, "", false)
+	service.SetLogger(log.TestingLogger())
+	blockStore := &prunedBlockStore{}
+	blockStore.base.Store(2)
+	// The latest 100 heights are kept, unless their blocks were pruned.
+	service.SetPruning(blockStore, 100)
+	require.NoError(t, service.Start())
+	t.Cleanup(func() {
+		if err := service.Stop(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	publish := func(height int64) {
+		require.NoError(t, eventBus.PublishEventNewBlockHeader(types.EventDataNewBlockHeader{
+			Header: types.Header{Height: height},
+		}))
+	}
+	for height := int64(1); height <= 3; height++ {
+		publish(height)
+	}
+	require.Eventually(t, func() bool {
+		return service.PrunedHeight() == 2
+	}, time.Second, 10*time.Millisecond)
+
+	// The indexes are pruned as soon as more blocks are pruned.
+	blockStore.base.Store(3)
+	publish(4)
+	require.Eventually(t, func() bool {
+		return service.PrunedHeight() == 3
+	}, time.Second, 10*time.Millisecond)
+
+	for height := int64(1); height <= 4; height++ {
+		ok, err := blockIndexer.Has(height)
+		require.NoError(t, err)
+		require.Equal(t, height >= 3, ok)
+	}
+}
+
+// prunedBlockStore is a block store whose blocks below base were pruned.
+type prunedBlockStore struct {
+	base atomic.Int64
+}
+
+func (s *prunedBlockStore) Base() int64 { return s.base.Load() }
+
+// testBlocks stores blocks and their ABCI responses.
+type testBlocks struct {
+	blocks map[int64]*types.Block
+}
+
+func newTestBlocks(height int64) *testBlocks {
+	blocks := &testBlocks{blocks: make(map[int64]*types.Block)}
+	for h := int64(1); h <= height; h++ {
+		blocks.blocks[h] = &types.Block{
+			Header: types.Header{Height: h},
+			Data:   types.Data{Txs: types.Txs{types.Tx(fmt.Sprintf("tx%d", h))}},
+		}
+	}
+	return blocks
+}
+
+func (b *testBlocks) Base() int64 { return 1 }
+
+func (b *testBlocks) Height() int64 { return int64(len(b.blocks)) }
+
+func (b *testBlocks) LoadBlock(height int64) *types.Block { return b.blocks[height] }
+
+func (b *testBlocks) LoadABCIResponses(height int64) (*cmtstate.ABCIResponses, error) {
+	block, ok := b.blocks[height]
+	if !ok {
+		return nil, fmt.Errorf("no ABCI responses at height %d", height)
+	}
+	responses := &cmtstate.ABCIResponses{
+		BeginBlock: &abci.ResponseBeginBlock{},
+		EndBlock:   &abci.ResponseEndBlock{Events: testBlockEvents(height)},
+	}
+	for range block.Txs {
+		responses.DeliverTxs = append(responses.DeliverTxs, &abci.ResponseDeliverTx{Code: abci.CodeTypeOK})
+	}
+	return responses, nil
+}
+
+// testBlockEvents returns the end block events of the test block at height.
+func testBlockEvents(height int64) []abci.Event {
+	return []abci.Event{{
+		Type: "end",
+		Attributes: []abci.EventAttribute{
+			{Key: []byte("height"), Value: []byte(fmt.Sprint(height)), Index: true},
+			{Key: []byte("odd"), Value: []byte(fmt.Sprint(height%2 == 1)), Index: true},
+		},
+	}}
+}
+
+// committedBlocks exposes the test blocks up to the committed height.
+type committedBlocks struct {
+	*testBlocks
+
+	mtx    sync.Mutex
+	height int64
+}
+
+func (b *committedBlocks) commit(height int64) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.height = height
+}
+
+func (b *committedBlocks) Height() int64 {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.height
+}
+
+func (b *committedBlocks) LoadBlock(height int64) *types.Block {
+	if height > b.Height() {
+		return nil
+	}
+	return b.testBlocks.LoadBlock(height)
+}
+
+// podsTxIndex records the heights of the pods indexed, and fails to index
+// the txs at failHeight once.
+type podsTxIndex struct {
+	*kv.TxIndex
+	failHeight int64
+
+	mtx     sync.Mutex
+	failed  bool
+	heights []int64
+}
+
+func (txi *podsTxIndex) AddBatch(b *txindex.Batch) error {
+	txi.mtx.Lock()
+	fail := !txi.failed && len(b.Ops) > 0 && b.Ops[0].Height == txi.failHeight
+	txi.failed = txi.failed || fail
+	txi.mtx.Unlock()
+	if fail {
+		return fmt.Errorf("failed to index height %d", txi.failHeight)
+	}
+	return txi.TxIndex.AddBatch(b)
+}
+
+func (txi *podsTxIndex) AddPod(b *txindex.Batch, stationType string) error {
+	txi.mtx.Lock()
+	defer txi.mtx.Unlock()
+	for _, op := range b.Ops {
+		txi.heights = append(txi.heights, op.Height)
+	}
+	return txi.TxIndex.AddPod(b, stationType)
+}
+
+func (txi *podsTxIndex) podHeights() []int64 {
+	txi.mtx.Lock()
+	defer txi.mtx.Unlock()
+	return append([]int64(nil), txi.heights...)
+}
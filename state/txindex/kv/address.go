@@ -0,0 +1,182 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/google/orderedcode"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/state/indexer"
+	"github.com/tendermint/tendermint/state/txindex"
+)
+
+// addressKeyPrefix starts the keys of the address index, followed by the
+// address, the height and the index of each tx, such that the txs of an
+// address are ordered by position.
+const addressKeyPrefix = "address"
+
+var _ txindex.AddressIndexer = (*TxIndex)(nil)
+
+// WithAddressIndex indexes the txs by the values of their event attributes
+// with one of the given composite keys (e.g. "transfer.sender"), regardless of
+// their index flag, in order to list the txs of an address with AddressTxs.
+// Composite keys may end with '*' to match the keys starting with a prefix.
+func WithAddressIndex(compositeKeys []string) Option {
+	return func(txi *TxIndex) {
+		if len(compositeKeys) > 0 {
+			txi.addresses = indexer.NewEventFilter(compositeKeys, nil)
+		}
+	}
+}
+
+// AddressTxs returns the txs of page involving address, reading the address
+// index from the position of the page, and the position of the last tx if
+// more follow.
+//
+// AddressTxs will exit early and return the txs fetched so far, when a
+// message is received on the context chan.
+func (txi *TxIndex) AddressTxs(
+	ctx context.Context,
+	address string,
+	page indexer.Page,
+) ([]*abci.TxResult, *indexer.Cursor, error) {
+	if txi.addresses == nil {
+		return nil, nil, txindex.ErrAddressIndexDisabled
+	}
+
+	prefix, err := orderedcode.Append(nil, addressKeyPrefix, address)
+	if err != nil {
+		return nil, nil, err
+	}
+	start, end := prefix, prefixEnd(prefix)
+	var it dbm.Iterator
+	if page.Desc {
+		if page.After != nil {
+			if end, err = addressKey(address, page.After.Height, page.After.Index); err != nil {
+				return nil, nil, err
+			}
+		}
+		it, err = txi.store.ReverseIterator(start, end)
+	} else {
+		if page.After != nil {
+			if start, err = addressKey(address, page.After.Height, page.After.Index); err != nil {
+				return nil, nil, err
+			}
+		}
+		it, err = txi.store.Iterator(start, end)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+
+	var (
+		results []*abci.TxResult
+		skip    = page.Skip
+	)
+	for ; it.Valid(); it.Next() {
+		select {
+		case <-ctx.Done():
+			return results, nil, nil
+		default:
+		}
+
+		var (
+			prefix, addr  string
+			height, index int64
+		)
+		if _, err := orderedcode.Parse(string(it.Key()), &prefix, &addr, &height, &index); err != nil {
+			return nil, nil, fmt.Errorf("invalid address index key %X: %w", it.Key(), err)
+		}
+		position := indexer.Cursor{Height: height, Index: uint32(index)}
+		if page.After != nil && !page.Less(*page.After, position) {
+			continue
+		}
+
+		res, err := txi.Get(it.Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		// Skip the txs indexed again at another position.
+		if res == nil || res.Height != position.Height || res.Index != position.Index {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		if page.Limit > 0 && len(results) == page.Limit {
+			last := results[len(results)-1]
+			return results, &indexer.Cursor{Height: last.Height, Index: last.Index}, nil
+		}
+		results = append(results, res)
+	}
+	return results, nil, it.Error()
+}
+
+// indexAddresses adds to b the address index keys of result.
+func (txi *TxIndex) indexAddresses(result *abci.TxResult, hash []byte, b dbm.Batch) error {
+	keys, err := txi.addressKeys(result)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := b.Set(key, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addressKeys returns the address index keys of result, one per address.
+func (txi *TxIndex) addressKeys(result *abci.TxResult) ([][]byte, error) {
+	if txi.addresses == nil {
+		return nil, nil
+	}
+
+	var keys [][]byte
+	seen := make(map[string]struct{})
+	for _, event := range result.Result.Events {
+		if len(event.Type) == 0 {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if len(attr.Key) == 0 || len(attr.Value) == 0 {
+				continue
+			}
+			if !txi.addresses.Allows(fmt.Sprintf("%s.%s", event.Type, attr.Key)) {
+				continue
+			}
+			if _, ok := seen[string(attr.Value)]; ok {
+				continue
+			}
+			seen[string(attr.Value)] = struct{}{}
+
+			key, err := addressKey(string(attr.Value), result.Height, result.Index)
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+func addressKey(address string, height int64, index uint32) ([]byte, error) {
+	return orderedcode.Append(nil, addressKeyPrefix, address, height, int64(index))
+}
+
+// prefixEnd returns the first key following all the keys starting with
+// prefix, or nil if there is none.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	return nil
+}
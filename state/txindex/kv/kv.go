@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -22,22 +24,52 @@ import (
 const (
 	tagKeySeparator   = "/"
 	eventSeqSeparator = "$es$"
+
+	// pruneBatchSize is the number of pruned txs written at once.
+	pruneBatchSize = 1000
 )
 
-var _ txindex.TxIndexer = (*TxIndex)(nil)
+var (
+	_ txindex.TxIndexer    = (*TxIndex)(nil)
+	_ txindex.PageSearcher = (*TxIndex)(nil)
+	_ txindex.Aggregator   = (*TxIndex)(nil)
+	_ indexer.Pruner       = (*TxIndex)(nil)
+	_ indexer.Rewinder     = (*TxIndex)(nil)
+
+	// retainHeightKey holds the height below which the index was pruned.
+	retainHeightKey = []byte("retainHeight")
+)
 
 // TxIndex is the simplest possible indexer, backed by key-value storage (levelDB).
 type TxIndex struct {
 	store dbm.DB
 	// Number the events in the event list
 	eventSeq int64
+	// Selects the event attributes indexed
+	filter *indexer.EventFilter
+	// Selects the event attributes whose values are indexed as addresses
+	addresses *indexer.EventFilter
+}
+
+// Option sets an optional parameter on the TxIndex.
+type Option func(*TxIndex)
+
+// WithEventFilter restricts the event attributes indexed to the ones allowed
+// by filter. The attributes are still only indexed if the application set
+// their index flag.
+func WithEventFilter(filter *indexer.EventFilter) Option {
+	return func(txi *TxIndex) { txi.filter = filter }
 }
 
 // NewTxIndex creates new KV indexer.
-func NewTxIndex(store dbm.DB) *TxIndex {
-	return &TxIndex{
+func NewTxIndex(store dbm.DB, options ...Option) *TxIndex {
+	txi := &TxIndex{
 		store: store,
 	}
+	for _, option := range options {
+		option(txi)
+	}
+	return txi
 }
 
 // Get gets transaction from the TxIndex storage and returns it or nil if the
@@ -98,12 +130,24 @@ func (txi *TxIndex) AddBatch(b *txindex.Batch) error {
 			return err
 		}
 
+		// index by addresses
+		err = txi.indexAddresses(result, hash, storeBatch)
+		if err != nil {
+			return err
+		}
+
 		// index by height (always)
 		err = storeBatch.Set(keyForHeight(result), hash)
 		if err != nil {
 			return err
 		}
 
+		// index by position (always)
+		err = txi.indexPosition(result, hash, storeBatch)
+		if err != nil {
+			return err
+		}
+
 		rawBytes, err := proto.Marshal(result)
 		if err != nil {
 			return err
@@ -180,12 +224,24 @@ func (txi *TxIndex) Index(result *abci.TxResult) error {
 		return err
 	}
 
+	// index by addresses
+	err = txi.indexAddresses(result, hash, b)
+	if err != nil {
+		return err
+	}
+
 	// index by height (always)
 	err = b.Set(keyForHeight(result), hash)
 	if err != nil {
 		return err
 	}
 
+	// index by position (always)
+	err = txi.indexPosition(result, hash, b)
+	if err != nil {
+		return err
+	}
+
 	rawBytes, err := proto.Marshal(result)
 	if err != nil {
 		return err
@@ -214,7 +270,7 @@ func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store dbm.Ba
 
 			// index if `index: true` is set
 			compositeTag := fmt.Sprintf("%s.%s", event.Type, string(attr.Key))
-			if attr.GetIndex() {
+			if attr.GetIndex() && txi.filter.Allows(compositeTag) {
 				err := store.Set(keyForEvent(compositeTag, attr.Value, result, txi.eventSeq), hash)
 				if err != nil {
 					return err
@@ -226,6 +282,260 @@ func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store dbm.Ba
 	return nil
 }
 
+// Prune removes the txs indexed below retainHeight, along with their events,
+// and returns their number. The heights are pruned from the one the previous
+// pruning stopped at, or else from the lowest indexed height, by their height
+// keys, and the removals are written by batches of about pruneBatchSize txs.
+func (txi *TxIndex) Prune(retainHeight int64) (uint64, error) {
+	from, err := txi.prunedHeight()
+	if err != nil {
+		return 0, err
+	}
+	if from >= retainHeight {
+		return 0, nil
+	}
+
+	b := txi.store.NewBatch()
+	defer func() { b.Close() }()
+	var pruned, pending uint64
+	for height := from; height < retainHeight; height++ {
+		n, err := txi.pruneHeight(b, height)
+		if err != nil {
+			return pruned, err
+		}
+		pending += n
+		if pending < pruneBatchSize && height < retainHeight-1 {
+			continue
+		}
+
+		// The height pruning stopped at is written along with the removals.
+		if err := b.Set(retainHeightKey, []byte(strconv.FormatInt(height+1, 10))); err != nil {
+			return pruned, err
+		}
+		if err := b.Write(); err != nil {
+			return pruned, err
+		}
+		b.Close()
+		b = txi.store.NewBatch()
+		pruned += pending
+		pending = 0
+	}
+	return pruned, nil
+}
+
+// prunedHeight returns the height the previous pruning stopped at, or the
+// lowest indexed height if the index was never pruned. Without any tx
+// indexed, it returns math.MaxInt64.
+func (txi *TxIndex) prunedHeight() (int64, error) {
+	bz, err := txi.store.Get(retainHeightKey)
+	if err != nil {
+		return 0, err
+	}
+	if bz != nil {
+		height, err := strconv.ParseInt(string(bz), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid retain height %q: %w", bz, err)
+		}
+		return height, nil
+	}
+
+	it, err := dbm.IteratePrefix(txi.store, startKey(types.TxHeightKey))
+	if err != nil {
+		return 0, err
+	}
+	defer it.Close()
+	lowest := int64(math.MaxInt64)
+	for ; it.Valid(); it.Next() {
+		if h, _, err := extractPositionFromKey(it.Key()); err == nil && h < lowest {
+			lowest = h
+		}
+	}
+	return lowest, it.Error()
+}
+
+// pruneHeight adds to b the removal of the txs indexed at height, and returns
+// their number. The event and address keys of a tx are recomputed from its
+// result, and its position keys from its height keys.
+func (txi *TxIndex) pruneHeight(b dbm.Batch, height int64) (uint64, error) {
+	var keys [][]byte
+	hashes := make(map[string]struct{})
+	it, err := dbm.IteratePrefix(txi.store, startKey(types.TxHeightKey, height))
+	if err != nil {
+		return 0, err
+	}
+	var positionKeys [][]byte
+	for ; it.Valid(); it.Next() {
+		keys = append(keys, it.Key())
+		hashes[string(it.Value())] = struct{}{}
+		if _, index, err := extractPositionFromKey(it.Key()); err == nil {
+			key, err := positionKey(height, index)
+			if err != nil {
+				it.Close()
+				return 0, err
+			}
+			positionKeys = append(positionKeys, key)
+		}
+	}
+	if err := it.Error(); err != nil {
+		it.Close()
+		return 0, err
+	}
+	it.Close()
+	pruned := uint64(len(keys))
+	keys = append(keys, positionKeys...)
+
+	for hash := range hashes {
+		res, err := txi.Get([]byte(hash))
+		if err != nil {
+			return 0, err
+		}
+		// Keep the txs indexed again at a later height.
+		if res == nil || res.Height != height {
+			continue
+		}
+		keys = append(keys, []byte(hash))
+
+		addressKeys, err := txi.addressKeys(res)
+		if err != nil {
+			return 0, err
+		}
+		keys = append(keys, addressKeys...)
+
+		for _, event := range res.Result.Events {
+			if len(event.Type) == 0 {
+				continue
+			}
+			for _, attr := range event.Attributes {
+				if len(attr.Key) == 0 || !attr.GetIndex() {
+					continue
+				}
+				prefix := fmt.Sprintf("%s.%s/%s/%d/%d", event.Type, attr.Key, attr.Value, res.Height, res.Index)
+				eventKeys, err := txi.eventKeys(prefix)
+				if err != nil {
+					return 0, err
+				}
+				keys = append(keys, eventKeys...)
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if err := b.Delete(key); err != nil {
+			return 0, err
+		}
+	}
+	return pruned, nil
+}
+
+// Rewind removes the txs indexed above height, along with their events, and
+// returns their number. The pods of the "evm" stations are rewound first, from
+// the removed txs.
+func (txi *TxIndex) Rewind(height int64) (uint64, error) {
+	heights, err := txi.heightsAbove(height)
+	if err != nil {
+		return 0, err
+	}
+	var results []*abci.TxResult
+	for _, h := range heights {
+		res, err := txi.heightTxs(h)
+		if err != nil {
+			return 0, err
+		}
+		results = append(results, res...)
+	}
+	if err := RewindPods(txi, results); err != nil {
+		return 0, fmt.Errorf("rewinding the pods: %w", err)
+	}
+
+	var removed uint64
+	for i := len(heights) - 1; i >= 0; i-- {
+		b := txi.store.NewBatch()
+		n, err := txi.pruneHeight(b, heights[i])
+		if err == nil {
+			err = b.WriteSync()
+		}
+		b.Close()
+		if err != nil {
+			return removed, err
+		}
+		removed += n
+	}
+	return removed, nil
+}
+
+// heightsAbove returns the heights above height at which txs are indexed, in
+// ascending order.
+func (txi *TxIndex) heightsAbove(height int64) ([]int64, error) {
+	it, err := dbm.IteratePrefix(txi.store, startKey(types.TxHeightKey))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	seen := make(map[int64]struct{})
+	var heights []int64
+	for ; it.Valid(); it.Next() {
+		h, _, err := extractPositionFromKey(it.Key())
+		if err != nil || h <= height {
+			continue
+		}
+		if _, ok := seen[h]; !ok {
+			seen[h] = struct{}{}
+			heights = append(heights, h)
+		}
+	}
+	sort.Slice(heights, func(i, j int) bool { return heights[i] < heights[j] })
+	return heights, it.Error()
+}
+
+// heightTxs returns the results of the txs indexed at height.
+func (txi *TxIndex) heightTxs(height int64) ([]*abci.TxResult, error) {
+	it, err := dbm.IteratePrefix(txi.store, startKey(types.TxHeightKey, height))
+	if err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]struct{})
+	for ; it.Valid(); it.Next() {
+		hashes[string(it.Value())] = struct{}{}
+	}
+	if err := it.Error(); err != nil {
+		it.Close()
+		return nil, err
+	}
+	it.Close()
+
+	var results []*abci.TxResult
+	for hash := range hashes {
+		res, err := txi.Get([]byte(hash))
+		if err != nil {
+			return nil, err
+		}
+		if res != nil && res.Height == height {
+			results = append(results, res)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Index < results[j].Index })
+	return results, nil
+}
+
+// eventKeys returns the keys of the events indexed with the given prefix,
+// with or without an event sequence.
+func (txi *TxIndex) eventKeys(prefix string) ([][]byte, error) {
+	it, err := dbm.IteratePrefix(txi.store, []byte(prefix))
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+
+	var keys [][]byte
+	for ; it.Valid(); it.Next() {
+		if key := string(it.Key()); key == prefix || strings.HasPrefix(key, prefix+eventSeqSeparator) {
+			keys = append(keys, it.Key())
+		}
+	}
+	return keys, it.Error()
+}
+
 // Search performs a search using the given query.
 //
 // It breaks the query into conditions (like "tx.height > 5"). For each
@@ -233,7 +543,8 @@ func (txi *TxIndex) indexEvents(result *abci.TxResult, hash []byte, store dbm.Ba
 // "tx.hash" is found, it returns tx result for it (2) for range queries it is
 // better for the client to provide both lower and upper bounds, so we are not
 // performing a full scan. Results from querying indexes are then intersected
-// and returned to the caller, in no particular order.
+// and returned to the caller, in no particular order. Queries with OR are
+// rewritten as alternatives of conditions, whose results are merged.
 //
 // Search will exit early and return any result fetched so far,
 // when a message is received on the context chan.
@@ -245,29 +556,263 @@ func (txi *TxIndex) Search(ctx context.Context, q *query.Query) ([]*abci.TxResul
 	default:
 	}
 
-	var hashesInitialized bool
-	filteredHashes := make(map[string][]byte)
+	filteredHashes, err := txi.matchQuery(ctx, q)
+	if err != nil {
+		return []*abci.TxResult{}, err
+	}
 
-	// get a list of conditions (like "tx.height > 5")
-	conditions, err := q.Conditions()
+	results := make([]*abci.TxResult, 0, len(filteredHashes))
+	resultMap := make(map[string]struct{})
+	for _, m := range filteredHashes {
+		hashString := string(m.hash)
+		if _, ok := resultMap[hashString]; ok {
+			continue
+		}
+		res, err := txi.Get(m.hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Tx{%X}: %w", m.hash, err)
+		}
+		resultMap[hashString] = struct{}{}
+		results = append(results, res)
+		// Potentially exit early.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// searchPageAll returns the transactions of page matching q, out of all the
+// matching transactions. Their positions are read from the keys of the
+// index, so that only the transactions of page are loaded.
+func (txi *TxIndex) searchPageAll(
+	ctx context.Context,
+	q *query.Query,
+	page indexer.Page,
+) ([]*abci.TxResult, *indexer.Cursor, error) {
+	filteredHashes, err := txi.matchQuery(ctx, q)
+	if err != nil {
+		return []*abci.TxResult{}, nil, err
+	}
+
+	matches := make([]txMatch, 0, len(filteredHashes))
+	resultMap := make(map[string]struct{})
+	for _, m := range filteredHashes {
+		hashString := string(m.hash)
+		if _, ok := resultMap[hashString]; ok {
+			continue
+		}
+		resultMap[hashString] = struct{}{}
+		if m.height == 0 {
+			// The key of the match did not hold its position.
+			res, err := txi.Get(m.hash)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get Tx{%X}: %w", m.hash, err)
+			} else if res == nil {
+				continue
+			}
+			m.height, m.index = res.Height, res.Index
+		}
+		matches = append(matches, m)
+	}
+
+	cursor := func(i int) indexer.Cursor {
+		return indexer.Cursor{Height: matches[i].height, Index: matches[i].index}
+	}
+	sort.Slice(matches, func(i, j int) bool { return page.Less(cursor(i), cursor(j)) })
+	start, end, next := page.Slice(len(matches), cursor)
+
+	results := make([]*abci.TxResult, 0, end-start)
+	for _, m := range matches[start:end] {
+		res, err := txi.Get(m.hash)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get Tx{%X}: %w", m.hash, err)
+		}
+		if res != nil {
+			results = append(results, res)
+		}
+		// Potentially exit early.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return results, next, nil
+}
+
+// Count returns the number of txs matching q, without loading them.
+func (txi *TxIndex) Count(ctx context.Context, q *query.Query) (int, error) {
+	filteredHashes, err := txi.matchQuery(ctx, q)
+	if err != nil {
+		return 0, err
+	}
+
+	resultMap := make(map[string]struct{}, len(filteredHashes))
+	for _, m := range filteredHashes {
+		resultMap[string(m.hash)] = struct{}{}
+	}
+	return len(resultMap), nil
+}
+
+// Aggregate adds the txs matching q to h, along with the integer values of
+// their attribute, unless empty. The values are read from the keys of the
+// index of the attribute, without loading the txs.
+func (txi *TxIndex) Aggregate(ctx context.Context, q *query.Query, attribute string, h *txindex.Histogram) error {
+	filteredHashes, err := txi.matchQuery(ctx, q)
+	if err != nil {
+		return err
+	}
+
+	heights := make(map[string]int64, len(filteredHashes)) // by hash
+	for _, m := range filteredHashes {
+		hashString := string(m.hash)
+		if _, ok := heights[hashString]; ok {
+			continue
+		}
+		if m.height == 0 {
+			// The key of the match did not hold its position.
+			res, err := txi.Get(m.hash)
+			if err != nil {
+				return fmt.Errorf("failed to get Tx{%X}: %w", m.hash, err)
+			} else if res == nil {
+				continue
+			}
+			m.height = res.Height
+		}
+		heights[hashString] = m.height
+		h.AddTx(m.height)
+	}
+	if attribute == "" || len(heights) == 0 {
+		return nil
+	}
+
+	it, err := dbm.IteratePrefix(txi.store, startKey(attribute))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for ; it.Valid(); it.Next() {
+		if !isTagKey(it.Key()) {
+			continue
+		}
+		height, ok := heights[string(it.Value())]
+		if !ok {
+			continue
+		}
+		// Skip the values of txs with the same hash at other heights.
+		if keyHeight, err := extractHeightFromKey(it.Key()); err != nil || keyHeight != height {
+			continue
+		}
+		if v, ok := new(big.Int).SetString(extractValueFromKey(it.Key()), 10); ok {
+			h.AddValue(height, v)
+		}
+
+		// Potentially exit early.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return it.Error()
+}
+
+// matchQuery returns the txs matching q, by hash (and event sequence if
+// matching events).
+//
+// The query is rewritten as alternatives of conditions (like "tx.height > 5"),
+// each matched separately before their results are merged.
+func (txi *TxIndex) matchQuery(ctx context.Context, q *query.Query) (map[string]txMatch, error) {
+	conjunctions, err := q.Disjunction()
 	if err != nil {
 		return nil, fmt.Errorf("error during parsing conditions from query: %w", err)
 	}
+	if len(conjunctions) == 1 {
+		return txi.matchConjunction(ctx, conjunctions[0])
+	}
+
+	filteredHashes := make(map[string]txMatch)
+	for _, conditions := range conjunctions {
+		matches, err := txi.matchConjunction(ctx, conditions)
+		if err != nil {
+			return nil, err
+		}
+		for _, m := range matches {
+			filteredHashes[string(m.hash)] = m
+		}
+
+		// Potentially exit early.
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return filteredHashes, nil
+}
+
+// matchConjunction returns the txs matching all the given conditions. The txs
+// matching negated conditions are removed from the ones matching the others,
+// or from all the txs if all the conditions are negated.
+func (txi *TxIndex) matchConjunction(ctx context.Context, conditions []query.Condition) (map[string]txMatch, error) {
+	var positive, negated []query.Condition
+	for _, c := range conditions {
+		if c.Negated {
+			c.Negated = false
+			negated = append(negated, c)
+		} else {
+			positive = append(positive, c)
+		}
+	}
+	if len(positive) == 0 || (len(positive) == 1 && positive[0].CompositeKey == types.MatchEventKey) {
+		// match all the txs, by their height
+		positive = append(positive, query.Condition{CompositeKey: types.TxHeightKey, Op: query.OpExists})
+	}
+
+	filteredHashes, err := txi.matchConditions(ctx, positive)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range negated {
+		if len(filteredHashes) == 0 {
+			break
+		}
+		excluded, err := txi.matchConditions(ctx, []query.Condition{c})
+		if err != nil {
+			return nil, err
+		}
+		excludedHashes := make(map[string]struct{}, len(excluded))
+		for _, m := range excluded {
+			excludedHashes[string(m.hash)] = struct{}{}
+		}
+		for k, m := range filteredHashes {
+			if _, ok := excludedHashes[string(m.hash)]; ok {
+				delete(filteredHashes, k)
+			}
+		}
+	}
+	return filteredHashes, nil
+}
+
+// matchConditions returns the txs matching all the given conditions, none of
+// which is negated.
+func (txi *TxIndex) matchConditions(ctx context.Context, conditions []query.Condition) (map[string]txMatch, error) {
+	var hashesInitialized bool
+	filteredHashes := make(map[string]txMatch)
 
 	// if there is a hash condition, return the result immediately
-	hash, ok, err := lookForHash(conditions)
+	hashes, ok, err := lookForHash(conditions)
 	if err != nil {
 		return nil, fmt.Errorf("error during searching for a hash in the query: %w", err)
 	} else if ok {
-		res, err := txi.Get(hash)
-		switch {
-		case err != nil:
-			return []*abci.TxResult{}, fmt.Errorf("error while retrieving the result: %w", err)
-		case res == nil:
-			return []*abci.TxResult{}, nil
-		default:
-			return []*abci.TxResult{res}, nil
+		for _, hash := range hashes {
+			res, err := txi.Get(hash)
+			switch {
+			case err != nil:
+				return nil, fmt.Errorf("error while retrieving the result: %w", err)
+			case res != nil:
+				filteredHashes[string(hash)] = txMatch{hash: hash, height: res.Height, index: res.Index}
+			}
 		}
+		return filteredHashes, nil
 	}
 
 	var matchEvents bool
@@ -358,35 +903,32 @@ func (txi *TxIndex) Search(ctx context.Context, q *query.Query) ([]*abci.TxResul
 		}
 	}
 
-	results := make([]*abci.TxResult, 0, len(filteredHashes))
-	resultMap := make(map[string]struct{})
-	for _, h := range filteredHashes {
-		res, err := txi.Get(h)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get Tx{%X}: %w", h, err)
-		}
-		hashString := string(h)
-		if _, ok := resultMap[hashString]; !ok {
-			resultMap[hashString] = struct{}{}
-			results = append(results, res)
-		}
-		// Potentially exit early.
-		select {
-		case <-ctx.Done():
-			break
-		default:
-		}
-	}
-
-	return results, nil
+	return filteredHashes, nil
 }
 
-func lookForHash(conditions []query.Condition) (hash []byte, ok bool, err error) {
+// lookForHash returns the hashes of the first "tx.hash=X" or
+// "tx.hash IN (X, Y)" condition, if any.
+func lookForHash(conditions []query.Condition) (hashes [][]byte, ok bool, err error) {
 	for _, c := range conditions {
-		if c.CompositeKey == types.TxHashKey {
-			decoded, err := hex.DecodeString(c.Operand.(string))
-			return decoded, true, err
+		if c.CompositeKey != types.TxHashKey {
+			continue
+		}
+		operands := []interface{}{c.Operand}
+		if c.Op == query.OpIn {
+			operands = c.Operand.([]interface{})
 		}
+		for _, operand := range operands {
+			hexHash, isString := operand.(string)
+			if !isString {
+				return nil, true, fmt.Errorf("invalid hash %v", operand)
+			}
+			decoded, err := hex.DecodeString(hexHash)
+			if err != nil {
+				return nil, true, err
+			}
+			hashes = append(hashes, decoded)
+		}
+		return hashes, true, nil
 	}
 	return
 }
@@ -400,12 +942,25 @@ func lookForHeight(conditions []query.Condition) (height int64, heightIdx int) {
 	}
 	return 0, -1
 }
-func (txi *TxIndex) setTmpHashes(tmpHeights map[string][]byte, it dbm.Iterator, matchEvents bool) {
+
+// txMatch is a tx matching a condition, at the position held by the key of
+// the index entry, if any (height is 0 otherwise).
+type txMatch struct {
+	hash   []byte
+	height int64
+	index  uint32
+}
+
+func (txi *TxIndex) setTmpHashes(tmpHeights map[string]txMatch, it dbm.Iterator, matchEvents bool) {
+	m := txMatch{hash: it.Value()}
+	if height, index, err := extractPositionFromKey(it.Key()); err == nil {
+		m.height, m.index = height, index
+	}
 	if matchEvents {
 		eventSeq := extractEventSeqFromKey(it.Key())
-		tmpHeights[string(it.Value())+eventSeq] = it.Value()
+		tmpHeights[string(it.Value())+eventSeq] = m
 	} else {
-		tmpHeights[string(it.Value())] = it.Value()
+		tmpHeights[string(it.Value())] = m
 	}
 }
 
@@ -418,18 +973,18 @@ func (txi *TxIndex) match(
 	ctx context.Context,
 	c query.Condition,
 	startKeyBz []byte,
-	filteredHashes map[string][]byte,
+	filteredHashes map[string]txMatch,
 	firstRun bool,
 	matchEvents bool,
 	heightInfo HeightInfo,
-) map[string][]byte {
+) map[string]txMatch {
 	// A previous match was attempted but resulted in no matches, so we return
 	// no matches (assuming AND operand).
 	if !firstRun && len(filteredHashes) == 0 {
 		return filteredHashes
 	}
 
-	tmpHashes := make(map[string][]byte)
+	tmpHashes := make(map[string]txMatch)
 
 	switch {
 	case c.Op == query.OpEqual:
@@ -454,10 +1009,8 @@ func (txi *TxIndex) match(
 
 			txi.setTmpHashes(tmpHashes, it, matchEvents)
 			// Potentially exit early.
-			select {
-			case <-ctx.Done():
+			if ctx.Err() != nil {
 				break
-			default:
 			}
 		}
 		if err := it.Error(); err != nil {
@@ -484,10 +1037,8 @@ func (txi *TxIndex) match(
 			txi.setTmpHashes(tmpHashes, it, matchEvents)
 
 			// Potentially exit early.
-			select {
-			case <-ctx.Done():
+			if ctx.Err() != nil {
 				break
-			default:
 			}
 		}
 		if err := it.Error(); err != nil {
@@ -520,15 +1071,53 @@ func (txi *TxIndex) match(
 			}
 
 			// Potentially exit early.
-			select {
-			case <-ctx.Done():
+			if ctx.Err() != nil {
 				break
-			default:
 			}
 		}
 		if err := it.Error(); err != nil {
 			panic(err)
 		}
+
+	case c.Op == query.OpStartsWith:
+		// The values starting with the operand are contiguous, but the
+		// startKey ends with a separator after the operand.
+		it, err := dbm.IteratePrefix(txi.store, append(startKey(c.CompositeKey), c.Operand.(string)...))
+		if err != nil {
+			panic(err)
+		}
+		defer it.Close()
+
+		for ; it.Valid(); it.Next() {
+			if !isTagKey(it.Key()) || !strings.HasPrefix(extractValueFromKey(it.Key()), c.Operand.(string)) {
+				continue
+			}
+			if matchEvents {
+				keyHeight, err := extractHeightFromKey(it.Key())
+				if err != nil || !checkHeightConditions(heightInfo, keyHeight) {
+					continue
+				}
+			}
+			txi.setTmpHashes(tmpHashes, it, matchEvents)
+
+			// Potentially exit early.
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		if err := it.Error(); err != nil {
+			panic(err)
+		}
+
+	case c.Op == query.OpIn:
+		// match each operand as an equality condition
+		for _, operand := range c.Operand.([]interface{}) {
+			eq := query.Condition{CompositeKey: c.CompositeKey, Op: query.OpEqual, Operand: operand}
+			for k, m := range txi.match(ctx, eq, startKeyForCondition(eq, heightInfo.height), nil, true, matchEvents, heightInfo) {
+				tmpHashes[k] = m
+			}
+		}
+
 	default:
 		panic("other operators should be handled already")
 	}
@@ -547,8 +1136,8 @@ func (txi *TxIndex) match(
 	// Remove/reduce matches in filteredHashes that were not found in this
 	// match (tmpHashes).
 	for k, v := range filteredHashes {
-		tmpHash := tmpHashes[k]
-		if tmpHash == nil || !bytes.Equal(tmpHash, v) {
+		tmpHash, ok := tmpHashes[k]
+		if !ok || !bytes.Equal(tmpHash.hash, v.hash) {
 			delete(filteredHashes, k)
 
 			// Potentially exit early.
@@ -572,18 +1161,18 @@ func (txi *TxIndex) matchRange(
 	ctx context.Context,
 	qr indexer.QueryRange,
 	startKey []byte,
-	filteredHashes map[string][]byte,
+	filteredHashes map[string]txMatch,
 	firstRun bool,
 	matchEvents bool,
 	heightInfo HeightInfo,
-) map[string][]byte {
+) map[string]txMatch {
 	// A previous match was attempted but resulted in no matches, so we return
 	// no matches (assuming AND operand).
 	if !firstRun && len(filteredHashes) == 0 {
 		return filteredHashes
 	}
 
-	tmpHashes := make(map[string][]byte)
+	tmpHashes := make(map[string]txMatch)
 
 	it, err := dbm.IteratePrefix(txi.store, startKey)
 	if err != nil {
@@ -624,10 +1213,8 @@ LOOP:
 		}
 
 		// Potentially exit early.
-		select {
-		case <-ctx.Done():
+		if ctx.Err() != nil {
 			break
-		default:
 		}
 	}
 	if err := it.Error(); err != nil {
@@ -648,8 +1235,8 @@ LOOP:
 	// Remove/reduce matches in filteredHashes that were not found in this
 	// match (tmpHashes).
 	for k, v := range filteredHashes {
-		tmpHash := tmpHashes[k]
-		if tmpHash == nil || !bytes.Equal(tmpHashes[k], v) {
+		tmpHash, ok := tmpHashes[k]
+		if !ok || !bytes.Equal(tmpHash.hash, v.hash) {
 			delete(filteredHashes, k)
 
 			// Potentially exit early.
@@ -679,6 +1266,25 @@ func extractHeightFromKey(key []byte) (int64, error) {
 	parts := strings.SplitN(string(key), tagKeySeparator, -1)
 	return strconv.ParseInt(parts[len(parts)-2], 10, 64)
 }
+
+// extractPositionFromKey returns the height and index of the tx of an event
+// key.
+func extractPositionFromKey(key []byte) (int64, uint32, error) {
+	parts := strings.Split(string(key), tagKeySeparator)
+	if len(parts) < 4 {
+		return 0, 0, fmt.Errorf("invalid key %q", key)
+	}
+	height, err := strconv.ParseInt(parts[len(parts)-2], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	index, err := strconv.ParseUint(strings.SplitN(parts[len(parts)-1], eventSeqSeparator, 2)[0], 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+	return height, uint32(index), nil
+}
+
 func extractValueFromKey(key []byte) string {
 	keyString := string(key)
 	parts := strings.SplitN(keyString, tagKeySeparator, -1)
@@ -4,9 +4,12 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/gogo/protobuf/proto"
+	"github.com/google/orderedcode"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -15,6 +18,7 @@ import (
 	abci "github.com/tendermint/tendermint/abci/types"
 	"github.com/tendermint/tendermint/libs/pubsub/query"
 	cmtrand "github.com/tendermint/tendermint/libs/rand"
+	"github.com/tendermint/tendermint/state/indexer"
 	"github.com/tendermint/tendermint/state/txindex"
 	"github.com/tendermint/tendermint/types"
 )
@@ -667,6 +671,495 @@ func TestTxSearchMultipleTxs(t *testing.T) {
 	require.Len(t, results, 3)
 }
 
+func TestTxSearchOperators(t *testing.T) {
+	txi := NewTxIndex(db.NewMemDB())
+
+	owners := []string{"Ivan", "Igor", "Vlad"}
+	hashes := make(map[string][]byte)
+	for i, owner := range owners {
+		txResult := txResultWithEvents([]abci.Event{
+			{Type: "account", Attributes: []abci.EventAttribute{
+				{Key: []byte("owner"), Value: []byte(owner), Index: true},
+				{Key: []byte("number"), Value: []byte(fmt.Sprint(i + 1)), Index: true},
+			}},
+		})
+		txResult.Tx = types.Tx(owner + "'s account")
+		txResult.Height = int64(i + 1)
+		require.NoError(t, txi.Index(txResult))
+		hashes[owner] = types.Tx(txResult.Tx).Hash()
+	}
+
+	testCases := []struct {
+		q      string
+		owners []string
+	}{
+		{"account.owner = 'Ivan' OR account.owner = 'Vlad'", []string{"Ivan", "Vlad"}},
+		{"account.owner = 'Ivan' OR account.number > 1", []string{"Ivan", "Igor", "Vlad"}},
+		{"(account.owner = 'Ivan' OR account.owner = 'Vlad') AND account.number > 1", []string{"Vlad"}},
+		{"NOT account.owner = 'Ivan'", []string{"Igor", "Vlad"}},
+		{"account.number >= 1 AND NOT (account.owner = 'Ivan' OR tx.height = 3)", []string{"Igor"}},
+		{"NOT account.owner EXISTS", nil},
+		{"account.owner IN ('Ivan', 'Vlad', 'Pavel')", []string{"Ivan", "Vlad"}},
+		{"account.number IN (2, 4) AND account.owner IN ('Igor')", []string{"Igor"}},
+		{"tx.height IN (1, 3)", []string{"Ivan", "Vlad"}},
+		{fmt.Sprintf("tx.hash IN ('%X', '%X')", hashes["Ivan"], hashes["Igor"]), []string{"Ivan", "Igor"}},
+		{"account.owner STARTS WITH 'I'", []string{"Ivan", "Igor"}},
+		{"account.owner STARTS WITH 'Iva'", []string{"Ivan"}},
+		{"account.owner STARTS WITH 'Ivan '", nil},
+		{"match.events = 1 AND (account.owner STARTS WITH 'I' AND account.number = 2)", []string{"Igor"}},
+		{"match.events = 1 AND (NOT account.owner STARTS WITH 'I')", []string{"Vlad"}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.q, func(t *testing.T) {
+			results, err := txi.Search(context.Background(), query.MustParse(tc.q))
+			require.NoError(t, err)
+
+			owners := make([]string, 0, len(results))
+			for _, res := range results {
+				owners = append(owners, strings.TrimSuffix(string(res.Tx), "'s account"))
+			}
+			assert.ElementsMatch(t, tc.owners, owners)
+
+			results, _, err = txi.SearchPage(context.Background(), query.MustParse(tc.q), indexer.Page{})
+			require.NoError(t, err)
+			owners = owners[:0]
+			for _, res := range results {
+				owners = append(owners, strings.TrimSuffix(string(res.Tx), "'s account"))
+			}
+			assert.ElementsMatch(t, tc.owners, owners)
+		})
+	}
+}
+
+func TestTxSearchPage(t *testing.T) {
+	txi := NewTxIndex(db.NewMemDB())
+
+	// Heights are not in lexicographic order.
+	var positions []indexer.Cursor
+	for _, height := range []int64{9, 10, 1, 11, 2} {
+		for index := uint32(0); index < 2; index++ {
+			txResult := txResultWithEvents([]abci.Event{
+				{Type: "account", Attributes: []abci.EventAttribute{{Key: []byte("owner"), Value: []byte("Ivan"), Index: true}}},
+			})
+			txResult.Tx = types.Tx(fmt.Sprintf("tx %d/%d", height, index))
+			txResult.Height = height
+			txResult.Index = index
+			require.NoError(t, txi.Index(txResult))
+			positions = append(positions, indexer.Cursor{Height: height, Index: index})
+		}
+	}
+	ascending := func(desc bool) []indexer.Cursor {
+		sorted := append([]indexer.Cursor{}, positions...)
+		page := indexer.Page{Desc: desc}
+		sort.Slice(sorted, func(i, j int) bool { return page.Less(sorted[i], sorted[j]) })
+		return sorted
+	}
+
+	for _, q := range []string{"account.owner = 'Ivan'", "match.events = 1 AND account.owner = 'Ivan'"} {
+		for _, desc := range []bool{false, true} {
+			var (
+				got  []indexer.Cursor
+				page = indexer.Page{Limit: 3, Desc: desc}
+			)
+			for {
+				results, next, err := txi.SearchPage(context.Background(), query.MustParse(q), page)
+				require.NoError(t, err)
+				for _, res := range results {
+					got = append(got, indexer.Cursor{Height: res.Height, Index: res.Index})
+				}
+				if next == nil {
+					break
+				}
+				require.Len(t, results, 3)
+				assert.Equal(t, got[len(got)-1], *next)
+				page.After = next
+			}
+			assert.Equal(t, ascending(desc), got, q)
+		}
+	}
+
+	// Pages can also skip results.
+	results, next, err := txi.SearchPage(context.Background(), query.MustParse("account.owner = 'Ivan'"),
+		indexer.Page{Skip: 8, Limit: 3})
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	require.Len(t, results, 2)
+	assert.EqualValues(t, 11, results[0].Height)
+
+	// Only the heights of the query are read.
+	results, next, err = txi.SearchPage(context.Background(), query.MustParse("tx.height > 2 AND tx.height <= 10"),
+		indexer.Page{Desc: true})
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	require.Len(t, results, 4)
+	assert.EqualValues(t, 10, results[0].Height)
+	assert.EqualValues(t, 9, results[3].Height)
+}
+
+func TestTxSearchPageLegacyIndex(t *testing.T) {
+	store := db.NewMemDB()
+	txi := NewTxIndex(store)
+
+	for height := int64(1); height <= 4; height++ {
+		txResult := txResultWithEvents([]abci.Event{
+			{Type: "account", Attributes: []abci.EventAttribute{{Key: []byte("owner"), Value: []byte("Ivan"), Index: true}}},
+		})
+		txResult.Tx = types.Tx(fmt.Sprintf("tx %d", height))
+		txResult.Height = height
+		require.NoError(t, txi.Index(txResult))
+
+		if height == 2 {
+			// Drop the position index, as if the first txs were indexed before it.
+			it, err := db.IteratePrefix(store, []byte(positionKeyPrefix))
+			require.NoError(t, err)
+			keys := [][]byte{positionsFromKey}
+			for ; it.Valid(); it.Next() {
+				keys = append(keys, append([]byte{}, it.Key()...))
+			}
+			require.NoError(t, it.Close())
+			for _, key := range keys {
+				require.NoError(t, store.Delete(key))
+			}
+		}
+	}
+	q := query.MustParse("account.owner = 'Ivan'")
+
+	// The txs not indexed by position are still found.
+	results, _, err := txi.SearchPage(context.Background(), q, indexer.Page{})
+	require.NoError(t, err)
+	assert.Len(t, results, 4)
+
+	// Once they are pruned, the position index is used.
+	_, err = txi.Prune(3)
+	require.NoError(t, err)
+	indexed, err := txi.positionsIndexed()
+	require.NoError(t, err)
+	assert.True(t, indexed)
+	results, _, err = txi.SearchPage(context.Background(), q, indexer.Page{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.EqualValues(t, 3, results[0].Height)
+}
+
+func TestTxAggregate(t *testing.T) {
+	txi := NewTxIndex(db.NewMemDB())
+
+	for height := int64(1); height <= 25; height++ {
+		txResult := txResultWithEvents([]abci.Event{
+			{Type: "transfer", Attributes: []abci.EventAttribute{
+				{Key: []byte("sender"), Value: []byte(fmt.Sprintf("sender%d", height%2)), Index: true},
+				{Key: []byte("amount"), Value: []byte(fmt.Sprintf("%d", height)), Index: true},
+			}},
+			{Type: "transfer", Attributes: []abci.EventAttribute{
+				{Key: []byte("amount"), Value: []byte("1.5"), Index: true},
+			}},
+		})
+		txResult.Tx = types.Tx(fmt.Sprintf("tx %d", height))
+		txResult.Height = height
+		require.NoError(t, txi.Index(txResult))
+	}
+	ctx := context.Background()
+	q := query.MustParse("transfer.sender = 'sender1' AND tx.height <= 20")
+
+	count, err := txi.Count(ctx, q)
+	require.NoError(t, err)
+	assert.Equal(t, 10, count)
+
+	h := txindex.NewHistogram(10)
+	require.NoError(t, txi.Aggregate(ctx, q, "transfer.amount", h))
+	buckets := h.Buckets()
+	require.Len(t, buckets, 2)
+	assert.EqualValues(t, 1, buckets[0].FromHeight)
+	assert.EqualValues(t, 10, buckets[0].ToHeight)
+	assert.Equal(t, 5, buckets[0].Count)
+	// Values that are not integers are skipped.
+	assert.Equal(t, 5, buckets[0].Values)
+	assert.EqualValues(t, 1+3+5+7+9, buckets[0].Sum.Int64())
+	assert.EqualValues(t, 1, buckets[0].Min.Int64())
+	assert.EqualValues(t, 9, buckets[0].Max.Int64())
+	assert.EqualValues(t, 11, buckets[1].FromHeight)
+	assert.EqualValues(t, 11+13+15+17+19, buckets[1].Sum.Int64())
+
+	// Indexers that can't aggregate load the matching txs.
+	var searcher txindex.TxIndexer = struct{ txindex.TxIndexer }{txi}
+	count, err = txindex.Count(ctx, searcher, q)
+	require.NoError(t, err)
+	assert.Equal(t, 10, count)
+	h2 := txindex.NewHistogram(10)
+	require.NoError(t, txindex.Aggregate(ctx, searcher, q, "transfer.amount", h2))
+	assert.Equal(t, buckets, h2.Buckets())
+}
+
+func TestTxIndexEventFilter(t *testing.T) {
+	txi := NewTxIndex(db.NewMemDB(), WithEventFilter(indexer.NewEventFilter(
+		[]string{"transfer.*", "account.owner"}, []string{"transfer.memo"})))
+
+	txResult := txResultWithEvents([]abci.Event{
+		{Type: "account", Attributes: []abci.EventAttribute{
+			{Key: []byte("owner"), Value: []byte("Ivan"), Index: true},
+			{Key: []byte("number"), Value: []byte("1"), Index: true},
+		}},
+		{Type: "transfer", Attributes: []abci.EventAttribute{
+			{Key: []byte("recipient"), Value: []byte("Igor"), Index: true},
+			{Key: []byte("memo"), Value: []byte("rent"), Index: true},
+			{Key: []byte("amount"), Value: []byte("10"), Index: false},
+		}},
+	})
+	require.NoError(t, txi.Index(txResult))
+
+	testCases := map[string]int{
+		"account.owner = 'Ivan'":      1,
+		"transfer.recipient = 'Igor'": 1,
+		"account.number = 1":          0,
+		"transfer.memo = 'rent'":      0,
+		"transfer.amount = 10":        0,
+		"tx.height = 1":               1,
+	}
+	for q, n := range testCases {
+		results, err := txi.Search(context.Background(), query.MustParse(q))
+		require.NoError(t, err)
+		assert.Len(t, results, n, q)
+	}
+}
+
+func TestTxIndexAddressTxs(t *testing.T) {
+	ctx := context.Background()
+	_, _, err := NewTxIndex(db.NewMemDB()).AddressTxs(ctx, "Ivan", indexer.Page{})
+	assert.ErrorIs(t, err, txindex.ErrAddressIndexDisabled)
+
+	txi := NewTxIndex(db.NewMemDB(), WithAddressIndex([]string{"transfer.sender", "transfer.recipient"}))
+	var positions []indexer.Cursor
+	for _, height := range []int64{9, 10, 1, 11, 2} {
+		for index := uint32(0); index < 2; index++ {
+			recipient := "Igor"
+			if index == 1 {
+				// Listed once, although the address appears twice.
+				recipient = "Ivan"
+			}
+			txResult := txResultWithEvents([]abci.Event{
+				{Type: "transfer", Attributes: []abci.EventAttribute{
+					{Key: []byte("sender"), Value: []byte("Ivan"), Index: false},
+					{Key: []byte("recipient"), Value: []byte(recipient), Index: true},
+				}},
+			})
+			txResult.Tx = types.Tx(fmt.Sprintf("tx %d/%d", height, index))
+			txResult.Height = height
+			txResult.Index = index
+			require.NoError(t, txi.Index(txResult))
+			positions = append(positions, indexer.Cursor{Height: height, Index: index})
+		}
+	}
+
+	for _, desc := range []bool{false, true} {
+		sorted := append([]indexer.Cursor{}, positions...)
+		page := indexer.Page{Limit: 3, Desc: desc}
+		sort.Slice(sorted, func(i, j int) bool { return page.Less(sorted[i], sorted[j]) })
+
+		var got []indexer.Cursor
+		for {
+			results, next, err := txi.AddressTxs(ctx, "Ivan", page)
+			require.NoError(t, err)
+			for _, res := range results {
+				got = append(got, indexer.Cursor{Height: res.Height, Index: res.Index})
+			}
+			if next == nil {
+				break
+			}
+			require.Len(t, results, 3)
+			assert.Equal(t, got[len(got)-1], *next)
+			page.After = next
+		}
+		assert.Equal(t, sorted, got)
+	}
+
+	results, next, err := txi.AddressTxs(ctx, "Igor", indexer.Page{Skip: 3, Limit: 2})
+	require.NoError(t, err)
+	assert.Nil(t, next)
+	require.Len(t, results, 2)
+	assert.EqualValues(t, 10, results[0].Height)
+	assert.EqualValues(t, 11, results[1].Height)
+
+	results, _, err = txi.AddressTxs(ctx, "Iv", indexer.Page{})
+	require.NoError(t, err)
+	assert.Empty(t, results)
+
+	// Pruning removes the txs from the address index.
+	_, err = txi.Prune(10)
+	require.NoError(t, err)
+	results, _, err = txi.AddressTxs(ctx, "Igor", indexer.Page{})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	assert.EqualValues(t, 10, results[0].Height)
+	prefix, err := orderedcode.Append(nil, addressKeyPrefix)
+	require.NoError(t, err)
+	it, err := db.IteratePrefix(txi.store, prefix)
+	require.NoError(t, err)
+	defer it.Close()
+	var addressKeys int
+	for ; it.Valid(); it.Next() {
+		addressKeys++
+	}
+	assert.Equal(t, 6, addressKeys)
+}
+
+func TestTxIndexPrune(t *testing.T) {
+	txi := NewTxIndex(db.NewMemDB())
+
+	var hashes [][]byte
+	for height := int64(1); height <= 5; height++ {
+		txResult := txResultWithEvents([]abci.Event{
+			{Type: "account", Attributes: []abci.EventAttribute{{Key: []byte("owner"), Value: []byte("Ivan"), Index: true}}},
+		})
+		txResult.Tx = types.Tx(fmt.Sprintf("tx %d", height))
+		txResult.Height = height
+		require.NoError(t, txi.Index(txResult))
+		hashes = append(hashes, types.Tx(txResult.Tx).Hash())
+	}
+
+	pruned, err := txi.Prune(3)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, pruned)
+
+	for i, hash := range hashes {
+		loaded, err := txi.Get(hash)
+		require.NoError(t, err)
+		if i < 2 {
+			assert.Nil(t, loaded)
+		} else {
+			assert.NotNil(t, loaded)
+		}
+	}
+	for _, q := range []string{"account.owner = 'Ivan'", "tx.height < 4", "match.events = 1 AND account.owner = 'Ivan'"} {
+		results, err := txi.Search(context.Background(), query.MustParse(q))
+		require.NoError(t, err)
+		for _, res := range results {
+			assert.GreaterOrEqual(t, res.Height, int64(3), q)
+		}
+	}
+
+	// The heights already pruned are not visited again.
+	pruned, err = txi.Prune(3)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, pruned)
+	pruned, err = txi.Prune(10)
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, pruned)
+
+	// Pruning starts from the lowest indexed height.
+	txi = NewTxIndex(db.NewMemDB())
+	for height := int64(1_000_000); height < 1_000_003; height++ {
+		txResult := txResultWithEvents(nil)
+		txResult.Tx = types.Tx(fmt.Sprintf("tx %d", height))
+		txResult.Height = height
+		require.NoError(t, txi.Index(txResult))
+	}
+	pruned, err = txi.Prune(1_000_002)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, pruned)
+}
+
+func TestTxIndexRewind(t *testing.T) {
+	txi := NewTxIndex(db.NewMemDB())
+	require.NoError(t, InitiateDatabaseForPods(txi))
+
+	// 5 Ethereum txs are indexed and added to the pods per height.
+	var (
+		hashes [][]byte
+		pod    [][]byte
+		podNum = 1
+	)
+	for height := int64(1); height <= 6; height++ {
+		for i := 0; i < 5; i++ {
+			txResult := txResultWithEvents([]abci.Event{
+				{Type: "message", Attributes: []abci.EventAttribute{
+					{Key: []byte("action"), Value: []byte("/ethermint.evm.v1.MsgEthereumTx"), Index: true},
+				}},
+				{Type: "message", Attributes: []abci.EventAttribute{
+					{Key: []byte("module"), Value: []byte("evm"), Index: true},
+					{Key: []byte("sender"), Value: []byte("0xa"), Index: true},
+				}},
+			})
+			txResult.Tx = types.Tx(fmt.Sprintf("tx %d/%d", height, i))
+			txResult.Height = height
+			txResult.Index = uint32(i)
+			require.NoError(t, txi.Index(txResult))
+			hashes = append(hashes, types.Tx(txResult.Tx).Hash())
+
+			pod = append(pod, txResult.Tx)
+			require.NoError(t, SetPod(txi, podNum, pod))
+			if len(pod) == transactionPodSize {
+				podNum++
+				pod = nil
+				require.NoError(t, IncrementPodCount(txi))
+			}
+		}
+	}
+	require.NoError(t, SetTxCount(txi, 30))
+	require.NoError(t, SetNonce(txi, "0xa", 30))
+
+	requirePods := func(txCount, podCount, lastPodSize int) {
+		count, err := RetrieveTxCount(txi)
+		require.NoError(t, err)
+		assert.Equal(t, txCount, count)
+		count, err = RetrievePodCount(txi)
+		require.NoError(t, err)
+		assert.Equal(t, podCount, count)
+		nonce, err := GetNonce(txi, "0xa")
+		require.NoError(t, err)
+		assert.EqualValues(t, txCount, nonce)
+		for n := 1; n <= podCount+1; n++ {
+			pod, err := GetPod(txi, n)
+			switch {
+			case n < podCount:
+				require.NoError(t, err)
+				assert.Len(t, pod, transactionPodSize)
+			case n == podCount && lastPodSize > 0:
+				require.NoError(t, err)
+				assert.Len(t, pod, lastPodSize)
+			default:
+				assert.Error(t, err, "pod %d", n)
+			}
+		}
+	}
+	requirePods(30, 2, 5)
+
+	removed, err := txi.Rewind(5)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, removed)
+	requirePods(25, 2, 0)
+
+	removed, err = txi.Rewind(4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, removed)
+	requirePods(20, 1, 20)
+
+	for i, hash := range hashes {
+		loaded, err := txi.Get(hash)
+		require.NoError(t, err)
+		if i < 20 {
+			assert.NotNil(t, loaded)
+		} else {
+			assert.Nil(t, loaded)
+		}
+	}
+	results, err := txi.Search(context.Background(), query.MustParse("message.sender = '0xa'"))
+	require.NoError(t, err)
+	assert.Len(t, results, 20)
+
+	// The heights rolled back are indexed again.
+	removed, err = txi.Rewind(4)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, removed)
+	txResult := txResultWithEvents(nil)
+	txResult.Height = 5
+	require.NoError(t, txi.Index(txResult))
+	loaded, err := txi.Get(types.Tx(txResult.Tx).Hash())
+	require.NoError(t, err)
+	assert.NotNil(t, loaded)
+}
+
 func txResultWithEvents(events []abci.Event) *abci.TxResult {
 	tx := types.Tx("HELLO WORLD")
 	return &abci.TxResult{
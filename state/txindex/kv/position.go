@@ -0,0 +1,428 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/google/orderedcode"
+
+	abci "github.com/tendermint/tendermint/abci/types"
+	"github.com/tendermint/tendermint/libs/pubsub/query"
+	"github.com/tendermint/tendermint/state/indexer"
+	"github.com/tendermint/tendermint/types"
+)
+
+// positionKeyPrefix starts the keys of the position index, followed by the
+// height and the index of each tx, such that all the txs are ordered by
+// position.
+const positionKeyPrefix = "position"
+
+// positionsFromKey holds the height from which the txs are indexed by
+// position, or 0 if they all are, i.e. no tx was indexed before the position
+// index.
+var positionsFromKey = []byte("positionsFrom")
+
+// SearchPage performs a search using the given query, like Search, and
+// returns the transactions of page, along with the position of the last one
+// if more follow. The txs are read in the order of the page from the position
+// index, within the heights of the query, and matched against the query one at
+// a time until the page is full, so that the cost of a page depends on its
+// position rather than on the number of matching txs.
+//
+// SearchPage will exit early and return the txs fetched so far, when a message
+// is received on the context chan.
+func (txi *TxIndex) SearchPage(
+	ctx context.Context,
+	q *query.Query,
+	page indexer.Page,
+) ([]*abci.TxResult, *indexer.Cursor, error) {
+	if ctx.Err() != nil {
+		return []*abci.TxResult{}, nil, nil
+	}
+
+	conjunctions, err := q.Disjunction()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error during parsing conditions from query: %w", err)
+	}
+	// As with Search, match.events only applies at the beginning of a query,
+	// where it keeps a single height condition.
+	for i, conditions := range conjunctions {
+		var matchEvents bool
+		if conditions, matchEvents = dedupMatchEvents(conditions); matchEvents {
+			conditions, _ = dedupHeight(conditions)
+		}
+		conjunctions[i] = conditions
+	}
+	indexed, err := txi.positionsIndexed()
+	if err != nil {
+		return nil, nil, err
+	}
+	// The txs of a hash are looked up directly.
+	if !indexed || lookForHashes(conjunctions) {
+		return txi.searchPageAll(ctx, q, page)
+	}
+
+	it, err := txi.positionIterator(page, heightBounds(conjunctions))
+	if err != nil {
+		return nil, nil, err
+	}
+	defer it.Close()
+
+	var (
+		results []*abci.TxResult
+		skip    = page.Skip
+	)
+	for ; it.Valid(); it.Next() {
+		select {
+		case <-ctx.Done():
+			return results, nil, nil
+		default:
+		}
+
+		var (
+			prefix        string
+			height, index int64
+		)
+		if _, err := orderedcode.Parse(string(it.Key()), &prefix, &height, &index); err != nil {
+			return nil, nil, fmt.Errorf("invalid position index key %X: %w", it.Key(), err)
+		}
+		position := indexer.Cursor{Height: height, Index: uint32(index)}
+		if page.After != nil && !page.Less(*page.After, position) {
+			continue
+		}
+
+		res, err := txi.Get(it.Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		// Skip the txs indexed again at another position.
+		if res == nil || res.Height != position.Height || res.Index != position.Index {
+			continue
+		}
+		if !txi.matches(res, it.Value(), conjunctions) {
+			continue
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		if page.Limit > 0 && len(results) == page.Limit {
+			last := results[len(results)-1]
+			return results, &indexer.Cursor{Height: last.Height, Index: last.Index}, nil
+		}
+		results = append(results, res)
+	}
+	return results, nil, it.Error()
+}
+
+// positionIterator returns an iterator over the position index in the order of
+// page, from its position, within the given heights.
+func (txi *TxIndex) positionIterator(page indexer.Page, heights [2]int64) (dbm.Iterator, error) {
+	start, err := positionKey(heights[0], 0)
+	if err != nil {
+		return nil, err
+	}
+	var end []byte
+	if heights[1] < math.MaxInt64 {
+		if end, err = positionKey(heights[1]+1, 0); err != nil {
+			return nil, err
+		}
+	} else if end, err = orderedcode.Append(nil, positionKeyPrefix); err != nil {
+		return nil, err
+	} else {
+		end = prefixEnd(end)
+	}
+
+	if page.After != nil {
+		after, err := positionKey(page.After.Height, page.After.Index)
+		if err != nil {
+			return nil, err
+		}
+		if page.Desc && bytes.Compare(after, end) < 0 {
+			end = after
+		} else if !page.Desc && bytes.Compare(after, start) > 0 {
+			start = after
+		}
+	}
+	if page.Desc {
+		return txi.store.ReverseIterator(start, end)
+	}
+	return txi.store.Iterator(start, end)
+}
+
+// positionsIndexed returns true if all the indexed txs are indexed by
+// position, i.e. the txs indexed before the position index were pruned.
+func (txi *TxIndex) positionsIndexed() (bool, error) {
+	from, ok, err := txi.heightAt(positionsFromKey)
+	if err != nil {
+		return false, err
+	} else if !ok {
+		// Nothing was indexed since, so neither before if the index is empty.
+		it, err := dbm.IteratePrefix(txi.store, startKey(types.TxHeightKey))
+		if err != nil {
+			return false, err
+		}
+		defer it.Close()
+		return !it.Valid(), it.Error()
+	}
+	if from <= 1 {
+		return true, nil
+	}
+	retainHeight, _, err := txi.heightAt(retainHeightKey)
+	return retainHeight >= from, err
+}
+
+// indexPosition adds to b the position key of result. The first time, it also
+// adds the height from which the txs are indexed by position: 0 if no tx was
+// indexed yet, or else the height of result.
+func (txi *TxIndex) indexPosition(result *abci.TxResult, hash []byte, b dbm.Batch) error {
+	key, err := positionKey(result.Height, result.Index)
+	if err != nil {
+		return err
+	}
+	if err := b.Set(key, hash); err != nil {
+		return err
+	}
+
+	if _, ok, err := txi.heightAt(positionsFromKey); err != nil || ok {
+		return err
+	}
+	it, err := dbm.IteratePrefix(txi.store, startKey(types.TxHeightKey))
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	from := result.Height
+	if !it.Valid() {
+		from = 0
+	}
+	return b.Set(positionsFromKey, []byte(strconv.FormatInt(from, 10)))
+}
+
+// heightAt returns the height held by key, if any.
+func (txi *TxIndex) heightAt(key []byte) (int64, bool, error) {
+	bz, err := txi.store.Get(key)
+	if err != nil || bz == nil {
+		return 0, false, err
+	}
+	height, err := strconv.ParseInt(string(bz), 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid height %q at %s: %w", bz, key, err)
+	}
+	return height, true, nil
+}
+
+func positionKey(height int64, index uint32) ([]byte, error) {
+	return orderedcode.Append(nil, positionKeyPrefix, height, int64(index))
+}
+
+// matches returns true if the tx of res, with the given hash, matches any of
+// the conjunctions of conditions, like the index would: against the attributes
+// of its events indexed, along with its height and hash. As with the index,
+// the range conditions on an attribute must all match a single integer value.
+// With match.events, the conditions on attributes must match within a single
+// event, except the negated ones, which exclude the txs matching them in any
+// event.
+func (txi *TxIndex) matches(res *abci.TxResult, hash []byte, conjunctions [][]query.Condition) bool {
+	var (
+		height = strconv.FormatInt(res.Height, 10)
+		all    = map[string][]string{types.TxHeightKey: {height}}
+		events []map[string][]string
+	)
+	for _, event := range res.Result.Events {
+		if len(event.Type) == 0 {
+			continue
+		}
+		attrs := make(map[string][]string)
+		for _, attr := range event.Attributes {
+			compositeKey := fmt.Sprintf("%s.%s", event.Type, attr.Key)
+			if len(attr.Key) == 0 || !attr.GetIndex() || !txi.filter.Allows(compositeKey) {
+				continue
+			}
+			attrs[compositeKey] = append(attrs[compositeKey], string(attr.Value))
+			all[compositeKey] = append(all[compositeKey], string(attr.Value))
+		}
+		events = append(events, attrs)
+	}
+
+	for _, conditions := range conjunctions {
+		if conjunctionMatches(conditions, hash, all, events) {
+			return true
+		}
+	}
+	return false
+}
+
+// conjunctionMatches returns true if the tx with the given hash, attributes
+// and attributes per event matches all the conditions.
+func conjunctionMatches(conditions []query.Condition, hash []byte, all map[string][]string,
+	events []map[string][]string) bool {
+	var (
+		matchEvents bool
+		attrConds   []query.Condition
+	)
+	for _, c := range conditions {
+		switch {
+		case c.CompositeKey == types.MatchEventKey:
+			matchEvents = true
+		case c.Negated:
+			if conditionMatches(c, hash, all) {
+				return false
+			}
+		case c.CompositeKey == types.TxHashKey || c.CompositeKey == types.TxHeightKey:
+			if !conditionMatches(c, hash, all) {
+				return false
+			}
+		default:
+			attrConds = append(attrConds, c)
+		}
+	}
+	if len(attrConds) == 0 {
+		return true
+	}
+
+	ranges, rangeIndexes, _ := indexer.LookForRangesWithHeight(attrConds)
+	attrsMatch := func(attrs map[string][]string) bool {
+		for i, c := range attrConds {
+			if !intInSlice(i, rangeIndexes) && !conditionMatches(c, hash, attrs) {
+				return false
+			}
+		}
+		for _, qr := range ranges {
+			if !rangeMatches(qr, attrs[qr.Key]) {
+				return false
+			}
+		}
+		return true
+	}
+	if !matchEvents {
+		return attrsMatch(all)
+	}
+	for _, attrs := range events {
+		if attrsMatch(attrs) {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionMatches returns true if c, ignoring Negated, matches the tx with
+// the given hash and indexed attributes. The values which can't be compared
+// to the operand of c don't match it.
+func conditionMatches(c query.Condition, hash []byte, attrs map[string][]string) bool {
+	if c.CompositeKey == types.TxHashKey {
+		if c.Op == query.OpExists {
+			return true
+		}
+		hashes, _, err := lookForHash([]query.Condition{c})
+		if err != nil {
+			return false
+		}
+		for _, h := range hashes {
+			if bytes.Equal(h, hash) {
+				return true
+			}
+		}
+		return false
+	}
+
+	values, ok := attrs[c.CompositeKey]
+	switch {
+	case c.Op == query.OpExists:
+		return ok
+	case indexer.IsRangeOperation(c.Op):
+		ranges, _, _ := indexer.LookForRangesWithHeight([]query.Condition{c})
+		return rangeMatches(ranges[c.CompositeKey], values)
+	}
+	for _, value := range values {
+		if match, err := c.MatchesValue(value); err == nil && match {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeMatches returns true if any of the values is an integer within qr.
+func rangeMatches(qr indexer.QueryRange, values []string) bool {
+	if _, ok := qr.AnyBound().(*big.Int); !ok {
+		return false
+	}
+	for _, value := range values {
+		if v, ok := new(big.Int).SetString(value, 10); ok && checkBounds(qr, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// lookForHashes returns true if every conjunction looks for the txs of a hash.
+func lookForHashes(conjunctions [][]query.Condition) bool {
+	for _, conditions := range conjunctions {
+		var positive []query.Condition
+		for _, c := range conditions {
+			if !c.Negated {
+				positive = append(positive, c)
+			}
+		}
+		if _, ok, _ := lookForHash(positive); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// heightBounds returns the lowest and highest heights of the txs matching any
+// of the conjunctions, per their tx.height conditions.
+func heightBounds(conjunctions [][]query.Condition) [2]int64 {
+	bounds := [2]int64{math.MaxInt64, 1}
+	for _, conditions := range conjunctions {
+		b := [2]int64{1, math.MaxInt64}
+		for _, c := range conditions {
+			if c.CompositeKey != types.TxHeightKey || c.Negated {
+				continue
+			}
+			operand, ok := c.Operand.(*big.Int)
+			if !ok || !operand.IsInt64() {
+				continue
+			}
+			h := operand.Int64()
+			switch c.Op {
+			case query.OpEqual:
+				b[0], b[1] = max64(b[0], h), min64(b[1], h)
+			case query.OpGreater:
+				b[0] = max64(b[0], h+1)
+			case query.OpGreaterEqual:
+				b[0] = max64(b[0], h)
+			case query.OpLess:
+				b[1] = min64(b[1], h-1)
+			case query.OpLessEqual:
+				b[1] = min64(b[1], h)
+			}
+		}
+		bounds[0], bounds[1] = min64(bounds[0], b[0]), max64(bounds[1], b[1])
+	}
+	if bounds[0] > bounds[1] {
+		// no tx can match
+		bounds[0] = bounds[1] + 1
+	}
+	return bounds
+}
+
+func min64(a, b int64) int64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func max64(a, b int64) int64 {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -207,3 +207,101 @@ func serializeEthTransaction(ethTx tracksTypes.EthTransaction) ([]byte, error) {
 	return txByte, nil
 
 }
+
+// RewindPods removes the Ethereum transactions of results, the last ones
+// added to the pods, as when rolling back the heights of results. The count
+// of transactions, the pods and the nonces of the senders are restored to
+// their values before the transactions were added.
+func RewindPods(txi *TxIndex, results []*abci.TxResult) error {
+	byteRes, err := txi.store.Get([]byte(CounterTxsKey))
+	if err != nil || byteRes == nil {
+		// No pods were stored.
+		return err
+	}
+
+	var senders []string
+	for _, result := range results {
+		if sender, ok := ethTxSender(result); ok {
+			senders = append(senders, sender)
+		}
+	}
+	if len(senders) == 0 {
+		return nil
+	}
+
+	txCount, err := RetrieveTxCount(txi)
+	if err != nil {
+		return err
+	}
+	podCount, err := RetrievePodCount(txi)
+	if err != nil {
+		return err
+	}
+	if len(senders) > txCount {
+		return fmt.Errorf("cannot remove %d transactions out of %d", len(senders), txCount)
+	}
+
+	// The pods are filled in order, the current one being stored once it
+	// holds a transaction.
+	txCount -= len(senders)
+	newPodCount := txCount/transactionPodSize + 1
+	for n := newPodCount + 1; n <= podCount; n++ {
+		if err := txi.store.Delete([]byte(fmt.Sprintf("%s%d", rawPodPrefix, n))); err != nil {
+			return err
+		}
+	}
+	if remaining := txCount % transactionPodSize; remaining == 0 {
+		err = txi.store.Delete([]byte(fmt.Sprintf("%s%d", rawPodPrefix, newPodCount)))
+	} else {
+		var pod [][]byte
+		if pod, err = GetPod(txi, newPodCount); err != nil {
+			return err
+		}
+		if len(pod) < remaining {
+			return fmt.Errorf("pod %d holds %d transactions instead of at least %d", newPodCount, len(pod), remaining)
+		}
+		err = SetPod(txi, newPodCount, pod[:remaining])
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, sender := range senders {
+		nonce, err := GetNonce(txi, sender)
+		if err != nil {
+			return err
+		}
+		if nonce > 0 {
+			if err := SetNonce(txi, sender, nonce-1); err != nil {
+				return err
+			}
+		}
+	}
+	if err := txi.store.Set([]byte(CounterPodsKey), []byte(strconv.Itoa(newPodCount))); err != nil {
+		return err
+	}
+	return SetTxCount(txi, txCount)
+}
+
+// ethTxSender returns the sender of the Ethereum transaction of result, and
+// false if result is not an Ethereum transaction, as identified by StorePod.
+func ethTxSender(result *abci.TxResult) (string, bool) {
+	var (
+		isEthTx bool
+		sender  string
+	)
+	for _, event := range result.Result.Events {
+		if event.Type != "message" {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			if string(attr.Key) == "action" && string(attr.Value) == "/ethermint.evm.v1.MsgEthereumTx" {
+				isEthTx = true
+			}
+		}
+		if extractAttribute(event.Attributes, "module") == "evm" {
+			sender = extractAttribute(event.Attributes, "sender")
+		}
+	}
+	return sender, isEthTx
+}
@@ -0,0 +1,158 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+
+	dbm "github.com/cometbft/cometbft-db"
+)
+
+// migrateFlushInterval is the number of blocks moved between the syncs of
+// the database, and of the segments.
+const migrateFlushInterval = 1000
+
+// MigrateToSegments moves the parts of the stored blocks from the database to
+// the segments of the block store, calling progress, if not nil, after each
+// block. It returns the number of blocks moved, the blocks already stored in
+// the segments being skipped. The node must be stopped.
+func (bs *BlockStore) MigrateToSegments(progress func(height int64)) (uint64, error) {
+	if bs.segments == nil {
+		return 0, errors.New("the block store has no segments")
+	}
+	// The parts are appended without syncing the segments, which are synced
+	// before the batch moving the blocks is written: the parts are deleted
+	// from the database only once they are durable in the segments.
+	return bs.migrate(progress, migrateFlushInterval, bs.segments.sync, func(batch dbm.Batch, height int64, total int) (bool, error) {
+		if bs.loadBlockPartsLocation(height) != nil {
+			return false, nil
+		}
+
+		parts := make([][]byte, total)
+		for i := range parts {
+			bz, err := bs.db.Get(calcBlockPartKey(height, i))
+			if err != nil {
+				return false, err
+			}
+			if len(bz) == 0 {
+				return false, fmt.Errorf("part %d of block %d is missing", i, height)
+			}
+			parts[i] = bz
+		}
+		loc, err := bs.segments.append(parts, false)
+		if err != nil {
+			return false, err
+		}
+		if err := bs.indexBlockParts(batch, height, loc); err != nil {
+			return false, err
+		}
+		for i := range parts {
+			if err := batch.Delete(calcBlockPartKey(height, i)); err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+	})
+}
+
+// MigrateFromSegments moves the parts of the stored blocks from the segments
+// of the block store back to the database, and removes the segments, calling
+// progress, if not nil, after each block. It returns the number of blocks
+// moved. The node must be stopped.
+func (bs *BlockStore) MigrateFromSegments(progress func(height int64)) (uint64, error) {
+	if bs.segments == nil {
+		return 0, errors.New("the block store has no segments")
+	}
+	// The segments are removed only once all the blocks are moved and synced,
+	// so each block is written on its own, bounding the size of the batches.
+	moved, err := bs.migrate(progress, 1, nil, func(batch dbm.Batch, height int64, total int) (bool, error) {
+		loc := bs.loadBlockPartsLocation(height)
+		if loc == nil {
+			return false, nil
+		}
+		if len(loc.sizes) != total {
+			return false, fmt.Errorf("%d parts stored for block %d instead of %d", len(loc.sizes), height, total)
+		}
+
+		for i := 0; i < total; i++ {
+			part, err := bs.segments.read(*loc, i)
+			if err != nil {
+				return false, fmt.Errorf("reading part %d of block %d: %w", i, height, err)
+			}
+			if err := batch.Set(calcBlockPartKey(height, i), part); err != nil {
+				return false, err
+			}
+		}
+		return true, batch.Delete(calcBlockPartsKey(height))
+	})
+	if err != nil {
+		return moved, err
+	}
+
+	// All the blocks were moved; the head is emptied rather than removed.
+	ids, err := bs.segments.ids()
+	if err != nil {
+		return moved, err
+	}
+	for _, id := range ids {
+		if err := bs.segments.remove(id); err != nil {
+			return moved, err
+		}
+		if err := bs.db.Delete(calcSegmentKey(id)); err != nil {
+			return moved, err
+		}
+	}
+	return moved, bs.segments.truncateHead()
+}
+
+// migrate calls move with a batch for each stored block, from the base to the
+// height of the store, move returning false if the block was skipped. The
+// batch is written every batchBlocks blocks moved, and synced every
+// migrateFlushInterval blocks moved and at the end, after calling flush if not
+// nil.
+func (bs *BlockStore) migrate(
+	progress func(height int64),
+	batchBlocks uint64,
+	flush func() error,
+	move func(batch dbm.Batch, height int64, total int) (bool, error),
+) (uint64, error) {
+	var moved uint64
+	batch := bs.db.NewBatch()
+	defer func() { batch.Close() }()
+
+	write := func(sync bool) error {
+		if !sync {
+			return batch.Write()
+		}
+		if flush != nil {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+		return batch.WriteSync()
+	}
+
+	for height := bs.Base(); height > 0 && height <= bs.Height(); height++ {
+		meta := bs.LoadBlockMeta(height)
+		if meta == nil {
+			continue
+		}
+		ok, err := move(batch, height, int(meta.BlockID.PartSetHeader.Total))
+		if err != nil {
+			return moved, fmt.Errorf("failed to move block %d: %w", height, err)
+		}
+		if ok {
+			moved++
+			if moved%batchBlocks == 0 {
+				if err := write(moved%migrateFlushInterval == 0); err != nil {
+					return moved, err
+				}
+				batch.Close()
+				batch = bs.db.NewBatch()
+			}
+		}
+		if progress != nil {
+			progress(height)
+		}
+	}
+	return moved, write(true)
+}
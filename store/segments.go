@@ -0,0 +1,335 @@
+package store
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	cmtsync "github.com/tendermint/tendermint/libs/sync"
+)
+
+// DefaultMaxSegmentSize is the size above which a new segment is started.
+const DefaultMaxSegmentSize = 512 * 1024 * 1024
+
+const segmentExt = ".seg"
+
+// errSegmentRemoved is returned when reading a segment which was removed,
+// along with the blocks it contained.
+var errSegmentRemoved = errors.New("segment removed")
+
+/*
+Segments stores the parts of the blocks in append-only files, rather than in
+the database of the block store, where they would be rewritten by the
+compactions of the database. The parts of each block are appended to the last
+segment, or head, which is replaced by a new one once it exceeds the maximum
+size. The location of the parts in the segments is indexed in the database.
+
+The segments are removed once all their blocks are pruned.
+*/
+type Segments struct {
+	dir     string
+	maxSize int64
+
+	mtx      cmtsync.Mutex
+	head     *os.File
+	headID   int64
+	headSize int64
+	readers  map[int64]*os.File
+}
+
+// partsLocation is the location of the parts of a block, appended one after
+// the other to a segment.
+type partsLocation struct {
+	segment   int64
+	offset    int64
+	sizes     []uint32
+	checksums []uint32
+}
+
+// OpenSegments opens the segments of dir, creating the directory if needed.
+// New segments are started once the head exceeds maxSize bytes.
+func OpenSegments(dir string, maxSize int64) (*Segments, error) {
+	if maxSize <= 0 {
+		return nil, errors.New("the maximum segment size must be positive")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+
+	s := &Segments{dir: dir, maxSize: maxSize, readers: make(map[int64]*os.File)}
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+	headID := int64(1)
+	if len(ids) > 0 {
+		headID = ids[len(ids)-1]
+	}
+	if err := s.openHead(headID); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Dir returns the directory of the segments.
+func (s *Segments) Dir() string {
+	return s.dir
+}
+
+// Close closes the files of the segments.
+func (s *Segments) Close() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var errs []string
+	for id, f := range s.readers {
+		if err := f.Close(); err != nil {
+			errs = append(errs, err.Error())
+		}
+		delete(s.readers, id)
+	}
+	if err := s.head.Close(); err != nil {
+		errs = append(errs, err.Error())
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("closing segments: %s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// append appends parts to the head, syncing it if sync is true, before
+// returning their location. Without sync, the parts are durable once sync is
+// called, or once the head is replaced by a new segment.
+func (s *Segments) append(parts [][]byte, sync bool) (partsLocation, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var (
+		size int64
+		loc  = partsLocation{
+			sizes:     make([]uint32, len(parts)),
+			checksums: make([]uint32, len(parts)),
+		}
+	)
+	for i, part := range parts {
+		size += int64(len(part))
+		loc.sizes[i] = uint32(len(part))
+		loc.checksums[i] = crc32.ChecksumIEEE(part)
+	}
+	if s.headSize > 0 && s.headSize+size > s.maxSize {
+		if err := s.head.Sync(); err != nil {
+			return partsLocation{}, fmt.Errorf("syncing segment %d: %w", s.headID, err)
+		}
+		if err := s.head.Close(); err != nil {
+			return partsLocation{}, err
+		}
+		if err := s.openHead(s.headID + 1); err != nil {
+			return partsLocation{}, err
+		}
+	}
+
+	buf := make([]byte, 0, size)
+	for _, part := range parts {
+		buf = append(buf, part...)
+	}
+	n, err := s.head.Write(buf)
+	// The bytes written before a failure are left unused.
+	s.headSize += int64(n)
+	if err != nil {
+		return partsLocation{}, fmt.Errorf("appending to segment %d: %w", s.headID, err)
+	}
+	if sync {
+		if err := s.head.Sync(); err != nil {
+			return partsLocation{}, fmt.Errorf("syncing segment %d: %w", s.headID, err)
+		}
+	}
+
+	loc.segment = s.headID
+	loc.offset = s.headSize - size
+	return loc, nil
+}
+
+// sync syncs the head, making the parts appended without sync durable.
+func (s *Segments) sync() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if err := s.head.Sync(); err != nil {
+		return fmt.Errorf("syncing segment %d: %w", s.headID, err)
+	}
+	return nil
+}
+
+// read returns the part at index in loc.
+func (s *Segments) read(loc partsLocation, index int) ([]byte, error) {
+	if index < 0 || index >= len(loc.sizes) {
+		return nil, fmt.Errorf("part %d out of %d", index, len(loc.sizes))
+	}
+	offset := loc.offset
+	for _, size := range loc.sizes[:index] {
+		offset += int64(size)
+	}
+
+	f, err := s.reader(loc.segment)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, errSegmentRemoved
+	} else if err != nil {
+		return nil, err
+	}
+	part := make([]byte, loc.sizes[index])
+	if _, err := f.ReadAt(part, offset); errors.Is(err, os.ErrClosed) {
+		return nil, errSegmentRemoved
+	} else if err != nil {
+		return nil, fmt.Errorf("reading segment %d at %d: %w", loc.segment, offset, err)
+	}
+	if crc32.ChecksumIEEE(part) != loc.checksums[index] {
+		return nil, fmt.Errorf("corrupted part in segment %d at %d", loc.segment, offset)
+	}
+	return part, nil
+}
+
+// remove removes the segment id, unless it is the head.
+func (s *Segments) remove(id int64) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if id == s.headID {
+		return nil
+	}
+	if f, ok := s.readers[id]; ok {
+		delete(s.readers, id)
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	err := os.Remove(s.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// truncateHead removes the content of the head.
+func (s *Segments) truncateHead() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if f, ok := s.readers[s.headID]; ok {
+		delete(s.readers, s.headID)
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	if err := s.head.Truncate(0); err != nil {
+		return err
+	}
+	s.headSize = 0
+	return nil
+}
+
+// ids returns the IDs of the segments of the directory, in ascending order.
+func (s *Segments) ids() ([]int64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, segmentExt) {
+			continue
+		}
+		id, err := strconv.ParseInt(strings.TrimSuffix(name, segmentExt), 10, 64)
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids, nil
+}
+
+func (s *Segments) openHead(id int64) error {
+	f, err := os.OpenFile(s.path(id), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	s.head, s.headID, s.headSize = f, id, info.Size()
+	return nil
+}
+
+// reader returns the file of segment id, opened for reading.
+func (s *Segments) reader(id int64) (*os.File, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if f, ok := s.readers[id]; ok {
+		return f, nil
+	}
+	f, err := os.Open(s.path(id))
+	if err != nil {
+		return nil, err
+	}
+	s.readers[id] = f
+	return f, nil
+}
+
+func (s *Segments) path(id int64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%016d%s", id, segmentExt))
+}
+
+// encode encodes loc as a sequence of varints.
+func (loc partsLocation) encode() []byte {
+	bz := make([]byte, 0, 3*binary.MaxVarintLen64+2*len(loc.sizes)*binary.MaxVarintLen32)
+	bz = binary.AppendUvarint(bz, uint64(loc.segment))
+	bz = binary.AppendUvarint(bz, uint64(loc.offset))
+	bz = binary.AppendUvarint(bz, uint64(len(loc.sizes)))
+	for i := range loc.sizes {
+		bz = binary.AppendUvarint(bz, uint64(loc.sizes[i]))
+		bz = binary.AppendUvarint(bz, uint64(loc.checksums[i]))
+	}
+	return bz
+}
+
+func decodePartsLocation(bz []byte) (partsLocation, error) {
+	var (
+		loc partsLocation
+		err error
+	)
+	next := func() uint64 {
+		v, n := binary.Uvarint(bz)
+		if n <= 0 {
+			err = errors.New("invalid parts location")
+			return 0
+		}
+		bz = bz[n:]
+		return v
+	}
+	loc.segment = int64(next())
+	loc.offset = int64(next())
+	total := next()
+	if err != nil || total > uint64(len(bz)) {
+		return partsLocation{}, errors.New("invalid parts location")
+	}
+	loc.sizes = make([]uint32, total)
+	loc.checksums = make([]uint32, total)
+	for i := range loc.sizes {
+		loc.sizes[i] = uint32(next())
+		loc.checksums[i] = uint32(next())
+	}
+	if err != nil || len(bz) > 0 {
+		return partsLocation{}, errors.New("invalid parts location")
+	}
+	return loc, nil
+}
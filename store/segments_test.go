@@ -0,0 +1,158 @@
+package store
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	dbm "github.com/cometbft/cometbft-db"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tendermint/tendermint/libs/log"
+	sm "github.com/tendermint/tendermint/state"
+	"github.com/tendermint/tendermint/types"
+	cmttime "github.com/tendermint/tendermint/types/time"
+)
+
+func saveTestBlocks(t *testing.T, bs *BlockStore, state sm.State, from, to int64) map[int64]*types.Block {
+	t.Helper()
+	blocks := make(map[int64]*types.Block)
+	for h := from; h <= to; h++ {
+		block := makeBlock(h, state, new(types.Commit))
+		bs.SaveBlock(block, block.MakePartSet(256), makeTestCommit(h, cmttime.Now()))
+		blocks[h] = block
+	}
+	return blocks
+}
+
+func requireBlocks(t *testing.T, bs *BlockStore, blocks map[int64]*types.Block) {
+	t.Helper()
+	for h, block := range blocks {
+		loaded := bs.LoadBlock(h)
+		require.NotNil(t, loaded, "block %d", h)
+		require.Equal(t, block.Hash(), loaded.Hash(), "block %d", h)
+	}
+}
+
+func TestSegmentsSaveLoadPrune(t *testing.T) {
+	state, _, cleanup := makeStateAndBlockStore(log.NewNopLogger())
+	defer cleanup()
+
+	dir := t.TempDir()
+	segments, err := OpenSegments(dir, 4096)
+	require.NoError(t, err)
+	db := dbm.NewMemDB()
+	bs := NewBlockStore(db, WithSegments(segments))
+
+	blocks := saveTestBlocks(t, bs, state, 1, 100)
+	requireBlocks(t, bs, blocks)
+	require.NotNil(t, bs.LoadBlockPart(50, 1))
+	require.Nil(t, bs.LoadBlockPart(50, 100))
+
+	// The parts are not stored in the database.
+	has, err := db.Has(calcBlockPartKey(50, 0))
+	require.NoError(t, err)
+	require.False(t, has)
+
+	ids, err := segments.ids()
+	require.NoError(t, err)
+	require.Greater(t, len(ids), 2)
+
+	// The segments are read again once reopened.
+	require.NoError(t, segments.Close())
+	segments, err = OpenSegments(dir, 4096)
+	require.NoError(t, err)
+	bs = NewBlockStore(db, WithSegments(segments))
+	requireBlocks(t, bs, blocks)
+	blocks[101] = saveTestBlocks(t, bs, state, 101, 101)[101]
+
+	pruned, err := bs.PruneBlocks(60)
+	require.NoError(t, err)
+	assert.EqualValues(t, 59, pruned)
+	for h := int64(1); h < 60; h++ {
+		require.Nil(t, bs.LoadBlock(h))
+		delete(blocks, h)
+	}
+	requireBlocks(t, bs, blocks)
+
+	// Only the segments of the blocks below 60 were removed.
+	remaining, err := segments.ids()
+	require.NoError(t, err)
+	require.Less(t, len(remaining), len(ids))
+	for _, id := range remaining {
+		height := bs.loadSegmentHeight(id)
+		require.True(t, height == 0 || height >= 60, "segment %d of height %d", id, height)
+	}
+	require.NoError(t, bs.Close())
+}
+
+func TestMigrateSegments(t *testing.T) {
+	state, _, cleanup := makeStateAndBlockStore(log.NewNopLogger())
+	defer cleanup()
+
+	db := dbm.NewMemDB()
+	blocks := saveTestBlocks(t, NewBlockStore(db), state, 1, 20)
+
+	// The blocks stored in the database are still read once the segments
+	// are enabled.
+	dir := t.TempDir()
+	segments, err := OpenSegments(dir, 4096)
+	require.NoError(t, err)
+	bs := NewBlockStore(db, WithSegments(segments))
+	for h, block := range saveTestBlocks(t, bs, state, 21, 30) {
+		blocks[h] = block
+	}
+	requireBlocks(t, bs, blocks)
+
+	// The blocks stored in segments can't be read without them.
+	assert.Panics(t, func() { NewBlockStore(db).LoadBlock(25) })
+
+	var heights []int64
+	moved, err := bs.MigrateToSegments(func(height int64) { heights = append(heights, height) })
+	require.NoError(t, err)
+	assert.EqualValues(t, 20, moved)
+	assert.Len(t, heights, 30)
+	requireBlocks(t, bs, blocks)
+	has, err := db.Has(calcBlockPartKey(10, 0))
+	require.NoError(t, err)
+	require.False(t, has)
+
+	moved, err = bs.MigrateFromSegments(nil)
+	require.NoError(t, err)
+	assert.EqualValues(t, 30, moved)
+	require.NoError(t, segments.Close())
+
+	ids, err := segments.ids()
+	require.NoError(t, err)
+	require.Len(t, ids, 1)
+	info, err := os.Stat(segments.path(ids[0]))
+	require.NoError(t, err)
+	require.Zero(t, info.Size())
+
+	requireBlocks(t, NewBlockStore(db), blocks)
+}
+
+func TestMigrateSegmentsFlush(t *testing.T) {
+	state, _, cleanup := makeStateAndBlockStore(log.NewNopLogger())
+	defer cleanup()
+
+	db := dbm.NewMemDB()
+	blocks := saveTestBlocks(t, NewBlockStore(db), state, 1, 10)
+	segments, err := OpenSegments(t.TempDir(), 4096)
+	require.NoError(t, err)
+	defer segments.Close()
+	bs := NewBlockStore(db, WithSegments(segments))
+
+	// Nothing is written to the database until the segments are flushed.
+	var flushes int
+	_, err = bs.migrate(nil, migrateFlushInterval, func() error {
+		flushes++
+		return errors.New("flush failed")
+	}, func(batch dbm.Batch, height int64, total int) (bool, error) {
+		return true, batch.Delete(calcBlockPartKey(height, 0))
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, flushes)
+	requireBlocks(t, bs, blocks)
+}
@@ -1,6 +1,7 @@
 package store
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 
@@ -41,17 +42,36 @@ type BlockStore struct {
 	mtx    cmtsync.RWMutex
 	base   int64
 	height int64
+
+	// The segments storing the block parts, if not stored in the database.
+	segments *Segments
+}
+
+// Option sets an optional parameter on the BlockStore.
+type Option func(*BlockStore)
+
+// WithSegments stores the parts of the new blocks in segments, rather than in
+// the database. The parts of the blocks saved before are still read from the
+// database, until they are moved with MigrateToSegments.
+func WithSegments(segments *Segments) Option {
+	return func(bs *BlockStore) {
+		bs.segments = segments
+	}
 }
 
 // NewBlockStore returns a new BlockStore with the given DB,
 // initialized to the last height that was committed to the DB.
-func NewBlockStore(db dbm.DB) *BlockStore {
-	bs := LoadBlockStoreState(db)
-	return &BlockStore{
-		base:   bs.Base,
-		height: bs.Height,
+func NewBlockStore(db dbm.DB, options ...Option) *BlockStore {
+	bss := LoadBlockStoreState(db)
+	bs := &BlockStore{
+		base:   bss.Base,
+		height: bss.Height,
 		db:     db,
 	}
+	for _, option := range options {
+		option(bs)
+	}
+	return bs
 }
 
 // Base returns the first known contiguous block height, or 0 for empty block stores.
@@ -149,15 +169,12 @@ func (bs *BlockStore) LoadBlockByHash(hash []byte) *types.Block {
 func (bs *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
 	var pbpart = new(cmtproto.Part)
 
-	bz, err := bs.db.Get(calcBlockPartKey(height, index))
-	if err != nil {
-		panic(err)
-	}
+	bz := bs.loadBlockPartBytes(height, index)
 	if len(bz) == 0 {
 		return nil
 	}
 
-	err = proto.Unmarshal(bz, pbpart)
+	err := proto.Unmarshal(bz, pbpart)
 	if err != nil {
 		panic(fmt.Errorf("unmarshal to cmtproto.Part failed: %w", err))
 	}
@@ -169,6 +186,53 @@ func (bs *BlockStore) LoadBlockPart(height int64, index int) *types.Part {
 	return part
 }
 
+// loadBlockPartBytes returns the encoded part at the given index from the
+// block at the given height, from the segments if enabled and indexed there,
+// or from the database.
+func (bs *BlockStore) loadBlockPartBytes(height int64, index int) []byte {
+	if bs.segments != nil {
+		if loc := bs.loadBlockPartsLocation(height); loc != nil {
+			if index < 0 || index >= len(loc.sizes) {
+				return nil
+			}
+			part, err := bs.segments.read(*loc, index)
+			if errors.Is(err, errSegmentRemoved) {
+				// The block was pruned since its location was loaded.
+				return nil
+			} else if err != nil {
+				panic(err)
+			}
+			return part
+		}
+	}
+
+	bz, err := bs.db.Get(calcBlockPartKey(height, index))
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 && bs.segments == nil && bs.loadBlockPartsLocation(height) != nil {
+		panic(fmt.Sprintf("the parts of block %d are stored in segments, which are not enabled", height))
+	}
+	return bz
+}
+
+// loadBlockPartsLocation returns the location of the parts of the block at
+// the given height in the segments, or nil if they are not stored there.
+func (bs *BlockStore) loadBlockPartsLocation(height int64) *partsLocation {
+	bz, err := bs.db.Get(calcBlockPartsKey(height))
+	if err != nil {
+		panic(err)
+	}
+	if len(bz) == 0 {
+		return nil
+	}
+	loc, err := decodePartsLocation(bz)
+	if err != nil {
+		panic(fmt.Errorf("error reading block parts location: %w", err))
+	}
+	return &loc
+}
+
 // LoadBlockMeta returns the BlockMeta for the given height.
 // If no block is found for the given height, it returns nil.
 func (bs *BlockStore) LoadBlockMeta(height int64) *types.BlockMeta {
@@ -297,6 +361,9 @@ func (bs *BlockStore) PruneBlocks(height int64) (uint64, error) {
 		if err := batch.Delete(calcSeenCommitKey(h)); err != nil {
 			return 0, err
 		}
+		if err := batch.Delete(calcBlockPartsKey(h)); err != nil {
+			return 0, err
+		}
 		for p := 0; p < int(meta.BlockID.PartSetHeader.Total); p++ {
 			if err := batch.Delete(calcBlockPartKey(h, p)); err != nil {
 				return 0, err
@@ -319,9 +386,94 @@ func (bs *BlockStore) PruneBlocks(height int64) (uint64, error) {
 	if err != nil {
 		return 0, err
 	}
+	if bs.segments != nil {
+		if err := bs.pruneSegments(height); err != nil {
+			return 0, err
+		}
+	}
 	return pruned, nil
 }
 
+// pruneSegments removes the segments whose blocks are all below height.
+func (bs *BlockStore) pruneSegments(height int64) error {
+	ids, err := bs.segments.ids()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		if bs.loadSegmentHeight(id) >= height {
+			continue
+		}
+		if err := bs.segments.remove(id); err != nil {
+			return fmt.Errorf("failed to remove segment %d: %w", id, err)
+		}
+		if err := bs.db.Delete(calcSegmentKey(id)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteBlocksAbove removes the blocks above height, down to the base, as when
+// rolling back the state to height. It returns the number of blocks deleted.
+// The parts of the blocks stored in segments are left there, unused.
+func (bs *BlockStore) DeleteBlocksAbove(height int64) (uint64, error) {
+	bs.mtx.RLock()
+	base, last := bs.base, bs.height
+	bs.mtx.RUnlock()
+	if height < base {
+		return 0, fmt.Errorf("cannot delete the blocks above height %v, it is lower than base height %v",
+			height, base)
+	}
+	if height >= last {
+		return 0, nil
+	}
+
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+	var deleted uint64
+	for h := last; h > height; h-- {
+		meta := bs.LoadBlockMeta(h)
+		if meta == nil { // assume already deleted
+			continue
+		}
+		if err := batch.Delete(calcBlockMetaKey(h)); err != nil {
+			return 0, err
+		}
+		if err := batch.Delete(calcBlockHashKey(meta.BlockID.Hash)); err != nil {
+			return 0, err
+		}
+		// The commit of the previous block is stored along with each block.
+		if err := batch.Delete(calcBlockCommitKey(h - 1)); err != nil {
+			return 0, err
+		}
+		if err := batch.Delete(calcSeenCommitKey(h)); err != nil {
+			return 0, err
+		}
+		if err := batch.Delete(calcBlockPartsKey(h)); err != nil {
+			return 0, err
+		}
+		for p := 0; p < int(meta.BlockID.PartSetHeader.Total); p++ {
+			if err := batch.Delete(calcBlockPartKey(h, p)); err != nil {
+				return 0, err
+			}
+		}
+		deleted++
+	}
+
+	// As when pruning, the height is lowered first, for no one to access the
+	// deleted blocks.
+	bs.mtx.Lock()
+	bs.height = height
+	bs.mtx.Unlock()
+	bs.saveState()
+
+	if err := batch.WriteSync(); err != nil {
+		return 0, fmt.Errorf("failed to delete the blocks above height %v: %w", height, err)
+	}
+	return deleted, nil
+}
+
 // SaveBlock persists the given block, blockParts, and seenCommit to the underlying db.
 // blockParts: Must be parts of the block
 // seenCommit: The +2/3 precommits that were seen which committed at height.
@@ -348,10 +500,7 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	// typically load the block meta first as an indication that the block exists
 	// and then go on to load block parts - we must make sure the block is
 	// complete as soon as the block meta is written.
-	for i := 0; i < int(blockParts.Total()); i++ {
-		part := blockParts.GetPart(i)
-		bs.saveBlockPart(height, i, part)
-	}
+	bs.saveBlockParts(height, blockParts)
 
 	// Save block meta
 	blockMeta := types.NewBlockMeta(block, blockParts)
@@ -394,15 +543,68 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	bs.saveState()
 }
 
-func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
-	pbp, err := part.ToProto()
+// saveBlockParts saves the parts of the block at height in the segments, if
+// enabled, or in the database.
+func (bs *BlockStore) saveBlockParts(height int64, blockParts *types.PartSet) {
+	parts := make([][]byte, blockParts.Total())
+	for i := range parts {
+		pbp, err := blockParts.GetPart(i).ToProto()
+		if err != nil {
+			panic(fmt.Errorf("unable to make part into proto: %w", err))
+		}
+		parts[i] = mustEncode(pbp)
+	}
+
+	if bs.segments == nil {
+		for i, partBytes := range parts {
+			if err := bs.db.Set(calcBlockPartKey(height, i), partBytes); err != nil {
+				panic(err)
+			}
+		}
+		return
+	}
+
+	loc, err := bs.segments.append(parts, true)
 	if err != nil {
-		panic(fmt.Errorf("unable to make part into proto: %w", err))
+		panic(err)
+	}
+	batch := bs.db.NewBatch()
+	defer batch.Close()
+	if err := bs.indexBlockParts(batch, height, loc); err != nil {
+		panic(err)
+	}
+	if err := batch.Write(); err != nil {
+		panic(err)
+	}
+}
+
+// indexBlockParts adds to batch the location of the parts of the block at
+// height, and the height to the ones of their segment.
+func (bs *BlockStore) indexBlockParts(batch dbm.Batch, height int64, loc partsLocation) error {
+	if err := batch.Set(calcBlockPartsKey(height), loc.encode()); err != nil {
+		return err
 	}
-	partBytes := mustEncode(pbp)
-	if err := bs.db.Set(calcBlockPartKey(height, index), partBytes); err != nil {
+	if height > bs.loadSegmentHeight(loc.segment) {
+		return batch.Set(calcSegmentKey(loc.segment), []byte(fmt.Sprintf("%d", height)))
+	}
+	return nil
+}
+
+// loadSegmentHeight returns the last height of the blocks in a segment, or 0
+// if unknown.
+func (bs *BlockStore) loadSegmentHeight(id int64) int64 {
+	bz, err := bs.db.Get(calcSegmentKey(id))
+	if err != nil {
 		panic(err)
 	}
+	if len(bz) == 0 {
+		return 0
+	}
+	height, err := strconv.ParseInt(string(bz), 10, 64)
+	if err != nil {
+		panic(fmt.Sprintf("failed to extract height from %s: %v", bz, err))
+	}
+	return height
 }
 
 func (bs *BlockStore) saveState() {
@@ -426,6 +628,11 @@ func (bs *BlockStore) SaveSeenCommit(height int64, seenCommit *types.Commit) err
 }
 
 func (bs *BlockStore) Close() error {
+	if bs.segments != nil {
+		if err := bs.segments.Close(); err != nil {
+			return err
+		}
+	}
 	return bs.db.Close()
 }
 
@@ -451,6 +658,14 @@ func calcBlockHashKey(hash []byte) []byte {
 	return []byte(fmt.Sprintf("BH:%x", hash))
 }
 
+func calcBlockPartsKey(height int64) []byte {
+	return []byte(fmt.Sprintf("PS:%v", height))
+}
+
+func calcSegmentKey(id int64) []byte {
+	return []byte(fmt.Sprintf("SG:%v", id))
+}
+
 //-----------------------------------------------------------------------------
 
 var blockStoreKey = []byte("blockStore")
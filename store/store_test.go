@@ -515,6 +515,57 @@ func TestPruneBlocks(t *testing.T) {
 	assert.Nil(t, bs.LoadBlock(1501))
 }
 
+func TestDeleteBlocksAbove(t *testing.T) {
+	state, _, cleanup := makeStateAndBlockStore(log.NewNopLogger())
+	defer cleanup()
+
+	for _, withSegments := range []bool{false, true} {
+		db := dbm.NewMemDB()
+		var options []Option
+		if withSegments {
+			segments, err := OpenSegments(t.TempDir(), 4096)
+			require.NoError(t, err)
+			options = append(options, WithSegments(segments))
+		}
+		bs := NewBlockStore(db, options...)
+		blocks := saveTestBlocks(t, bs, state, 1, 20)
+		_, err := bs.PruneBlocks(5)
+		require.NoError(t, err)
+
+		_, err = bs.DeleteBlocksAbove(4)
+		require.Error(t, err, "below the base")
+		deleted, err := bs.DeleteBlocksAbove(20)
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, deleted)
+
+		deleted, err = bs.DeleteBlocksAbove(10)
+		require.NoError(t, err)
+		assert.EqualValues(t, 10, deleted)
+		assert.EqualValues(t, 5, bs.Base())
+		assert.EqualValues(t, 10, bs.Height())
+		assert.EqualValues(t, cmtstore.BlockStoreState{Base: 5, Height: 10}, LoadBlockStoreState(db))
+		for h := int64(11); h <= 20; h++ {
+			assert.Nil(t, bs.LoadBlockMeta(h))
+			assert.Nil(t, bs.LoadBlock(h))
+			assert.Nil(t, bs.LoadBlockByHash(blocks[h].Hash()))
+			assert.Nil(t, bs.LoadSeenCommit(h))
+			delete(blocks, h)
+		}
+		// The commit of the last block is only stored with the next one.
+		assert.Nil(t, bs.LoadBlockCommit(10))
+		assert.NotNil(t, bs.LoadSeenCommit(10))
+		for h := int64(1); h < 5; h++ {
+			delete(blocks, h)
+		}
+		requireBlocks(t, bs, blocks)
+
+		// The blocks above are saved again.
+		requireBlocks(t, bs, saveTestBlocks(t, bs, state, 11, 15))
+		assert.EqualValues(t, 15, bs.Height())
+		require.NoError(t, bs.Close())
+	}
+}
+
 func TestLoadBlockMeta(t *testing.T) {
 	bs, db := freshBlockStore()
 	height := int64(10)